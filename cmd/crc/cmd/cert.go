@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	crcErrors "github.com/code-ready/crc/pkg/crc/errors"
+	"github.com/code-ready/crc/pkg/crc/machine"
+	"github.com/spf13/cobra"
+)
+
+// certificates expiring in less than this are reported before they expire
+const certExpiringSoon = 7 * 24 * time.Hour
+
+func init() {
+	addOutputFormatFlag(certStatusCmd)
+	certCmd.AddCommand(certStatusCmd)
+	certCmd.AddCommand(certRenewCmd)
+	rootCmd.AddCommand(certCmd)
+}
+
+var certCmd = &cobra.Command{
+	Use:   "cert",
+	Short: "Inspect and renew the certificates of the OpenShift cluster",
+	Long:  "Inspect and renew the certificates of the running OpenShift cluster",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var certStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Display the expiry dates of the cluster certificates",
+	Long:  "Display the expiry dates of the kubelet, aggregator client CA, API server and ingress certificates of the running OpenShift cluster",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCertStatus(cmd.Context(), os.Stdout, newMachine(), outputFormat, time.Now())
+	},
+}
+
+var certRenewCmd = &cobra.Command{
+	Use:   "renew",
+	Short: "Renew the kubelet certificates and the aggregator client CA of the running cluster",
+	Long: `Force the renewal of the kubelet client and serving certificates and of the aggregator client CA of the running
+OpenShift cluster, instead of waiting for them to be renewed during 'crc start' once they expired. This can take up to
+16 minutes. The API server and ingress certificates are not renewed, they are rotated by the cluster operators.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCertRenew(cmd.Context(), os.Stdout, newMachine())
+	},
+}
+
+func runCertRenew(ctx context.Context, writer io.Writer, client machine.Client) error {
+	if err := checkIfMachineMissing(ctx, client); err != nil {
+		return err
+	}
+	if err := client.RenewCertificates(ctx); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(writer, "The kubelet certificates and the aggregator client CA were renewed")
+	return err
+}
+
+type certStatusResult struct {
+	Success      bool                         `json:"success"`
+	Error        *crcErrors.SerializableError `json:"error,omitempty"`
+	Certificates []certificate                `json:"certificates,omitempty"`
+}
+
+type certificate struct {
+	Name      string `json:"name"`
+	Location  string `json:"location"`
+	ExpiresAt string `json:"expiresAt"`
+	Status    string `json:"status"`
+}
+
+func runCertStatus(ctx context.Context, writer io.Writer, client machine.Client, outputFormat string, now time.Time) error {
+	return render(getCertStatus(ctx, client, now), writer, outputFormat)
+}
+
+func getCertStatus(ctx context.Context, client machine.Client, now time.Time) *certStatusResult {
+	if err := checkIfMachineMissing(ctx, client); err != nil {
+		return &certStatusResult{Success: false, Error: crcErrors.ToSerializableError(err)}
+	}
+	certs, err := client.Certificates(ctx)
+	if err != nil {
+		return &certStatusResult{Success: false, Error: crcErrors.ToSerializableError(err)}
+	}
+	result := &certStatusResult{Success: true}
+	for _, cert := range certs {
+		result.Certificates = append(result.Certificates, certificate{
+			Name:      cert.Name,
+			Location:  cert.Location,
+			ExpiresAt: cert.ExpiresAt.Format(time.RFC3339),
+			Status:    certStatus(cert.ExpiresAt, now),
+		})
+	}
+	return result
+}
+
+func certStatus(expiresAt, now time.Time) string {
+	switch {
+	case !now.Before(expiresAt):
+		return "Expired"
+	case expiresAt.Sub(now) < certExpiringSoon:
+		return "Expiring soon"
+	default:
+		return "Valid"
+	}
+}
+
+func (s *certStatusResult) prettyPrintTo(writer io.Writer) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	w := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "CERTIFICATE\tEXPIRES\tSTATUS"); err != nil {
+		return err
+	}
+	for _, cert := range s.Certificates {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", cert.Name, cert.ExpiresAt, cert.Status); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/code-ready/crc/pkg/crc/machine/fakemachine"
+	"github.com/stretchr/testify/assert"
+)
+
+var certTestNow = time.Date(2021, 2, 25, 10, 0, 0, 0, time.UTC)
+
+func TestPlainCertStatus(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runCertStatus(context.Background(), out, fakemachine.NewClient(), "", certTestNow))
+	assert.Equal(t, `CERTIFICATE           EXPIRES               STATUS
+Kubelet client        2021-03-01T10:00:00Z  Expiring soon
+Aggregator client CA  2099-03-01T10:00:00Z  Valid
+`, out.String())
+}
+
+func TestJsonCertStatus(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runCertStatus(context.Background(), out, fakemachine.NewClient(), jsonFormat, certTestNow.AddDate(0, 1, 0)))
+	assert.JSONEq(t, `{
+  "success": true,
+  "certificates": [
+    {
+      "name": "Kubelet client",
+      "location": "/var/lib/kubelet/pki/kubelet-client-current.pem",
+      "expiresAt": "2021-03-01T10:00:00Z",
+      "status": "Expired"
+    },
+    {
+      "name": "Aggregator client CA",
+      "location": "/etc/kubernetes/static-pod-resources/kube-apiserver-certs/configmaps/aggregator-client-ca/ca-bundle.crt",
+      "expiresAt": "2099-03-01T10:00:00Z",
+      "status": "Valid"
+    }
+  ]
+}`, out.String())
+}
+
+func TestJsonCertStatusWithError(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runCertStatus(context.Background(), out, fakemachine.NewFailingClient(), jsonFormat, certTestNow))
+	assert.JSONEq(t, `{"success": false, "error": "broken"}`, out.String())
+}
+
+func TestCertRenew(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runCertRenew(context.Background(), out, fakemachine.NewClient()))
+	assert.Equal(t, "The kubelet certificates and the aggregator client CA were renewed\n", out.String())
+
+	out.Reset()
+	assert.EqualError(t, runCertRenew(context.Background(), out, fakemachine.NewFailingClient()), "renewal failed")
+	assert.Empty(t, out.String())
+}
@@ -10,25 +10,42 @@ import (
 	"github.com/code-ready/crc/pkg/crc/config"
 	"github.com/code-ready/crc/pkg/crc/constants"
 	"github.com/code-ready/crc/pkg/crc/network"
+	"github.com/spf13/cast"
 	"github.com/spf13/cobra"
 )
 
 const (
-	Bundle                  = "bundle"
-	CPUs                    = "cpus"
-	Memory                  = "memory"
-	DiskSize                = "disk-size"
-	NameServer              = "nameserver"
-	PullSecretFile          = "pull-secret-file"
-	DisableUpdateCheck      = "disable-update-check"
-	ExperimentalFeatures    = "enable-experimental-features"
-	NetworkMode             = "network-mode"
-	HTTPProxy               = "http-proxy"
-	HTTPSProxy              = "https-proxy"
-	NoProxy                 = "no-proxy"
-	ProxyCAFile             = "proxy-ca-file"
-	ConsentTelemetry        = "consent-telemetry"
-	EnableClusterMonitoring = "enable-cluster-monitoring"
+	Bundle                   = "bundle"
+	CPUs                     = "cpus"
+	Memory                   = "memory"
+	DiskSize                 = "disk-size"
+	NameServer               = "nameserver"
+	PullSecretFile           = "pull-secret-file"
+	DisableUpdateCheck       = "disable-update-check"
+	ExperimentalFeatures     = "enable-experimental-features"
+	NetworkMode              = "network-mode"
+	NetworkSubnet            = "network-subnet"
+	NetworkGatewayIP         = "network-gateway-ip"
+	NetworkHostIP            = "network-host-ip"
+	NetworkMTU               = "network-mtu"
+	NetworkGatewayMAC        = "network-gateway-mac"
+	NetworkForwardedPorts    = "network-forwarded-ports"
+	HTTPProxy                = "http-proxy"
+	HTTPSProxy               = "https-proxy"
+	NoProxy                  = "no-proxy"
+	ProxyCAFile              = "proxy-ca-file"
+	AdditionalTrustedCAFiles = "additional-trusted-ca-files"
+	TrustedCARegistries      = "additional-trusted-ca-registries"
+	RegistryMirrors          = "registry-mirrors"
+	InsecureRegistries       = "insecure-registries"
+	ConsentTelemetry         = "consent-telemetry"
+	EnableClusterMonitoring  = "enable-cluster-monitoring"
+	Wait                     = "wait"
+	WaitTimeout              = "wait-timeout"
+	WaitOperators            = "wait-operators"
+	StopTimeout              = "stop-timeout"
+	AutoStopIdleMinutes      = "auto-stop-idle-minutes"
+	AutoStopGraceMinutes     = "auto-stop-grace-minutes"
 )
 
 func RegisterSettings(cfg *config.Config) {
@@ -42,18 +59,81 @@ func RegisterSettings(cfg *config.Config) {
 	cfg.AddSetting(DisableUpdateCheck, false, config.ValidateBool, config.SuccessfullyApplied)
 	cfg.AddSetting(ExperimentalFeatures, false, config.ValidateBool, config.SuccessfullyApplied)
 	cfg.AddSetting(NetworkMode, string(network.DefaultMode), network.ValidateMode, network.SuccessfullyAppliedMode)
+	// Virtual network of the daemon, only used with the vsock network mode
+	cfg.AddSetting(NetworkSubnet, network.DefaultVirtualSubnet, network.ValidateVirtualSubnet, network.SuccessfullyAppliedVirtualNetwork)
+	cfg.AddSetting(NetworkGatewayIP, network.DefaultVirtualGatewayIP, config.ValidateIPAddress, network.SuccessfullyAppliedVirtualNetwork)
+	cfg.AddSetting(NetworkHostIP, network.DefaultVirtualHostIP, config.ValidateIPAddress, network.SuccessfullyAppliedVirtualNetwork)
+	cfg.AddSetting(NetworkMTU, network.DefaultVirtualMTU, network.ValidateVirtualMTU, network.SuccessfullyAppliedVirtualNetwork)
+	cfg.AddSetting(NetworkGatewayMAC, network.DefaultVirtualGatewayMAC, network.ValidateVirtualGatewayMAC, network.SuccessfullyAppliedVirtualNetwork)
+	// Comma separated list of port or hostPort:vmPort entries
+	cfg.AddSetting(NetworkForwardedPorts, network.DefaultVirtualForwardedPorts, network.ValidateVirtualForwardedPorts, network.SuccessfullyAppliedVirtualNetwork)
 	// Proxy Configuration
 	cfg.AddSetting(HTTPProxy, "", config.ValidateURI, config.SuccessfullyApplied)
 	cfg.AddSetting(HTTPSProxy, "", config.ValidateURI, config.SuccessfullyApplied)
 	cfg.AddSetting(NoProxy, "", config.ValidateNoProxy, config.SuccessfullyApplied)
 	cfg.AddSetting(ProxyCAFile, "", config.ValidatePath, config.SuccessfullyApplied)
+	// Comma separated list of CA files trusted by the node and the cluster
+	cfg.AddSetting(AdditionalTrustedCAFiles, "", config.ValidatePathList, config.SuccessfullyApplied)
+	// Comma separated list of registry hosts whose images are imported by the cluster with these CAs
+	cfg.AddSetting(TrustedCARegistries, "", network.ValidateRegistryHosts, config.SuccessfullyApplied)
+	// Registries Configuration, comma separated source=mirror entries and registry names
+	cfg.AddSetting(RegistryMirrors, "", network.ValidateRegistryMirrors, config.SuccessfullyApplied)
+	cfg.AddSetting(InsecureRegistries, "", network.ValidateInsecureRegistries, config.SuccessfullyApplied)
 
 	cfg.AddSetting(EnableClusterMonitoring, false, config.ValidateBool, config.SuccessfullyApplied)
 
+	// Cluster readiness at the end of start
+	cfg.AddSetting(Wait, constants.DefaultWait, config.ValidateWaitCondition, config.SuccessfullyApplied)
+	cfg.AddSetting(WaitTimeout, constants.DefaultWaitTimeout, config.ValidateTimeout, config.SuccessfullyApplied)
+	cfg.AddSetting(WaitOperators, "", config.ValidateClusterOperators, config.SuccessfullyApplied)
+
+	// Time given to the VM to shut down gracefully before powering it off
+	cfg.AddSetting(StopTimeout, constants.DefaultStopTimeout, config.ValidateTimeout, config.SuccessfullyApplied)
+	// Minutes without traffic with the VM before the daemon stops it, 0 disables the auto-stop.
+	// Only with the vsock network mode
+	cfg.AddSetting(AutoStopIdleMinutes, 0, config.ValidateMinutes, autoStopApplied(cfg))
+	// Minutes between the auto-stop event and the stop, the stop is cancelled if the cluster is used again
+	cfg.AddSetting(AutoStopGraceMinutes, constants.DefaultAutoStopGraceMinutes, config.ValidateMinutes, config.SuccessfullyApplied)
+
 	// Telemeter Configuration
 	cfg.AddSetting(ConsentTelemetry, "", config.ValidateYesNo, config.SuccessfullyApplied)
 }
 
+// autoStopApplied warns that the idle cluster is not stopped when the
+// traffic with the VM cannot be measured
+func autoStopApplied(cfg config.Storage) config.SetFn {
+	return func(key string, value interface{}) string {
+		if cast.ToInt(value) > 0 && network.ParseMode(cfg.Get(NetworkMode).AsString()) != network.VSockMode {
+			return fmt.Sprintf("%s\nThe idle cluster is only stopped with the %s network mode, set '%s' to '%s' to enable it.",
+				config.SuccessfullyApplied(key, value), network.VSockMode, NetworkMode, network.VSockMode)
+		}
+		return config.SuccessfullyApplied(key, value)
+	}
+}
+
+// VirtualNetwork returns the settings of the virtual network used with the vsock network mode
+func VirtualNetwork(cfg config.Storage) network.VirtualNetwork {
+	return network.VirtualNetwork{
+		Subnet:         cfg.Get(NetworkSubnet).AsString(),
+		GatewayIP:      cfg.Get(NetworkGatewayIP).AsString(),
+		HostIP:         cfg.Get(NetworkHostIP).AsString(),
+		MTU:            cfg.Get(NetworkMTU).AsInt(),
+		GatewayMAC:     cfg.Get(NetworkGatewayMAC).AsString(),
+		ForwardedPorts: cfg.Get(NetworkForwardedPorts).AsString(),
+	}
+}
+
+// TrustedCAFiles returns the CA files of the additional-trusted-ca-files setting
+func TrustedCAFiles(cfg config.Storage) []string {
+	var files []string
+	for _, file := range strings.Split(cfg.Get(AdditionalTrustedCAFiles).AsString(), ",") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
 func isPreflightKey(key string) bool {
 	return strings.HasPrefix(key, "skip-")
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -32,22 +33,22 @@ var consoleCmd = &cobra.Command{
 	Short:   "Open the OpenShift Web Console in the default browser",
 	Long:    `Open the OpenShift Web Console in the default browser or print its URL or credentials`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runConsole(os.Stdout, newMachine(), consolePrintURL, consolePrintCredentials, outputFormat)
+		return runConsole(cmd.Context(), os.Stdout, newMachine(), consolePrintURL, consolePrintCredentials, outputFormat)
 	},
 }
 
-func showConsole(client machine.Client) (*machine.ConsoleResult, error) {
-	if err := checkIfMachineMissing(client); err != nil {
+func showConsole(ctx context.Context, client machine.Client) (*machine.ConsoleResult, error) {
+	if err := checkIfMachineMissing(ctx, client); err != nil {
 		// In case of machine doesn't exist then consoleResult error
 		// should be updated so that when rendering the result it have
 		// error details also.
 		return nil, err
 	}
-	return client.GetConsoleURL()
+	return client.GetConsoleURL(ctx)
 }
 
-func runConsole(writer io.Writer, client machine.Client, consolePrintURL, consolePrintCredentials bool, outputFormat string) error {
-	result, err := showConsole(client)
+func runConsole(ctx context.Context, writer io.Writer, client machine.Client, consolePrintURL, consolePrintCredentials bool, outputFormat string) error {
+	result, err := showConsole(ctx, client)
 	return render(&consoleResult{
 		Success:                 err == nil,
 		state:                   toState(result),
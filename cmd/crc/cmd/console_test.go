@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"testing"
 
@@ -11,13 +12,13 @@ import (
 
 func TestConsolePlainSuccess(t *testing.T) {
 	out := new(bytes.Buffer)
-	assert.NoError(t, runConsole(out, fakemachine.NewClient(), true, false, ""))
+	assert.NoError(t, runConsole(context.Background(), out, fakemachine.NewClient(), true, false, ""))
 	assert.Equal(t, fmt.Sprintf("%s\n", fakemachine.DummyClusterConfig.WebConsoleURL), out.String())
 }
 
 func TestConsolePlainError(t *testing.T) {
 	out := new(bytes.Buffer)
-	assert.EqualError(t, runConsole(out, fakemachine.NewFailingClient(), true, false, ""), "console failed")
+	assert.EqualError(t, runConsole(context.Background(), out, fakemachine.NewFailingClient(), true, false, ""), "console failed")
 }
 
 func TestConsoleWithPrintCredentialsPlainSuccess(t *testing.T) {
@@ -25,7 +26,7 @@ func TestConsoleWithPrintCredentialsPlainSuccess(t *testing.T) {
 To login as an admin, run 'oc login -u kubeadmin -p %s %s'
 `, fakemachine.DummyClusterConfig.ClusterAPI, fakemachine.DummyClusterConfig.KubeAdminPass, fakemachine.DummyClusterConfig.ClusterAPI)
 	out := new(bytes.Buffer)
-	assert.NoError(t, runConsole(out, fakemachine.NewClient(), false, true, ""))
+	assert.NoError(t, runConsole(context.Background(), out, fakemachine.NewClient(), false, true, ""))
 	assert.Equal(t, expectedOut, out.String())
 }
 
@@ -35,7 +36,7 @@ To login as a regular user, run 'oc login -u developer -p developer %s'.
 To login as an admin, run 'oc login -u kubeadmin -p %s %s'
 `, fakemachine.DummyClusterConfig.WebConsoleURL, fakemachine.DummyClusterConfig.ClusterAPI, fakemachine.DummyClusterConfig.KubeAdminPass, fakemachine.DummyClusterConfig.ClusterAPI)
 	out := new(bytes.Buffer)
-	assert.NoError(t, runConsole(out, fakemachine.NewClient(), true, true, ""))
+	assert.NoError(t, runConsole(context.Background(), out, fakemachine.NewClient(), true, true, ""))
 	assert.Equal(t, expectedOut, out.String())
 }
 
@@ -57,12 +58,12 @@ func TestConsoleJSONSuccess(t *testing.T) {
   }
 }`, fakemachine.DummyClusterConfig.ClusterCACert, fakemachine.DummyClusterConfig.WebConsoleURL, fakemachine.DummyClusterConfig.ClusterAPI, fakemachine.DummyClusterConfig.KubeAdminPass)
 	out := new(bytes.Buffer)
-	assert.NoError(t, runConsole(out, fakemachine.NewClient(), false, false, jsonFormat))
+	assert.NoError(t, runConsole(context.Background(), out, fakemachine.NewClient(), false, false, jsonFormat))
 	assert.JSONEq(t, expectedJSONOut, out.String())
 }
 
 func TestConsoleJSONError(t *testing.T) {
 	out := new(bytes.Buffer)
-	assert.NoError(t, runConsole(out, fakemachine.NewFailingClient(), false, false, jsonFormat))
+	assert.NoError(t, runConsole(context.Background(), out, fakemachine.NewFailingClient(), false, false, jsonFormat))
 	assert.JSONEq(t, `{"error":"console failed", "success":false}`, out.String())
 }
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/code-ready/crc/pkg/crc/machine"
+	crcos "github.com/code-ready/crc/pkg/os"
+	"github.com/spf13/cobra"
+)
+
+const vmPathPrefix = "crc:"
+
+func init() {
+	rootCmd.AddCommand(cpCmd)
+}
+
+var cpCmd = &cobra.Command{
+	Use:   "cp SOURCE DESTINATION",
+	Short: "Copy files and directories between the host and the CodeReady Containers VM",
+	Long: `Copy files and directories between the host and the CodeReady Containers VM.
+Paths in the VM are prefixed with 'crc:', for example:
+  crc cp ./ca.pem crc:/etc/pki/ca-trust/source/anchors/
+  crc cp crc:/var/log/containers ./logs
+Directories are copied recursively and permissions are kept. The files copied to the VM are owned by root.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCp(cmd.Context(), newMachine(), args[0], args[1], crcos.RunningInTerminal())
+	},
+}
+
+func runCp(ctx context.Context, client machine.Client, src, dest string, showProgress bool) error {
+	srcVMPath, srcInVM := vmPath(src)
+	destVMPath, destInVM := vmPath(dest)
+	if srcInVM == destInVM {
+		return fmt.Errorf("Exactly one of the paths must be in the VM, with the '%s' prefix", vmPathPrefix)
+	}
+	if srcVMPath == "" || destVMPath == "" {
+		return errors.New("Empty path in the VM")
+	}
+
+	sshRunner, err := newSSHRunner(ctx, client)
+	if err != nil {
+		return err
+	}
+	defer sshRunner.Close()
+
+	if srcInVM {
+		return sshRunner.CopyFromVM(srcVMPath, dest, showProgress)
+	}
+	return sshRunner.CopyToVM(src, destVMPath, showProgress)
+}
+
+// vmPath returns the path without its prefix and true when path is in the VM
+func vmPath(path string) (string, bool) {
+	if strings.HasPrefix(path, vmPathPrefix) {
+		return strings.TrimPrefix(path, vmPathPrefix), true
+	}
+	return path, false
+}
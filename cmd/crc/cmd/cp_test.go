@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/code-ready/crc/pkg/crc/machine/fakemachine"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCpNeedsExactlyOneVMPath(t *testing.T) {
+	assert.Error(t, runCp(context.Background(), fakemachine.NewClient(), "ca.pem", "/tmp/ca.pem", false))
+	assert.Error(t, runCp(context.Background(), fakemachine.NewClient(), "crc:/etc/hosts", "crc:/tmp/hosts", false))
+	assert.Error(t, runCp(context.Background(), fakemachine.NewClient(), "ca.pem", "crc:", false))
+}
+
+func TestCpVMNotRunning(t *testing.T) {
+	assert.EqualError(t, runCp(context.Background(), fakemachine.NewFailingClient(), "ca.pem", "crc:/tmp", false), "VM is not running")
+}
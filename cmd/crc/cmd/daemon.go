@@ -7,13 +7,17 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
-	"regexp"
 	"runtime"
 	"syscall"
 	"time"
 
+	cmdConfig "github.com/code-ready/crc/cmd/crc/cmd/config"
 	"github.com/code-ready/crc/pkg/crc/api"
 	"github.com/code-ready/crc/pkg/crc/constants"
+	"github.com/code-ready/crc/pkg/crc/machine/bundle"
+	"github.com/code-ready/crc/pkg/crc/network"
+	"github.com/code-ready/crc/pkg/crc/profile"
+	"github.com/code-ready/crc/pkg/crc/systemd"
 	"github.com/code-ready/gvisor-tap-vsock/pkg/transport"
 	"github.com/code-ready/gvisor-tap-vsock/pkg/types"
 	"github.com/code-ready/gvisor-tap-vsock/pkg/virtualnetwork"
@@ -23,76 +27,52 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var daemonMaxRequestSize int64
+
 func init() {
+	daemonCmd.Flags().Int64Var(&daemonMaxRequestSize, "max-request-size", api.DefaultMaxRequestSize, "Maximum size in bytes of a request sent to the daemon API")
 	rootCmd.AddCommand(daemonCmd)
 }
 
-const hostVirtualIP = "192.168.127.254"
-
 var daemonCmd = &cobra.Command{
 	Use:    "daemon",
 	Short:  "Run the crc daemon",
 	Long:   "Run the crc daemon",
 	Hidden: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		// sockets passed by systemd when the daemon is socket activated
+		activated, err := systemd.ActivationListeners()
+		if err != nil {
+			return err
+		}
+		networkListener := activated[constants.DaemonNetworkSocketName]
+
 		var endpoints []string
 		if runtime.GOOS == "windows" {
 			endpoints = append(endpoints, transport.DefaultURL)
 		} else {
-			_ = os.Remove(constants.NetworkSocketPath)
-			endpoints = append(endpoints, fmt.Sprintf("unix://%s", constants.NetworkSocketPath))
+			if networkListener == nil {
+				_ = os.Remove(profile.NetworkSocketPath(profileName))
+				endpoints = append(endpoints, fmt.Sprintf("unix://%s", profile.NetworkSocketPath(profileName)))
+			}
 			if runtime.GOOS == "linux" {
 				endpoints = append(endpoints, transport.DefaultURL)
 			}
 		}
 
-		err := run(&types.Configuration{
-			Debug:             false, // never log packets
-			CaptureFile:       captureFile(),
-			MTU:               4000, // Large packets slightly improve the performance. Less small packets.
-			Subnet:            "192.168.127.0/24",
-			GatewayIP:         constants.VSockGateway,
-			GatewayMacAddress: "\x5A\x94\xEF\xE4\x0C\xDD",
-			DNS: []types.Zone{
-				{
-					Name:      "apps-crc.testing.",
-					DefaultIP: net.ParseIP("192.168.127.2"),
-				},
-				{
-					Name: "crc.testing.",
-					Records: []types.Record{
-						{
-							Name: "gateway",
-							IP:   net.ParseIP("192.168.127.1"),
-						},
-						{
-							Name: "api",
-							IP:   net.ParseIP("192.168.127.2"),
-						},
-						{
-							Name: "api-int",
-							IP:   net.ParseIP("192.168.127.2"),
-						},
-						{
-							Regexp: regexp.MustCompile("crc-(.*?)-master-0"),
-							IP:     net.ParseIP("192.168.126.11"),
-						},
-						{
-							Name: "host",
-							IP:   net.ParseIP(hostVirtualIP),
-						},
-					},
-				},
-			},
-			Forwards: map[string]string{
-				fmt.Sprintf(":%d", constants.VsockSSHPort): "192.168.127.2:22",
-				":6443": "192.168.127.2:6443",
-				":443":  "192.168.127.2:443",
-			},
-			NAT: map[string]string{
-				hostVirtualIP: "127.0.0.1",
-			},
-		}, endpoints)
+		virtualNetwork := cmdConfig.VirtualNetwork(config)
+		bundleName, bundleInfo := daemonBundleInfo()
+		configuration, err := newVirtualNetworkConfiguration(virtualNetwork, bundleInfo)
+		if err != nil {
+			return err
+		}
+		recordDaemonBundle(bundleName)
+		configuration.CaptureFile = captureFile()
+		vmIP, err := virtualNetwork.VMIP()
+		if err != nil {
+			return err
+		}
+		err = run(configuration, vmIP.String(), endpoints, networkListener, activated[constants.DaemonAPISocketName])
 		return err
 	},
 }
@@ -104,7 +84,9 @@ func captureFile() string {
 	return filepath.Join(constants.CrcBaseDir, "capture.pcap")
 }
 
-func run(configuration *types.Configuration, endpoints []string) error {
+// run serves the virtual network on endpoints and on networkListener, and the
+// API on apiListener. The listeners are only set when the daemon is socket activated.
+func run(configuration *types.Configuration, vmIP string, endpoints []string, networkListener, apiListener net.Listener) error {
 	vn, err := virtualnetwork.New(configuration)
 	if err != nil {
 		return err
@@ -112,13 +94,21 @@ func run(configuration *types.Configuration, endpoints []string) error {
 	log.Info("waiting for clients...")
 	errCh := make(chan error)
 
+	var listeners []net.Listener
+	if networkListener != nil {
+		log.Infof("listening %s passed by systemd", networkListener.Addr())
+		listeners = append(listeners, networkListener)
+	}
 	for _, endpoint := range endpoints {
 		log.Infof("listening %s", endpoint)
 		ln, err := transport.Listen(endpoint)
 		if err != nil {
 			return errors.Wrap(err, "cannot listen")
 		}
-
+		listeners = append(listeners, ln)
+	}
+	for _, ln := range listeners {
+		ln := ln
 		go func() {
 			if err := http.Serve(ln, vn.Mux()); err != nil {
 				errCh <- err
@@ -134,8 +124,18 @@ func run(configuration *types.Configuration, endpoints []string) error {
 		}()
 	}
 
+	// Ports can only be forwarded to the VM when it is connected to the virtual network
+	// and the traffic with the VM is only known in this case
+	var forwarder api.PortForwarder
+	var trafficCounter api.TrafficCounter
+	if network.ParseMode(config.Get(cmdConfig.NetworkMode).AsString()) == network.VSockMode {
+		forwarder = newVirtualNetworkForwarder(vn.Mux(), configuration.Forwards, vmIP)
+		trafficCounter = func() uint64 {
+			return vn.BytesSent() + vn.BytesReceived()
+		}
+	}
 	go func() {
-		if err := runDaemon(); err != nil {
+		if err := runDaemon(forwarder, trafficCounter, apiListener); err != nil {
 			errCh <- err
 		}
 	}()
@@ -150,12 +150,30 @@ func run(configuration *types.Configuration, endpoints []string) error {
 	}
 }
 
-func runDaemon() error {
-	// Remove if an old socket is present
-	os.Remove(constants.DaemonSocketPath)
-	apiServer, err := api.CreateServer(constants.DaemonSocketPath, config, newMachine())
+func runDaemon(forwarder api.PortForwarder, trafficCounter api.TrafficCounter, apiListener net.Listener) error {
+	var apiServer api.Server
+	var err error
+	if apiListener != nil {
+		apiServer, err = api.CreateServerWithListener(apiListener, config, newMachine(), forwarder, daemonMaxRequestSize)
+	} else {
+		// Remove if an old socket is present
+		os.Remove(profile.DaemonSocketPath(profileName))
+		apiServer, err = api.CreateServer(profile.DaemonSocketPath(profileName), config, newMachine(), forwarder, daemonMaxRequestSize)
+	}
 	if err != nil {
 		return err
 	}
+	if trafficCounter != nil {
+		apiServer.AutoStopWhenIdle(config, trafficCounter)
+	} else if config.Get(cmdConfig.AutoStopIdleMinutes).AsInt() > 0 {
+		log.Warnf("The '%s' setting is only supported with the vsock network mode", cmdConfig.AutoStopIdleMinutes)
+	}
+	apiServer.WarnBeforeBundleExpiry(func() (time.Time, error) {
+		bundleInfo, err := bundle.GetCachedBundleInfo(filepath.Base(config.Get(cmdConfig.Bundle).AsString()))
+		if err != nil {
+			return time.Time{}, err
+		}
+		return bundleInfo.GetCertsExpiryDate()
+	})
 	return apiServer.Serve()
 }
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/code-ready/crc/pkg/crc/api"
+	"github.com/code-ready/gvisor-tap-vsock/pkg/types"
+)
+
+// virtualNetworkForwarder changes the ports forwarded by the virtual network
+// of the daemon through the HTTP API of the network. The calls are
+// serialized: the API neither checks if a port is already forwarded nor
+// locks the list of forwards while reading it.
+type virtualNetworkForwarder struct {
+	lock sync.Mutex
+	mux  http.Handler
+	// forwards set up when the daemon starts, they are needed by crc
+	reserved map[int]bool
+	vmIP     string
+}
+
+func newVirtualNetworkForwarder(mux http.Handler, forwards map[string]string, vmIP string) *virtualNetworkForwarder {
+	reserved := make(map[int]bool)
+	for local := range forwards {
+		if port, err := parsePort(local); err == nil {
+			reserved[port] = true
+		}
+	}
+	return &virtualNetworkForwarder{
+		mux:      mux,
+		reserved: reserved,
+		vmIP:     vmIP,
+	}
+}
+
+func (f *virtualNetworkForwarder) Expose(forward api.PortForward) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	forwarded, err := f.isForwarded(forward.HostPort)
+	if err != nil {
+		return err
+	}
+	if forwarded {
+		return api.ErrPortForwardExists
+	}
+	return f.do("/expose", types.ExposeRequest{
+		Local:  fmt.Sprintf(":%d", forward.HostPort),
+		Remote: fmt.Sprintf("%s:%d", f.vmIP, forward.VMPort),
+	}, nil)
+}
+
+func (f *virtualNetworkForwarder) Unexpose(hostPort int) error {
+	if f.reserved[hostPort] {
+		return api.ErrPortForwardReserved
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	forwarded, err := f.isForwarded(hostPort)
+	if err != nil {
+		return err
+	}
+	if !forwarded {
+		return api.ErrPortForwardNotFound
+	}
+	return f.do("/unexpose", types.UnexposeRequest{
+		Local: fmt.Sprintf(":%d", hostPort),
+	}, nil)
+}
+
+func (f *virtualNetworkForwarder) List() ([]api.PortForward, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.list()
+}
+
+func (f *virtualNetworkForwarder) list() ([]api.PortForward, error) {
+	var proxies []types.ExposeRequest
+	if err := f.do("/all", nil, &proxies); err != nil {
+		return nil, err
+	}
+	var forwards []api.PortForward
+	for _, proxy := range proxies {
+		hostPort, err := parsePort(proxy.Local)
+		if err != nil {
+			return nil, err
+		}
+		vmPort, err := parsePort(proxy.Remote)
+		if err != nil {
+			return nil, err
+		}
+		forwards = append(forwards, api.PortForward{HostPort: hostPort, VMPort: vmPort})
+	}
+	sort.Slice(forwards, func(i, j int) bool {
+		return forwards[i].HostPort < forwards[j].HostPort
+	})
+	return forwards, nil
+}
+
+func (f *virtualNetworkForwarder) isForwarded(hostPort int) (bool, error) {
+	forwards, err := f.list()
+	if err != nil {
+		return false, err
+	}
+	for _, forward := range forwards {
+		if forward.HostPort == hostPort {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// do sends a request to the forwarder API of the virtual network, in-process
+func (f *virtualNetworkForwarder) do(path string, in interface{}, out interface{}) error {
+	method := http.MethodGet
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		method = http.MethodPost
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, "/services/forwarder"+path, body)
+	if err != nil {
+		return err
+	}
+	res := newForwarderResponse()
+	f.mux.ServeHTTP(res, req)
+	if res.code != http.StatusOK {
+		return errors.New(strings.TrimSpace(res.body.String()))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(res.body.Bytes(), out)
+}
+
+// forwarderResponse keeps the response of the forwarder API in memory
+type forwarderResponse struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func newForwarderResponse() *forwarderResponse {
+	return &forwarderResponse{
+		header: make(http.Header),
+		code:   http.StatusOK,
+	}
+}
+
+func (r *forwarderResponse) Header() http.Header {
+	return r.header
+}
+
+func (r *forwarderResponse) Write(data []byte) (int, error) {
+	return r.body.Write(data)
+}
+
+func (r *forwarderResponse) WriteHeader(code int) {
+	r.code = code
+}
+
+func parsePort(address string) (int, error) {
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(port)
+}
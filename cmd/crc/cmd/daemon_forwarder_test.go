@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/code-ready/crc/pkg/crc/api"
+	"github.com/code-ready/gvisor-tap-vsock/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeForwarderMux serves the forwarder API of the virtual network, like
+// upstream it does not lock the forwards
+func fakeForwarderMux(forwards map[string]string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/services/forwarder/all", func(w http.ResponseWriter, r *http.Request) {
+		var proxies []types.ExposeRequest
+		for local, remote := range forwards {
+			proxies = append(proxies, types.ExposeRequest{Local: local, Remote: remote})
+		}
+		_ = json.NewEncoder(w).Encode(proxies)
+	})
+	mux.HandleFunc("/services/forwarder/expose", func(w http.ResponseWriter, r *http.Request) {
+		var req types.ExposeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, ok := forwards[req.Local]; ok {
+			http.Error(w, "proxy already running", http.StatusInternalServerError)
+			return
+		}
+		forwards[req.Local] = req.Remote
+	})
+	mux.HandleFunc("/services/forwarder/unexpose", func(w http.ResponseWriter, r *http.Request) {
+		var req types.UnexposeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		delete(forwards, req.Local)
+	})
+	return mux
+}
+
+func TestVirtualNetworkForwarder(t *testing.T) {
+	forwards := map[string]string{":2222": "192.168.127.2:22"}
+	forwarder := newVirtualNetworkForwarder(fakeForwarderMux(forwards), forwards, "192.168.127.2")
+
+	require.NoError(t, forwarder.Expose(api.PortForward{HostPort: 8080, VMPort: 80}))
+	assert.Equal(t, api.ErrPortForwardExists, forwarder.Expose(api.PortForward{HostPort: 8080, VMPort: 8080}))
+	list, err := forwarder.List()
+	require.NoError(t, err)
+	assert.Equal(t, []api.PortForward{{HostPort: 2222, VMPort: 22}, {HostPort: 8080, VMPort: 80}}, list)
+
+	assert.Equal(t, api.ErrPortForwardReserved, forwarder.Unexpose(2222))
+	require.NoError(t, forwarder.Unexpose(8080))
+	assert.Equal(t, api.ErrPortForwardNotFound, forwarder.Unexpose(8080))
+}
+
+func TestVirtualNetworkForwarderConcurrentExpose(t *testing.T) {
+	forwards := map[string]string{}
+	forwarder := newVirtualNetworkForwarder(fakeForwarderMux(forwards), forwards, "192.168.127.2")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- forwarder.Expose(api.PortForward{HostPort: 8080, VMPort: 80})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	var exposed int
+	for err := range errs {
+		if err == nil {
+			exposed++
+			continue
+		}
+		assert.Equal(t, api.ErrPortForwardExists, err)
+	}
+	assert.Equal(t, 1, exposed)
+}
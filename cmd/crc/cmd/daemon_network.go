@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"regexp"
+
+	cmdConfig "github.com/code-ready/crc/cmd/crc/cmd/config"
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/code-ready/crc/pkg/crc/machine/bundle"
+	"github.com/code-ready/crc/pkg/crc/network"
+	"github.com/code-ready/crc/pkg/crc/profile"
+	"github.com/code-ready/gvisor-tap-vsock/pkg/types"
+)
+
+// defaultNodeIP is the address of the node in the bundles built by snc
+const defaultNodeIP = "192.168.126.11"
+
+// daemonBundleInfo returns the file name and the metadata of the configured
+// bundle, or nil when it is not extracted yet, for instance when the daemon is
+// started by crc setup before the bundle is extracted.
+func daemonBundleInfo() (string, *bundle.CrcBundleInfo) {
+	bundleName := filepath.Base(config.Get(cmdConfig.Bundle).AsString())
+	bundleInfo, err := bundle.GetCachedBundleInfo(bundleName)
+	if err != nil {
+		logging.Debugf("Cannot read the bundle metadata, using the default DNS records: %v", err)
+		return "", nil
+	}
+	return bundleName, bundleInfo
+}
+
+// recordDaemonBundle records the bundle the DNS records are derived from,
+// crc setup restarts the daemon when it is not the bundle of this crc version.
+func recordDaemonBundle(bundleName string) {
+	// #nosec G306
+	if err := ioutil.WriteFile(profile.DaemonBundlePath(profileName), []byte(bundleName), 0644); err != nil {
+		logging.Warnf("Cannot record the bundle used by the daemon: %v", err)
+	}
+}
+
+// newVirtualNetworkConfiguration returns the configuration of the virtual
+// network serving the VM. The DNS records are derived from the bundle.
+func newVirtualNetworkConfiguration(virtualNetwork network.VirtualNetwork, bundleInfo *bundle.CrcBundleInfo) (*types.Configuration, error) {
+	if err := virtualNetwork.Validate(); err != nil {
+		return nil, fmt.Errorf("Invalid virtual network configuration: %v", err)
+	}
+	vmIP, err := virtualNetwork.VMIP()
+	if err != nil {
+		return nil, err
+	}
+	gatewayMAC, err := virtualNetwork.GatewayMACAddress()
+	if err != nil {
+		return nil, err
+	}
+	portForwards, err := virtualNetwork.PortForwards()
+	if err != nil {
+		return nil, err
+	}
+	forwards := make(map[string]string)
+	for _, forward := range portForwards {
+		forwards[fmt.Sprintf(":%d", forward.HostPort)] = fmt.Sprintf("%s:%d", vmIP, forward.VMPort)
+	}
+
+	// domains of constants.ClusterDomain and constants.AppsDomain, they are checked when extracting a bundle
+	clusterInfo := bundle.ClusterInfo{
+		ClusterName: "crc",
+		BaseDomain:  "testing",
+		AppsDomain:  "apps-crc.testing",
+	}
+	nodeIP := defaultNodeIP
+	if bundleInfo != nil {
+		clusterInfo = bundleInfo.ClusterInfo
+		if len(bundleInfo.Nodes) > 0 && bundleInfo.Nodes[0].InternalIP != "" {
+			nodeIP = bundleInfo.Nodes[0].InternalIP
+		}
+	}
+
+	return &types.Configuration{
+		Debug:             false, // never log packets
+		MTU:               virtualNetwork.MTU,
+		Subnet:            virtualNetwork.Subnet,
+		GatewayIP:         virtualNetwork.GatewayIP,
+		GatewayMacAddress: gatewayMAC,
+		DNS: []types.Zone{
+			{
+				Name:      fmt.Sprintf("%s.", clusterInfo.AppsDomain),
+				DefaultIP: vmIP,
+			},
+			{
+				Name: fmt.Sprintf("%s.%s.", clusterInfo.ClusterName, clusterInfo.BaseDomain),
+				Records: []types.Record{
+					{
+						Name: "gateway",
+						IP:   net.ParseIP(virtualNetwork.GatewayIP),
+					},
+					{
+						Name: "api",
+						IP:   vmIP,
+					},
+					{
+						Name: "api-int",
+						IP:   vmIP,
+					},
+					{
+						Regexp: regexp.MustCompile(fmt.Sprintf("%s-(.*?)-master-0", regexp.QuoteMeta(clusterInfo.ClusterName))),
+						IP:     net.ParseIP(nodeIP),
+					},
+					{
+						Name: "host",
+						IP:   net.ParseIP(virtualNetwork.HostIP),
+					},
+				},
+			},
+		},
+		Forwards: forwards,
+		NAT: map[string]string{
+			virtualNetwork.HostIP: "127.0.0.1",
+		},
+	}, nil
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"net"
+	"testing"
+
+	"github.com/code-ready/crc/pkg/crc/machine/bundle"
+	"github.com/code-ready/crc/pkg/crc/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVirtualNetworkConfiguration(t *testing.T) {
+	configuration, err := newVirtualNetworkConfiguration(network.VirtualNetwork{
+		Subnet:         "10.88.0.0/24",
+		GatewayIP:      "10.88.0.1",
+		HostIP:         "10.88.0.254",
+		MTU:            1500,
+		GatewayMAC:     "5a:94:ef:00:00:01",
+		ForwardedPorts: "6443,8443:443",
+	}, &bundle.CrcBundleInfo{
+		ClusterInfo: bundle.ClusterInfo{
+			ClusterName: "crc",
+			BaseDomain:  "testing",
+			AppsDomain:  "apps-crc.testing",
+		},
+		Nodes: []bundle.Node{{InternalIP: "192.168.126.12"}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "10.88.0.0/24", configuration.Subnet)
+	assert.Equal(t, "10.88.0.1", configuration.GatewayIP)
+	assert.Equal(t, 1500, configuration.MTU)
+	assert.Equal(t, map[string]string{
+		":2222": "10.88.0.2:22",
+		":6443": "10.88.0.2:6443",
+		":8443": "10.88.0.2:443",
+	}, configuration.Forwards)
+	assert.Equal(t, "\x5A\x94\xEF\x00\x00\x01", configuration.GatewayMacAddress)
+	assert.Equal(t, map[string]string{"10.88.0.254": "127.0.0.1"}, configuration.NAT)
+
+	require.Len(t, configuration.DNS, 2)
+	assert.Equal(t, "apps-crc.testing.", configuration.DNS[0].Name)
+	assert.True(t, configuration.DNS[0].DefaultIP.Equal(net.ParseIP("10.88.0.2")))
+	assert.Equal(t, "crc.testing.", configuration.DNS[1].Name)
+	records := configuration.DNS[1].Records
+	require.Len(t, records, 5)
+	assert.True(t, records[0].IP.Equal(net.ParseIP("10.88.0.1")))
+	assert.True(t, records[1].IP.Equal(net.ParseIP("10.88.0.2")))
+	assert.True(t, records[3].Regexp.MatchString("crc-4727w-master-0"))
+	assert.True(t, records[3].IP.Equal(net.ParseIP("192.168.126.12")))
+	assert.True(t, records[4].IP.Equal(net.ParseIP("10.88.0.254")))
+}
+
+func TestNewVirtualNetworkConfigurationWithoutBundle(t *testing.T) {
+	configuration, err := newVirtualNetworkConfiguration(network.VirtualNetwork{
+		Subnet:     network.DefaultVirtualSubnet,
+		GatewayIP:  network.DefaultVirtualGatewayIP,
+		HostIP:     network.DefaultVirtualHostIP,
+		MTU:        network.DefaultVirtualMTU,
+		GatewayMAC: network.DefaultVirtualGatewayMAC,
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "apps-crc.testing.", configuration.DNS[0].Name)
+	assert.Equal(t, "crc.testing.", configuration.DNS[1].Name)
+	assert.True(t, configuration.DNS[1].Records[3].IP.Equal(net.ParseIP(defaultNodeIP)))
+}
+
+func TestNewVirtualNetworkConfigurationInvalid(t *testing.T) {
+	_, err := newVirtualNetworkConfiguration(network.VirtualNetwork{
+		Subnet:     network.DefaultVirtualSubnet,
+		GatewayIP:  "10.88.0.1",
+		HostIP:     network.DefaultVirtualHostIP,
+		MTU:        network.DefaultVirtualMTU,
+		GatewayMAC: network.DefaultVirtualGatewayMAC,
+	}, nil)
+	assert.EqualError(t, err, "Invalid virtual network configuration: the gateway virtual address 10.88.0.1 is not in subnet 192.168.127.0/24")
+}
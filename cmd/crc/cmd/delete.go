@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -28,11 +29,11 @@ var deleteCmd = &cobra.Command{
 	Short: "Delete the OpenShift cluster",
 	Long:  "Delete the OpenShift cluster",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runDelete(os.Stdout, newMachine(), clearCache, constants.MachineCacheDir, outputFormat != jsonFormat, globalForce, outputFormat)
+		return runDelete(cmd.Context(), os.Stdout, newMachine(), clearCache, constants.MachineCacheDir, outputFormat != jsonFormat, globalForce, outputFormat)
 	},
 }
 
-func deleteMachine(client machine.Client, clearCache bool, cacheDir string, interactive, force bool) (bool, error) {
+func deleteMachine(ctx context.Context, client machine.Client, clearCache bool, cacheDir string, interactive, force bool) (bool, error) {
 	if clearCache {
 		if !interactive && !force {
 			return false, errors.New("non-interactive deletion requires --force")
@@ -43,7 +44,7 @@ func deleteMachine(client machine.Client, clearCache bool, cacheDir string, inte
 		}
 	}
 
-	if err := checkIfMachineMissing(client); err != nil {
+	if err := checkIfMachineMissing(ctx, client); err != nil {
 		return false, err
 	}
 
@@ -53,13 +54,13 @@ func deleteMachine(client machine.Client, clearCache bool, cacheDir string, inte
 
 	yes := input.PromptUserForYesOrNo("Do you want to delete the OpenShift cluster", force)
 	if yes {
-		return true, client.Delete()
+		return true, client.Delete(ctx)
 	}
 	return false, nil
 }
 
-func runDelete(writer io.Writer, client machine.Client, clearCache bool, cacheDir string, interactive, force bool, outputFormat string) error {
-	machineDeleted, err := deleteMachine(client, clearCache, cacheDir, interactive, force)
+func runDelete(ctx context.Context, writer io.Writer, client machine.Client, clearCache bool, cacheDir string, interactive, force bool, outputFormat string) error {
+	machineDeleted, err := deleteMachine(ctx, client, clearCache, cacheDir, interactive, force)
 	return render(&deleteResult{
 		Success:        err == nil,
 		Error:          crcErrors.ToSerializableError(err),
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -17,7 +18,7 @@ func TestPlainDelete(t *testing.T) {
 	defer os.RemoveAll(cacheDir)
 
 	out := new(bytes.Buffer)
-	assert.NoError(t, runDelete(out, fakemachine.NewClient(), true, cacheDir, true, true, ""))
+	assert.NoError(t, runDelete(context.Background(), out, fakemachine.NewClient(), true, cacheDir, true, true, ""))
 	assert.Equal(t, "Deleted the OpenShift cluster\n", out.String())
 
 	_, err = os.Stat(cacheDir)
@@ -30,7 +31,7 @@ func TestNonForceDelete(t *testing.T) {
 	defer os.RemoveAll(cacheDir)
 
 	out := new(bytes.Buffer)
-	assert.NoError(t, runDelete(out, fakemachine.NewClient(), true, cacheDir, true, false, ""))
+	assert.NoError(t, runDelete(context.Background(), out, fakemachine.NewClient(), true, cacheDir, true, false, ""))
 	assert.Equal(t, "", out.String())
 
 	_, err = os.Stat(cacheDir)
@@ -43,7 +44,7 @@ func TestJSONDelete(t *testing.T) {
 	defer os.RemoveAll(cacheDir)
 
 	out := new(bytes.Buffer)
-	assert.NoError(t, runDelete(out, fakemachine.NewClient(), true, cacheDir, false, true, jsonFormat))
+	assert.NoError(t, runDelete(context.Background(), out, fakemachine.NewClient(), true, cacheDir, false, true, jsonFormat))
 	assert.JSONEq(t, `{"success": true}`, out.String())
 
 	_, err = os.Stat(cacheDir)
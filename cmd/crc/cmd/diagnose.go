@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	cmdConfig "github.com/code-ready/crc/cmd/crc/cmd/config"
+	"github.com/code-ready/crc/pkg/crc/cluster"
+	crcConfig "github.com/code-ready/crc/pkg/crc/config"
+	"github.com/code-ready/crc/pkg/crc/constants"
+	"github.com/code-ready/crc/pkg/crc/diagnose"
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/code-ready/crc/pkg/crc/machine"
+	"github.com/code-ready/crc/pkg/crc/machine/bundle"
+	"github.com/code-ready/crc/pkg/crc/preflight"
+	"github.com/code-ready/crc/pkg/crc/profile"
+	"github.com/code-ready/crc/pkg/crc/ssh"
+	"github.com/spf13/cobra"
+)
+
+const ocCommand = "sudo KUBECONFIG=/opt/kubeconfig oc"
+
+var diagnoseOutputFile string
+
+func init() {
+	diagnoseCmd.Flags().StringVar(&diagnoseOutputFile, "output-file", "", "Path of the diagnostics archive (default crc-diagnose-<date>.zip in the current directory)")
+	rootCmd.AddCommand(diagnoseCmd)
+}
+
+var diagnoseCmd = &cobra.Command{
+	Use:   "diagnose",
+	Short: "Collect diagnostics of the host and of the OpenShift cluster",
+	Long: `Collect the logs, the configuration and the preflight checks results of crc, and the logs and the state of the
+OpenShift cluster when it is running, in a zip archive which can be attached to a bug report.
+The pull secret, the kubeadmin password and the proxy credentials are removed from the archive.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDiagnose(cmd.Context(), os.Stdout, newMachine(), config, diagnoseOutputFile)
+	},
+}
+
+func runDiagnose(ctx context.Context, writer io.Writer, client machine.Client, cfg crcConfig.Storage, filename string) error {
+	if filename == "" {
+		filename = fmt.Sprintf("crc-diagnose-%s.zip", time.Now().Format("20060102-150405"))
+	}
+
+	collectors := hostCollectors(client.GetName(), cfg)
+	sshRunner, err := diagnoseSSHRunner(ctx, client)
+	if err != nil {
+		logging.Warnf("Cannot collect the diagnostics of the OpenShift cluster: %v", err)
+	} else {
+		defer sshRunner.Close()
+		collectors = append(collectors, vmCollectors(sshRunner)...)
+	}
+
+	if err := diagnose.Run(filename, collectors, diagnose.NewScrubber(diagnoseSecrets(cfg)...)); err != nil {
+		return fmt.Errorf("Cannot write the diagnostics archive: %v", err)
+	}
+	_, err = fmt.Fprintf(writer, "Diagnostics written to %s\n", filename)
+	return err
+}
+
+// diagnoseSSHRunner returns a runner connected to the VM, or an error when it is not running
+func diagnoseSSHRunner(ctx context.Context, client machine.Client) (*ssh.Runner, error) {
+	if err := checkIfMachineMissing(ctx, client); err != nil {
+		return nil, err
+	}
+	running, err := client.IsRunning(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !running {
+		return nil, fmt.Errorf("the CodeReady Containers VM is not running")
+	}
+	sshRunner, err := newSSHRunner(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	// the connection is opened by the first command, before the
+	// collectors share it
+	if _, _, err := sshRunner.Run("true"); err != nil {
+		sshRunner.Close()
+		return nil, err
+	}
+	return sshRunner, nil
+}
+
+func hostCollectors(name string, cfg crcConfig.Storage) []diagnose.Collector {
+	return []diagnose.Collector{
+		&diagnose.TreeCollector{
+			Dir:    constants.CrcBaseDir,
+			Target: "tree.txt",
+		},
+		diagnose.File(constants.LogFilePath),
+		diagnose.File(constants.DaemonLogFilePath),
+		&diagnose.FileCollector{
+			Source: filepath.Join(constants.MachineBaseDir, "machines", name, "config.json"),
+			Target: "machine-config.json",
+		},
+		&diagnose.DataCollector{
+			Data:   diagnoseConfig(cfg),
+			Target: "crc-config.txt",
+		},
+		&diagnose.DataCollector{
+			Data:   diagnosePreflight(cfg),
+			Target: "preflight.txt",
+		},
+		&diagnose.DataCollector{
+			Data:   diagnoseVersion(),
+			Target: "version.txt",
+		},
+	}
+}
+
+func vmCollectors(sshRunner *ssh.Runner) []diagnose.Collector {
+	return []diagnose.Collector{
+		diagnose.VMCommand(sshRunner, "sudo journalctl -u kubelet -n 5000 --no-pager", "journalctl-kubelet.txt"),
+		diagnose.VMCommand(sshRunner, "sudo journalctl -u crio -n 5000 --no-pager", "journalctl-crio.txt"),
+		diagnose.VMCommand(sshRunner, "sudo journalctl -n 1000 --no-pager", "journalctl.txt"),
+		diagnose.VMFile(sshRunner, "/etc/resolv.conf"),
+		diagnose.VMFile(sshRunner, "/etc/hosts"),
+		diagnose.VMCommand(sshRunner, "ip addr", "ip-addr.txt"),
+		diagnose.VMCommand(sshRunner, "df -h", "df.txt"),
+		diagnose.VMCommand(sshRunner, "free -m", "free.txt"),
+		diagnose.VMCommand(sshRunner, "sudo crictl ps -a", "crictl-ps.txt"),
+		diagnose.VMCommand(sshRunner, ocCommand+" get clusteroperators", "get-clusteroperators.txt"),
+		diagnose.VMCommand(sshRunner, ocCommand+" get nodes -o wide", "get-nodes.txt"),
+		diagnose.VMCommand(sshRunner, ocCommand+" get pods --all-namespaces -o wide", "get-pods.txt"),
+		diagnose.VMCommand(sshRunner, ocCommand+" get events --all-namespaces", "get-events.txt"),
+		diagnose.VMCommand(sshRunner, ocCommand+" get clusterversion -o yaml", "clusterversion.yaml"),
+		diagnose.VMCommand(sshRunner, ocCommand+" get clusteroperators -o yaml", "clusteroperators.yaml"),
+		diagnose.VMCommand(sshRunner, ocCommand+" describe nodes", "describe-nodes.txt"),
+		&diagnose.ContainerLogCollector{Runner: sshRunner, Process: "crictl"},
+	}
+}
+
+// diagnoseConfig returns the crc configuration, the secrets being removed later by the scrubber
+func diagnoseConfig(cfg crcConfig.Storage) []byte {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "profile: %s\nconfig file: %s\n\n", profileName, profile.ConfigPath(profileName))
+	settings := cfg.AllConfigs()
+	var keys []string
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		setting := settings[key]
+		source := "config"
+		if setting.IsDefault {
+			source = "default"
+		}
+		fmt.Fprintf(&buffer, "%-38s: %s (%s)\n", key, setting.AsString(), source)
+	}
+	return buffer.Bytes()
+}
+
+func diagnosePreflight(cfg crcConfig.Storage) []byte {
+	var buffer bytes.Buffer
+	for _, result := range preflight.CheckHost(cfg) {
+		switch {
+		case result.Skipped:
+			fmt.Fprintf(&buffer, "SKIPPED %s\n", result.Description)
+		case result.Err != nil:
+			fmt.Fprintf(&buffer, "FAILED  %s: %v\n", result.Description, result.Err)
+		default:
+			fmt.Fprintf(&buffer, "OK      %s\n", result.Description)
+		}
+	}
+	return buffer.Bytes()
+}
+
+func diagnoseVersion() []byte {
+	var buffer bytes.Buffer
+	for _, line := range defaultVersion().lines() {
+		fmt.Fprintln(&buffer, line)
+	}
+	return buffer.Bytes()
+}
+
+// diagnoseSecrets returns the secrets which must not be in the diagnostics archive
+func diagnoseSecrets(cfg crcConfig.Storage) []string {
+	var secrets []string
+	if pullSecret, err := cluster.NewNonInteractivePullSecretLoader(cfg, "").Value(); err == nil {
+		secrets = append(secrets, strings.TrimSpace(pullSecret))
+	}
+	if bundleInfo, err := bundle.GetCachedBundleInfo(filepath.Base(cfg.Get(cmdConfig.Bundle).AsString())); err == nil {
+		if password, err := bundleInfo.GetKubeadminPassword(); err == nil {
+			secrets = append(secrets, strings.TrimSpace(password))
+		}
+	}
+	for _, key := range []string{cmdConfig.HTTPProxy, cmdConfig.HTTPSProxy} {
+		proxy, err := url.Parse(cfg.Get(key).AsString())
+		if err != nil || proxy.User == nil {
+			continue
+		}
+		if password, ok := proxy.User.Password(); ok {
+			secrets = append(secrets, password, url.QueryEscape(password))
+		}
+	}
+	return secrets
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -15,17 +16,17 @@ var ipCmd = &cobra.Command{
 	Short: "Get IP address of the running OpenShift cluster",
 	Long:  "Get IP address of the running OpenShift cluster",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runIP(args)
+		return runIP(cmd.Context(), args)
 	},
 }
 
-func runIP(arguments []string) error {
+func runIP(ctx context.Context, arguments []string) error {
 	client := newMachine()
-	if err := checkIfMachineMissing(client); err != nil {
+	if err := checkIfMachineMissing(ctx, client); err != nil {
 		return err
 	}
 
-	ip, err := client.IP()
+	ip, err := client.IP(ctx)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	crcErrors "github.com/code-ready/crc/pkg/crc/errors"
+	"github.com/code-ready/crc/pkg/crc/machine"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addOutputFormatFlag(mountListCmd)
+	mountCmd.AddCommand(mountAddCmd)
+	mountCmd.AddCommand(mountListCmd)
+	mountCmd.AddCommand(mountRemoveCmd)
+	rootCmd.AddCommand(mountCmd)
+}
+
+var mountCmd = &cobra.Command{
+	Use:   "mount",
+	Short: "Share host directories with the OpenShift cluster",
+	Long: `Share host directories with the OpenShift cluster.
+The directories are mounted in the virtual machine on every 'crc start', they can be used by hostPath volumes.
+Directories are shared with virtiofs, this is only supported with the libvirt driver.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var mountAddCmd = &cobra.Command{
+	Use:   "add HOST_DIR VM_DIR",
+	Short: "Share a host directory with the OpenShift cluster",
+	Long: `Share a host directory with the OpenShift cluster, it is mounted at VM_DIR in the virtual machine.
+VM_DIR must be in a writable location of the virtual machine such as /mnt or /var.
+A running cluster must be restarted with 'crc stop' and 'crc start' for the directory to be mounted.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMountAdd(cmd.Context(), os.Stdout, newMachine(), args[0], args[1])
+	},
+}
+
+var mountListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the host directories shared with the OpenShift cluster",
+	Long:  "List the host directories shared with the OpenShift cluster",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMountList(cmd.Context(), os.Stdout, newMachine(), outputFormat)
+	},
+}
+
+var mountRemoveCmd = &cobra.Command{
+	Use:   "remove VM_DIR",
+	Short: "Stop sharing a host directory with the OpenShift cluster",
+	Long:  "Stop sharing the host directory mounted at VM_DIR, it is unmounted right away when the cluster is running",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMountRemove(cmd.Context(), os.Stdout, newMachine(), args[0])
+	},
+}
+
+func runMountAdd(ctx context.Context, writer io.Writer, client machine.Client, hostDir, vmDir string) error {
+	if err := checkIfMachineMissing(ctx, client); err != nil {
+		return err
+	}
+	hostDir, err := filepath.Abs(hostDir)
+	if err != nil {
+		return err
+	}
+	if err := client.AddMount(ctx, hostDir, vmDir); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(writer, "Sharing %s at %s\n", hostDir, vmDir); err != nil {
+		return err
+	}
+	if running, _ := client.IsRunning(ctx); running {
+		_, err = fmt.Fprintln(writer, "Run 'crc stop' and 'crc start' to mount it in the running cluster")
+		return err
+	}
+	_, err = fmt.Fprintln(writer, "It will be mounted on the next 'crc start'")
+	return err
+}
+
+func runMountRemove(ctx context.Context, writer io.Writer, client machine.Client, vmDir string) error {
+	if err := checkIfMachineMissing(ctx, client); err != nil {
+		return err
+	}
+	if err := client.RemoveMount(ctx, vmDir); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(writer, "Stopped sharing %s\n", vmDir)
+	return err
+}
+
+type mountListResult struct {
+	Success bool                         `json:"success"`
+	Error   *crcErrors.SerializableError `json:"error,omitempty"`
+	Mounts  []mount                      `json:"mounts,omitempty"`
+}
+
+type mount struct {
+	HostDir string `json:"hostDir"`
+	VMDir   string `json:"vmDir"`
+}
+
+func runMountList(ctx context.Context, writer io.Writer, client machine.Client, outputFormat string) error {
+	return render(getMountList(ctx, client), writer, outputFormat)
+}
+
+func getMountList(ctx context.Context, client machine.Client) *mountListResult {
+	if err := checkIfMachineMissing(ctx, client); err != nil {
+		return &mountListResult{Success: false, Error: crcErrors.ToSerializableError(err)}
+	}
+	mounts, err := client.ListMounts(ctx)
+	if err != nil {
+		return &mountListResult{Success: false, Error: crcErrors.ToSerializableError(err)}
+	}
+	result := &mountListResult{Success: true}
+	for _, m := range mounts {
+		result.Mounts = append(result.Mounts, mount{
+			HostDir: m.HostDir,
+			VMDir:   m.VMDir,
+		})
+	}
+	return result
+}
+
+func (s *mountListResult) prettyPrintTo(writer io.Writer) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	if len(s.Mounts) == 0 {
+		_, err := fmt.Fprintln(writer, "No shared directories")
+		return err
+	}
+	w := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "HOST DIRECTORY\tVM DIRECTORY"); err != nil {
+		return err
+	}
+	for _, mount := range s.Mounts {
+		if _, err := fmt.Fprintf(w, "%s\t%s\n", mount.HostDir, mount.VMDir); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/code-ready/crc/pkg/crc/machine/fakemachine"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlainMountList(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runMountList(context.Background(), out, fakemachine.NewClient(), ""))
+	assert.Equal(t, `HOST DIRECTORY  VM DIRECTORY
+/home/user/src  /mnt/src
+`, out.String())
+}
+
+func TestJsonMountList(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runMountList(context.Background(), out, fakemachine.NewClient(), jsonFormat))
+	assert.JSONEq(t, `{
+  "success": true,
+  "mounts": [
+    {
+      "hostDir": "/home/user/src",
+      "vmDir": "/mnt/src"
+    }
+  ]
+}`, out.String())
+}
+
+func TestJsonMountListWithError(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runMountList(context.Background(), out, fakemachine.NewFailingClient(), jsonFormat))
+	assert.JSONEq(t, `{"success": false, "error": "broken"}`, out.String())
+}
+
+func TestMountAdd(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runMountAdd(context.Background(), out, fakemachine.NewClient(), "/home/user/src", "/mnt/src"))
+	assert.Equal(t, "Sharing /home/user/src at /mnt/src\nRun 'crc stop' and 'crc start' to mount it in the running cluster\n", out.String())
+
+	out.Reset()
+	assert.EqualError(t, runMountAdd(context.Background(), out, fakemachine.NewFailingClient(), "/home/user/src", "/mnt/src"), "mount failed")
+	assert.Empty(t, out.String())
+}
+
+func TestMountRemove(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runMountRemove(context.Background(), out, fakemachine.NewClient(), "/mnt/src"))
+	assert.Equal(t, "Stopped sharing /mnt/src\n", out.String())
+
+	out.Reset()
+	assert.EqualError(t, runMountRemove(context.Background(), out, fakemachine.NewFailingClient(), "/mnt/src"), "unmount failed")
+	assert.Empty(t, out.String())
+}
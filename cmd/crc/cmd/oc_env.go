@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/code-ready/crc/pkg/crc/constants"
@@ -17,18 +18,18 @@ var ocEnvCmd = &cobra.Command{
 	Short: "Add the 'oc' executable to PATH",
 	Long:  `Add the OpenShift client executable 'oc' to PATH`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runOcEnv(args)
+		return runOcEnv(cmd.Context(), args)
 	},
 }
 
-func runOcEnv(args []string) error {
+func runOcEnv(ctx context.Context, args []string) error {
 	userShell, err := shell.GetShell(forceShell)
 	if err != nil {
 		return fmt.Errorf("Error running the oc-env command: %s", err.Error())
 	}
 
 	client := newMachine()
-	consoleResult, err := client.GetConsoleURL()
+	consoleResult, err := client.GetConsoleURL(ctx)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	crcErrors "github.com/code-ready/crc/pkg/crc/errors"
+	"github.com/code-ready/crc/pkg/crc/machine"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addOutputFormatFlag(pauseCmd)
+	addOutputFormatFlag(resumeCmd)
+	rootCmd.AddCommand(pauseCmd)
+	rootCmd.AddCommand(resumeCmd)
+}
+
+var pauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Pause the OpenShift cluster",
+	Long:  "Save the memory of the OpenShift cluster to disk and stop its virtual machine, freeing its CPU and memory until 'crc resume'",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPause(cmd.Context(), os.Stdout, newMachine(), outputFormat)
+	},
+}
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume the paused OpenShift cluster",
+	Long:  "Resume the OpenShift cluster paused with 'crc pause'",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := cancelOnInterrupt(cmd.Context())
+		defer cancel()
+		return runResume(ctx, os.Stdout, newMachine(), outputFormat)
+	},
+}
+
+func runPause(ctx context.Context, writer io.Writer, client machine.Client, outputFormat string) error {
+	err := checkIfMachineMissing(ctx, client)
+	if err == nil {
+		err = client.Pause(ctx)
+	}
+	return render(&pauseResult{
+		Success: err == nil,
+		Error:   crcErrors.ToSerializableError(err),
+		message: "Paused the OpenShift cluster",
+	}, writer, outputFormat)
+}
+
+func runResume(ctx context.Context, writer io.Writer, client machine.Client, outputFormat string) error {
+	err := checkIfMachineMissing(ctx, client)
+	if err == nil {
+		err = client.Resume(ctx)
+	}
+	return render(&pauseResult{
+		Success: err == nil,
+		Error:   crcErrors.ToSerializableError(err),
+		message: "Resumed the OpenShift cluster",
+	}, writer, outputFormat)
+}
+
+type pauseResult struct {
+	Success bool                         `json:"success"`
+	Error   *crcErrors.SerializableError `json:"error,omitempty"`
+	message string
+}
+
+func (s *pauseResult) prettyPrintTo(writer io.Writer) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	_, err := fmt.Fprintln(writer, s.message)
+	return err
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/code-ready/crc/pkg/crc/machine/fakemachine"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPausePlainSuccess(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runPause(context.Background(), out, fakemachine.NewClient(), ""))
+	assert.Equal(t, "Paused the OpenShift cluster\n", out.String())
+}
+
+func TestPauseJSONError(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runPause(context.Background(), out, fakemachine.NewFailingClient(), jsonFormat))
+	assert.JSONEq(t, `{"success": false, "error": "pause failed"}`, out.String())
+}
+
+func TestResumePlainSuccess(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runResume(context.Background(), out, fakemachine.NewClient(), ""))
+	assert.Equal(t, "Resumed the OpenShift cluster\n", out.String())
+}
+
+func TestResumePlainError(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.EqualError(t, runResume(context.Background(), out, fakemachine.NewFailingClient(), ""), "resume failed")
+}
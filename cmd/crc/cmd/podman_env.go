@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -19,18 +20,18 @@ var podmanEnvCmd = &cobra.Command{
 	},
 }
 
-func RunPodmanEnv(args []string) error {
+func RunPodmanEnv(ctx context.Context, args []string) error {
 	userShell, err := shell.GetShell(forceShell)
 	if err != nil {
 		return fmt.Errorf("Error running the podman-env command: %s", err.Error())
 	}
 
 	client := newMachine()
-	if err := checkIfMachineMissing(client); err != nil {
+	if err := checkIfMachineMissing(ctx, client); err != nil {
 		return err
 	}
 
-	ip, err := client.IP()
+	ip, err := client.IP(ctx)
 	if err != nil {
 		return err
 	}
@@ -38,7 +39,7 @@ func RunPodmanEnv(args []string) error {
 	fmt.Println(shell.GetPathEnvString(userShell, constants.CrcBinDir))
 	fmt.Println(shell.GetEnvString(userShell, "PODMAN_USER", constants.DefaultSSHUser))
 	fmt.Println(shell.GetEnvString(userShell, "PODMAN_HOST", ip))
-	fmt.Println(shell.GetEnvString(userShell, "PODMAN_IDENTITY_FILE", constants.GetPrivateKeyPath()))
+	fmt.Println(shell.GetEnvString(userShell, "PODMAN_IDENTITY_FILE", constants.GetPrivateKeyPath(client.GetName())))
 	fmt.Println(shell.GetEnvString(userShell, "PODMAN_IGNORE_HOSTS", "1"))
 	fmt.Println(shell.GenerateUsageHint(userShell, "crc podman-env"))
 	return nil
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/code-ready/crc/pkg/crc/api"
+	apiClient "github.com/code-ready/crc/pkg/crc/api/client"
+	crcErrors "github.com/code-ready/crc/pkg/crc/errors"
+	"github.com/code-ready/crc/pkg/crc/profile"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addOutputFormatFlag(portForwardListCmd)
+	portForwardCmd.AddCommand(portForwardAddCmd)
+	portForwardCmd.AddCommand(portForwardListCmd)
+	portForwardCmd.AddCommand(portForwardRemoveCmd)
+	rootCmd.AddCommand(portForwardCmd)
+}
+
+var portForwardCmd = &cobra.Command{
+	Use:   "port-forward",
+	Short: "Manage the ports of the host forwarded to the VM",
+	Long: `Manage the ports of the host forwarded to the CodeReady Containers VM, for instance to reach NodePort services or debug ports.
+The forwards are done by the crc daemon with the vsock network mode. They are lost when the daemon exits.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var portForwardAddCmd = &cobra.Command{
+	Use:   "add HOSTPORT:VMPORT",
+	Short: "Forward a port of the host to the VM",
+	Long:  "Forward the connections to HOSTPORT on the host to VMPORT in the VM",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPortForwardAdd(os.Stdout, newDaemonClient(), args[0])
+	},
+}
+
+var portForwardListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the ports of the host forwarded to the VM",
+	Long:  "List the ports of the host forwarded to the VM, including the ones needed by crc",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPortForwardList(os.Stdout, newDaemonClient(), outputFormat)
+	},
+}
+
+var portForwardRemoveCmd = &cobra.Command{
+	Use:   "remove HOSTPORT",
+	Short: "Stop forwarding a port of the host to the VM",
+	Long:  "Stop forwarding the connections to HOSTPORT on the host to the VM",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPortForwardRemove(os.Stdout, newDaemonClient(), args[0])
+	},
+}
+
+type portForwardClient interface {
+	PortForwards() ([]api.PortForward, error)
+	AddPortForward(forward api.PortForward) (api.PortForward, error)
+	RemovePortForward(hostPort int) error
+}
+
+func newDaemonClient() *apiClient.Client {
+	return apiClient.New(profile.DaemonSocketPath(profileName))
+}
+
+func runPortForwardAdd(writer io.Writer, client portForwardClient, arg string) error {
+	forward, err := parsePortForward(arg)
+	if err != nil {
+		return err
+	}
+	if _, err := client.AddPortForward(forward); err != nil {
+		return daemonError(err)
+	}
+	_, err = fmt.Fprintf(writer, "Forwarding port %d of the host to port %d of the VM\n", forward.HostPort, forward.VMPort)
+	return err
+}
+
+func runPortForwardRemove(writer io.Writer, client portForwardClient, arg string) error {
+	hostPort, err := parsePort(":" + arg)
+	if err != nil {
+		return fmt.Errorf("Invalid port '%s'", arg)
+	}
+	if err := client.RemovePortForward(hostPort); err != nil {
+		return daemonError(err)
+	}
+	_, err = fmt.Fprintf(writer, "Port %d of the host is no longer forwarded to the VM\n", hostPort)
+	return err
+}
+
+func runPortForwardList(writer io.Writer, client portForwardClient, outputFormat string) error {
+	forwards, err := client.PortForwards()
+	err = daemonError(err)
+	return render(&portForwardListResult{
+		Success:  err == nil,
+		Error:    crcErrors.ToSerializableError(err),
+		Forwards: toPortForwards(forwards),
+	}, writer, outputFormat)
+}
+
+// parsePortForward parses HOSTPORT:VMPORT
+func parsePortForward(arg string) (api.PortForward, error) {
+	ports := strings.Split(arg, ":")
+	if len(ports) != 2 {
+		return api.PortForward{}, fmt.Errorf("Invalid port forward '%s', the expected format is HOSTPORT:VMPORT", arg)
+	}
+	hostPort, err := strconv.Atoi(ports[0])
+	if err != nil {
+		return api.PortForward{}, fmt.Errorf("Invalid host port '%s'", ports[0])
+	}
+	vmPort, err := strconv.Atoi(ports[1])
+	if err != nil {
+		return api.PortForward{}, fmt.Errorf("Invalid VM port '%s'", ports[1])
+	}
+	return api.PortForward{HostPort: hostPort, VMPort: vmPort}, nil
+}
+
+// daemonError gives a hint when the daemon cannot be reached
+func daemonError(err error) error {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return fmt.Errorf("Cannot reach the crc daemon, make sure it is running: %v", err)
+	}
+	return err
+}
+
+type portForward struct {
+	HostPort int `json:"hostPort"`
+	VMPort   int `json:"vmPort"`
+}
+
+type portForwardListResult struct {
+	Success  bool                         `json:"success"`
+	Error    *crcErrors.SerializableError `json:"error,omitempty"`
+	Forwards []portForward                `json:"forwards"`
+}
+
+func toPortForwards(forwards []api.PortForward) []portForward {
+	ret := []portForward{}
+	for _, forward := range forwards {
+		ret = append(ret, portForward{HostPort: forward.HostPort, VMPort: forward.VMPort})
+	}
+	return ret
+}
+
+func (s *portForwardListResult) prettyPrintTo(writer io.Writer) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	w := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "HOST PORT\tVM PORT"); err != nil {
+		return err
+	}
+	for _, forward := range s.Forwards {
+		if _, err := fmt.Fprintf(w, "%d\t%d\n", forward.HostPort, forward.VMPort); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/code-ready/crc/pkg/crc/api"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePortForwardClient struct {
+	forwards []api.PortForward
+}
+
+func (c *fakePortForwardClient) PortForwards() ([]api.PortForward, error) {
+	return c.forwards, nil
+}
+
+func (c *fakePortForwardClient) AddPortForward(forward api.PortForward) (api.PortForward, error) {
+	c.forwards = append(c.forwards, forward)
+	return forward, nil
+}
+
+func (c *fakePortForwardClient) RemovePortForward(hostPort int) error {
+	for i, forward := range c.forwards {
+		if forward.HostPort == hostPort {
+			c.forwards = append(c.forwards[:i], c.forwards[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("Error from the daemon (404 Not Found): Cannot remove port 8080: Port is not forwarded")
+}
+
+func TestParsePortForward(t *testing.T) {
+	forward, err := parsePortForward("8080:30080")
+	assert.NoError(t, err)
+	assert.Equal(t, api.PortForward{HostPort: 8080, VMPort: 30080}, forward)
+
+	_, err = parsePortForward("8080")
+	assert.Error(t, err)
+	_, err = parsePortForward("http:30080")
+	assert.Error(t, err)
+}
+
+func TestPortForwardAddListRemove(t *testing.T) {
+	client := &fakePortForwardClient{
+		forwards: []api.PortForward{{HostPort: 443, VMPort: 443}},
+	}
+
+	out := new(bytes.Buffer)
+	assert.NoError(t, runPortForwardAdd(out, client, "8080:30080"))
+	assert.Equal(t, "Forwarding port 8080 of the host to port 30080 of the VM\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, runPortForwardList(out, client, ""))
+	assert.Equal(t, `HOST PORT  VM PORT
+443        443
+8080       30080
+`, out.String())
+
+	out.Reset()
+	assert.NoError(t, runPortForwardRemove(out, client, "8080"))
+	assert.Equal(t, "Port 8080 of the host is no longer forwarded to the VM\n", out.String())
+	assert.Error(t, runPortForwardRemove(out, client, "8080"))
+}
+
+func TestPortForwardListJSON(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runPortForwardList(out, &fakePortForwardClient{}, jsonFormat))
+	assert.JSONEq(t, `{"success": true, "forwards": []}`, out.String())
+}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	cmdConfig "github.com/code-ready/crc/cmd/crc/cmd/config"
+	crcConfig "github.com/code-ready/crc/pkg/crc/config"
+	crcErrors "github.com/code-ready/crc/pkg/crc/errors"
+	"github.com/code-ready/crc/pkg/crc/input"
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/code-ready/crc/pkg/crc/machine"
+	"github.com/code-ready/crc/pkg/crc/network"
+	"github.com/code-ready/crc/pkg/crc/profile"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addOutputFormatFlag(profileListCmd)
+	addForceFlag(profileDeleteCmd)
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileDeleteCmd)
+	rootCmd.AddCommand(profileCmd)
+}
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage the CodeReady Containers instances",
+	Long: `Manage the CodeReady Containers instances.
+Each profile has its own configuration and virtual machine, the profile to act on is selected with the --profile (-p) flag.
+Profiles are created by 'crc setup' and 'crc start', each one gets its own virtual network subnet in vsock network mode.
+Only one profile can be running at a time.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the profiles",
+	Long:  "List the profiles and the state of their virtual machine",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profiles, err := profile.List()
+		if err != nil {
+			return err
+		}
+		return runProfileList(cmd.Context(), os.Stdout, profiles, newProfileMachine, profileName, outputFormat)
+	},
+}
+
+var profileDeleteCmd = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Delete a profile",
+	Long:  "Delete the virtual machine and the configuration of a profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newProfileMachine(args[0])
+		if err != nil {
+			return err
+		}
+		return runProfileDelete(cmd.Context(), os.Stdout, client, globalForce)
+	},
+}
+
+// newProfileMachine returns the client of the VM of a profile, created with
+// the configuration of this profile
+func newProfileMachine(name string) (machine.Client, error) {
+	if name == profileName {
+		return newMachine(), nil
+	}
+	if err := profile.CheckExists(name); err != nil {
+		return nil, err
+	}
+	cfg, _, err := newViperConfigFromFile(profile.ConfigPath(name))
+	if err != nil {
+		return nil, err
+	}
+	return machine.NewClient(name, isDebugLog(), cfg), nil
+}
+
+// allocateProfileNetwork sets the virtual network of a new profile to a
+// subnet which is not used by the other profiles
+func allocateProfileNetwork(cfg *crcConfig.Config, name string) error {
+	profiles, err := profile.List()
+	if err != nil {
+		return err
+	}
+	var used []string
+	for _, other := range profiles {
+		if other == name {
+			continue
+		}
+		otherCfg, _, err := newViperConfigFromFile(profile.ConfigPath(other))
+		if err != nil {
+			return err
+		}
+		used = append(used, otherCfg.Get(cmdConfig.NetworkSubnet).AsString())
+	}
+	virtualNetwork, err := network.AllocateVirtualNetwork(used)
+	if err != nil {
+		return err
+	}
+	logging.Debugf("Using subnet %s for the virtual network of the '%s' profile", virtualNetwork.Subnet, name)
+	for _, setting := range []struct {
+		key   string
+		value string
+	}{
+		{cmdConfig.NetworkSubnet, virtualNetwork.Subnet},
+		{cmdConfig.NetworkGatewayIP, virtualNetwork.GatewayIP},
+		{cmdConfig.NetworkHostIP, virtualNetwork.HostIP},
+	} {
+		if _, err := cfg.Set(setting.key, setting.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type profileState struct {
+	Name    string `json:"name"`
+	State   string `json:"state"`
+	Current bool   `json:"current"`
+}
+
+type profileListResult struct {
+	Success  bool                         `json:"success"`
+	Error    *crcErrors.SerializableError `json:"error,omitempty"`
+	Profiles []profileState               `json:"profiles,omitempty"`
+}
+
+func runProfileList(ctx context.Context, writer io.Writer, profiles []string, newClient func(string) (machine.Client, error), current, outputFormat string) error {
+	result := &profileListResult{Success: true}
+	for _, name := range profiles {
+		client, err := newClient(name)
+		if err != nil {
+			result = &profileListResult{Success: false, Error: crcErrors.ToSerializableError(err)}
+			break
+		}
+		state, err := getProfileState(ctx, client)
+		if err != nil {
+			result = &profileListResult{Success: false, Error: crcErrors.ToSerializableError(err)}
+			break
+		}
+		result.Profiles = append(result.Profiles, profileState{
+			Name:    name,
+			State:   state,
+			Current: name == current,
+		})
+	}
+	return render(result, writer, outputFormat)
+}
+
+func getProfileState(ctx context.Context, client machine.Client) (string, error) {
+	exists, err := client.Exists(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "Does not exist", nil
+	}
+	running, err := client.IsRunning(ctx)
+	if err != nil {
+		return "", err
+	}
+	if running {
+		return "Running", nil
+	}
+	return "Stopped", nil
+}
+
+func (s *profileListResult) prettyPrintTo(writer io.Writer) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	w := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	for _, p := range s.Profiles {
+		marker := " "
+		if p.Current {
+			marker = "*"
+		}
+		if _, err := fmt.Fprintf(w, "%s %s\t%s\n", marker, p.Name, p.State); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func runProfileDelete(ctx context.Context, writer io.Writer, client machine.Client, force bool) error {
+	name := client.GetName()
+	if err := profile.Validate(name); err != nil {
+		return err
+	}
+	if name == profile.Default {
+		return fmt.Errorf("The default profile '%s' cannot be deleted, use 'crc delete' to delete its virtual machine", profile.Default)
+	}
+	if !input.PromptUserForYesOrNo(fmt.Sprintf("Do you want to delete the '%s' profile and its OpenShift cluster", name), force) {
+		return nil
+	}
+	exists, err := client.Exists(ctx)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := client.Delete(ctx); err != nil {
+			return err
+		}
+	}
+	if err := profile.Remove(name); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(writer, "Deleted the '%s' profile\n", name)
+	return err
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/code-ready/crc/pkg/crc/machine"
+	"github.com/code-ready/crc/pkg/crc/machine/fakemachine"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFakeProfileMachine(_ string) (machine.Client, error) {
+	return fakemachine.NewClient(), nil
+}
+
+func TestPlainProfileList(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runProfileList(context.Background(), out, []string{"crc", "okd"}, newFakeProfileMachine, "okd", ""))
+	assert.Equal(t, `  crc  Running
+* okd  Running
+`, out.String())
+}
+
+func TestJsonProfileList(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runProfileList(context.Background(), out, []string{"crc"}, newFakeProfileMachine, "crc", jsonFormat))
+	assert.JSONEq(t, `{
+  "success": true,
+  "profiles": [
+    {
+      "name": "crc",
+      "state": "Running",
+      "current": true
+    }
+  ]
+}`, out.String())
+}
+
+func TestJsonProfileListWithError(t *testing.T) {
+	out := new(bytes.Buffer)
+	newClient := func(name string) (machine.Client, error) {
+		return nil, fmt.Errorf("Profile '%s' does not exist", name)
+	}
+	assert.NoError(t, runProfileList(context.Background(), out, []string{"okd"}, newClient, "crc", jsonFormat))
+	assert.JSONEq(t, `{"success": false, "error": "Profile 'okd' does not exist"}`, out.String())
+}
+
+func TestDeleteDefaultProfile(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.Error(t, runProfileDelete(context.Background(), out, fakemachine.NewClient(), true))
+	assert.Empty(t, out.String())
+}
@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/code-ready/crc/pkg/crc/telemetry"
@@ -18,6 +21,7 @@ import (
 	"github.com/code-ready/crc/pkg/crc/machine"
 	"github.com/code-ready/crc/pkg/crc/network"
 	"github.com/code-ready/crc/pkg/crc/preflight"
+	"github.com/code-ready/crc/pkg/crc/profile"
 	"github.com/code-ready/crc/pkg/crc/segment"
 	"github.com/spf13/cobra"
 )
@@ -39,6 +43,7 @@ var rootCmd = &cobra.Command{
 
 var (
 	globalForce   bool
+	profileName   string
 	viper         *crcConfig.ViperStorage
 	config        *crcConfig.Config
 	segmentClient *segment.Client
@@ -62,6 +67,7 @@ func init() {
 	rootCmd.AddCommand(cmdConfig.GetConfigCmd(config))
 
 	rootCmd.PersistentFlags().StringVar(&logging.LogLevel, "log-level", constants.DefaultLogLevel, "log level (e.g. \"debug | info | warn | error\")")
+	rootCmd.PersistentFlags().StringVarP(&profileName, "profile", "p", profile.Default, "Name of the CodeReady Containers instance to act on")
 }
 
 func runPrerun(cmd *cobra.Command) error {
@@ -71,6 +77,9 @@ func runPrerun(cmd *cobra.Command) error {
 		logFile = constants.DaemonLogFilePath
 	}
 	logging.InitLogrus(logging.LogLevel, logFile)
+	if err := selectProfile(cmd); err != nil {
+		return err
+	}
 	if err := setProxyDefaults(); err != nil {
 		return err
 	}
@@ -81,6 +90,36 @@ func runPrerun(cmd *cobra.Command) error {
 	return nil
 }
 
+// selectProfile makes the commands act on the profile given with --profile.
+// Profiles are only created by setup and start, the other commands fail
+// when the profile does not exist so that a typo does not create a new one.
+func selectProfile(cmd *cobra.Command) error {
+	if err := profile.Validate(profileName); err != nil {
+		// -p used to be the shorthand of --pull-secret-file
+		if info, statErr := os.Stat(profileName); statErr == nil && !info.IsDir() {
+			return fmt.Errorf("%v\n-p selects the profile, use --pull-secret-file to give the pull secret file", err)
+		}
+		return err
+	}
+	if cmd != setupCmd && cmd != startCmd {
+		if err := profile.CheckExists(profileName); err != nil {
+			return err
+		}
+		viper.SetConfigFile(profile.ConfigPath(profileName))
+		return nil
+	}
+
+	created := !profile.Exists(profileName)
+	if err := profile.EnsureExists(profileName); err != nil {
+		return err
+	}
+	viper.SetConfigFile(profile.ConfigPath(profileName))
+	if created {
+		return allocateProfileNetwork(config, profileName)
+	}
+	return nil
+}
+
 func runPostrun() {
 	segmentClient.Close()
 	logging.CloseLogging()
@@ -95,14 +134,28 @@ func Execute() {
 
 	if err := rootCmd.ExecuteContext(telemetry.NewContext(context.Background())); err != nil {
 		runPostrun()
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		_, _ = fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
 	runPostrun()
 }
 
-func checkIfMachineMissing(client machine.Client) error {
-	exists, err := client.Exists()
+// exitCodeError makes crc exit with the code of a command it ran, without
+// printing an error as the command already reported it
+type exitCodeError struct {
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+func checkIfMachineMissing(ctx context.Context, client machine.Client) error {
+	exists, err := client.Exists(ctx)
 	if err != nil {
 		return err
 	}
@@ -153,7 +206,11 @@ func trimTrailingEOL(s string) string {
 }
 
 func newViperConfig() (*crcConfig.Config, *crcConfig.ViperStorage, error) {
-	viper, err := crcConfig.NewViperStorage(constants.ConfigPath, constants.CrcEnvPrefix)
+	return newViperConfigFromFile(constants.ConfigPath)
+}
+
+func newViperConfigFromFile(configFile string) (*crcConfig.Config, *crcConfig.ViperStorage, error) {
+	viper, err := crcConfig.NewViperStorage(configFile, constants.CrcEnvPrefix)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -164,7 +221,27 @@ func newViperConfig() (*crcConfig.Config, *crcConfig.ViperStorage, error) {
 }
 
 func newMachine() machine.Client {
-	return machine.NewClient(constants.DefaultName, isDebugLog(), config)
+	return machine.NewClient(profileName, isDebugLog(), config)
+}
+
+// cancelOnInterrupt returns a context which is cancelled when the process
+// receives an interrupt (Ctrl-C) or termination signal
+func cancelOnInterrupt(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			logging.Warn("Interrupted, cancelling the current operation...")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel()
+	}
 }
 
 func addForceFlag(cmd *cobra.Command) {
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	crcErrors "github.com/code-ready/crc/pkg/crc/errors"
+	"github.com/code-ready/crc/pkg/crc/input"
+	"github.com/code-ready/crc/pkg/crc/machine"
+	crcos "github.com/code-ready/crc/pkg/os"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addOutputFormatFlag(snapshotListCmd)
+	addForceFlag(snapshotRestoreCmd)
+	snapshotCmd.AddCommand(snapshotSaveCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	snapshotCmd.AddCommand(snapshotDeleteCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Manage snapshots of the OpenShift cluster",
+	Long: `Manage snapshots of the OpenShift cluster.
+Snapshots save the disk of the stopped virtual machine, the cluster must be stopped with 'crc stop' before using these commands.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var snapshotSaveCmd = &cobra.Command{
+	Use:   "save NAME",
+	Short: "Save a snapshot of the stopped OpenShift cluster",
+	Long:  "Save a snapshot of the stopped OpenShift cluster",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSnapshotSave(cmd.Context(), os.Stdout, newMachine(), args[0])
+	},
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore NAME",
+	Short: "Restore the OpenShift cluster to a snapshot",
+	Long:  "Restore the stopped OpenShift cluster to a snapshot, the changes made since the snapshot was saved are lost",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSnapshotRestore(cmd.Context(), os.Stdout, newMachine(), args[0], crcos.RunningInTerminal(), globalForce)
+	},
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the snapshots of the OpenShift cluster",
+	Long:  "List the snapshots of the OpenShift cluster",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSnapshotList(cmd.Context(), os.Stdout, newMachine(), outputFormat)
+	},
+}
+
+var snapshotDeleteCmd = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Delete a snapshot of the OpenShift cluster",
+	Long:  "Delete a snapshot of the OpenShift cluster",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSnapshotDelete(cmd.Context(), os.Stdout, newMachine(), args[0])
+	},
+}
+
+func runSnapshotSave(ctx context.Context, writer io.Writer, client machine.Client, name string) error {
+	if err := checkIfMachineMissing(ctx, client); err != nil {
+		return err
+	}
+	if err := client.SaveSnapshot(ctx, name); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(writer, "Saved snapshot '%s'\n", name)
+	return err
+}
+
+func runSnapshotRestore(ctx context.Context, writer io.Writer, client machine.Client, name string, interactive, force bool) error {
+	if err := checkIfMachineMissing(ctx, client); err != nil {
+		return err
+	}
+	if !interactive && !force {
+		return errors.New("non-interactive restore requires --force")
+	}
+	if !input.PromptUserForYesOrNo(fmt.Sprintf("Do you want to restore snapshot '%s' and lose the changes made since", name), force) {
+		return nil
+	}
+	if err := client.RestoreSnapshot(ctx, name); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(writer, "Restored snapshot '%s'\n", name)
+	return err
+}
+
+func runSnapshotDelete(ctx context.Context, writer io.Writer, client machine.Client, name string) error {
+	if err := checkIfMachineMissing(ctx, client); err != nil {
+		return err
+	}
+	if err := client.DeleteSnapshot(ctx, name); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(writer, "Deleted snapshot '%s'\n", name)
+	return err
+}
+
+type snapshotListResult struct {
+	Success   bool                         `json:"success"`
+	Error     *crcErrors.SerializableError `json:"error,omitempty"`
+	Snapshots []snapshot                   `json:"snapshots,omitempty"`
+}
+
+type snapshot struct {
+	Name       string `json:"name"`
+	CreatedAt  string `json:"createdAt"`
+	BundleName string `json:"bundleName"`
+}
+
+func runSnapshotList(ctx context.Context, writer io.Writer, client machine.Client, outputFormat string) error {
+	return render(getSnapshotList(ctx, client), writer, outputFormat)
+}
+
+func getSnapshotList(ctx context.Context, client machine.Client) *snapshotListResult {
+	if err := checkIfMachineMissing(ctx, client); err != nil {
+		return &snapshotListResult{Success: false, Error: crcErrors.ToSerializableError(err)}
+	}
+	snapshots, err := client.ListSnapshots(ctx)
+	if err != nil {
+		return &snapshotListResult{Success: false, Error: crcErrors.ToSerializableError(err)}
+	}
+	result := &snapshotListResult{Success: true}
+	for _, s := range snapshots {
+		result.Snapshots = append(result.Snapshots, snapshot{
+			Name:       s.Name,
+			CreatedAt:  s.CreatedAt.Format(time.RFC3339),
+			BundleName: s.BundleName,
+		})
+	}
+	return result
+}
+
+func (s *snapshotListResult) prettyPrintTo(writer io.Writer) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	if len(s.Snapshots) == 0 {
+		_, err := fmt.Fprintln(writer, "No snapshots")
+		return err
+	}
+	w := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "NAME\tCREATED\tBUNDLE"); err != nil {
+		return err
+	}
+	for _, snapshot := range s.Snapshots {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", snapshot.Name, snapshot.CreatedAt, snapshot.BundleName); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
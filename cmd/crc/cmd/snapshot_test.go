@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/code-ready/crc/pkg/crc/machine/fakemachine"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlainSnapshotList(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runSnapshotList(context.Background(), out, fakemachine.NewClient(), ""))
+	assert.Equal(t, `NAME   CREATED               BUNDLE
+clean  2020-12-01T10:00:00Z  crc_libvirt_4.6.6.crcbundle
+`, out.String())
+}
+
+func TestJsonSnapshotList(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runSnapshotList(context.Background(), out, fakemachine.NewClient(), jsonFormat))
+	assert.JSONEq(t, `{
+  "success": true,
+  "snapshots": [
+    {
+      "name": "clean",
+      "createdAt": "2020-12-01T10:00:00Z",
+      "bundleName": "crc_libvirt_4.6.6.crcbundle"
+    }
+  ]
+}`, out.String())
+}
+
+func TestJsonSnapshotListWithError(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runSnapshotList(context.Background(), out, fakemachine.NewFailingClient(), jsonFormat))
+	assert.JSONEq(t, `{
+  "success": false,
+  "error": "broken"
+}`, out.String())
+}
+
+func TestSnapshotSave(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runSnapshotSave(context.Background(), out, fakemachine.NewClient(), "clean"))
+	assert.Equal(t, "Saved snapshot 'clean'\n", out.String())
+
+	out.Reset()
+	assert.EqualError(t, runSnapshotSave(context.Background(), out, fakemachine.NewFailingClient(), "clean"), "snapshot failed")
+	assert.Empty(t, out.String())
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.EqualError(t, runSnapshotRestore(context.Background(), out, fakemachine.NewClient(), "clean", false, false), "non-interactive restore requires --force")
+	assert.Empty(t, out.String())
+
+	assert.NoError(t, runSnapshotRestore(context.Background(), out, fakemachine.NewClient(), "clean", false, true))
+	assert.Equal(t, "Restored snapshot 'clean'\n", out.String())
+}
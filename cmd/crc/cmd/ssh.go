@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/code-ready/crc/pkg/crc/machine"
+	crcssh "github.com/code-ready/crc/pkg/crc/ssh"
+	"github.com/code-ready/crc/pkg/os/shell"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(sshCmd)
+}
+
+var sshCmd = &cobra.Command{
+	Use:   "ssh [-- COMMAND...]",
+	Short: "Open a shell in the CodeReady Containers VM",
+	Long: `Open a shell in the CodeReady Containers VM, or run a command in it.
+The arguments are given to the command as they are, use 'sh -c' to run a shell command line.
+The exit code of the command is returned by crc.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSSH(cmd.Context(), newMachine(), args, os.Stdin, os.Stdout, os.Stderr)
+	},
+}
+
+func newSSHRunner(ctx context.Context, client machine.Client) (*crcssh.Runner, error) {
+	if err := checkIfMachineMissing(ctx, client); err != nil {
+		return nil, err
+	}
+	connectionDetails, err := client.ConnectionDetails(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return crcssh.CreateRunner(connectionDetails.IP, connectionDetails.SSHPort, connectionDetails.SSHKeys...)
+}
+
+func runSSH(ctx context.Context, client machine.Client, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	sshRunner, err := newSSHRunner(ctx, client)
+	if err != nil {
+		return err
+	}
+	defer sshRunner.Close()
+
+	exitCode, err := sshRunner.RunInteractive(commandLine(args), stdin, stdout, stderr)
+	if err != nil {
+		return fmt.Errorf("Cannot connect to the CodeReady Containers VM: %v", err)
+	}
+	if exitCode != 0 {
+		return &exitCodeError{code: exitCode}
+	}
+	return nil
+}
+
+// commandLine quotes the arguments so that the shell of the VM gives them
+// to the command as they are
+func commandLine(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		quoted = append(quoted, shell.Quote(arg))
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/code-ready/crc/pkg/crc/machine/fakemachine"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSHNotRunning(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.EqualError(t, runSSH(context.Background(), fakemachine.NewFailingClient(), []string{"uptime"}, strings.NewReader(""), out, out), "VM is not running")
+	assert.Empty(t, out.String())
+}
+
+func TestSSHCommandLine(t *testing.T) {
+	assert.Equal(t, "", commandLine(nil))
+	assert.Equal(t, `'sh' '-c' 'echo a b'`, commandLine([]string{"sh", "-c", "echo a b"}))
+	assert.Equal(t, `'cat' '/tmp/it'\''s here'`, commandLine([]string{"cat", "/tmp/it's here"}))
+}
@@ -7,14 +7,18 @@ import (
 	"io"
 	"os"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	cmdConfig "github.com/code-ready/crc/cmd/crc/cmd/config"
+	"github.com/code-ready/crc/pkg/crc/api"
 	"github.com/code-ready/crc/pkg/crc/cluster"
 	"github.com/code-ready/crc/pkg/crc/constants"
 	crcErrors "github.com/code-ready/crc/pkg/crc/errors"
 	"github.com/code-ready/crc/pkg/crc/logging"
 	"github.com/code-ready/crc/pkg/crc/machine"
 	"github.com/code-ready/crc/pkg/crc/preflight"
+	"github.com/code-ready/crc/pkg/crc/profile"
 	"github.com/code-ready/crc/pkg/crc/telemetry"
 	"github.com/code-ready/crc/pkg/crc/validation"
 	crcversion "github.com/code-ready/crc/pkg/crc/version"
@@ -28,32 +32,44 @@ func init() {
 
 	flagSet := pflag.NewFlagSet("start", pflag.ExitOnError)
 	flagSet.StringP(cmdConfig.Bundle, "b", constants.DefaultBundlePath, "The system bundle used for deployment of the OpenShift cluster")
-	flagSet.StringP(cmdConfig.PullSecretFile, "p", "", fmt.Sprintf("File path of image pull secret (download from %s)", constants.CrcLandingPageURL))
+	flagSet.String(cmdConfig.PullSecretFile, "", fmt.Sprintf("File path of image pull secret (download from %s)", constants.CrcLandingPageURL))
 	flagSet.IntP(cmdConfig.CPUs, "c", constants.DefaultCPUs, "Number of CPU cores to allocate to the OpenShift cluster")
 	flagSet.IntP(cmdConfig.Memory, "m", constants.DefaultMemory, "MiB of memory to allocate to the OpenShift cluster")
 	flagSet.UintP(cmdConfig.DiskSize, "d", constants.DefaultDiskSize, "Total size in GiB of the disk used by the OpenShift cluster")
 	flagSet.StringP(cmdConfig.NameServer, "n", "", "IPv4 address of nameserver to use for the OpenShift cluster")
 	flagSet.Bool(cmdConfig.DisableUpdateCheck, false, "Don't check for update")
+	flagSet.String(cmdConfig.Wait, constants.DefaultWait, fmt.Sprintf("Condition to wait for before reporting the cluster as started (%s or %s)", constants.WaitOperators, constants.WaitNone))
+	flagSet.String(cmdConfig.WaitTimeout, constants.DefaultWaitTimeout, "Maximum time to wait for the cluster to be ready")
 
 	startCmd.Flags().AddFlagSet(flagSet)
+	startCmd.Flags().BoolVar(&profileTiming, "profile-timing", false, "Show the time spent in each step of the start")
+	startCmd.Flags().StringVar(&fromStep, "from-step", "", fmt.Sprintf("Run the start again from this step, even if it was already completed (%s)", strings.Join(machine.StartSteps(), ", ")))
 }
 
+var (
+	fromStep      string
+	profileTiming bool
+)
+
 var startCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Start the OpenShift cluster",
-	Long:  "Start the OpenShift cluster",
+	Long: `Start the OpenShift cluster.
+The steps completed by a start which failed are skipped when running it again.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := viper.BindFlagSet(cmd.Flags()); err != nil {
 			return err
 		}
-		if err := renderStartResult(runStart(cmd.Context())); err != nil {
+		ctx, cancel := cancelOnInterrupt(cmd.Context())
+		defer cancel()
+		if err := renderStartResult(runStart(ctx, cmd.Flags())); err != nil {
 			return err
 		}
 		return nil
 	},
 }
 
-func runStart(ctx context.Context) (*machine.StartResult, error) {
+func runStart(ctx context.Context, flags *pflag.FlagSet) (*machine.StartResult, error) {
 	if err := validateStartFlags(); err != nil {
 		return nil, err
 	}
@@ -64,6 +80,8 @@ func runStart(ctx context.Context) (*machine.StartResult, error) {
 	telemetry.SetContextProperty(ctx, cmdConfig.Memory, uint64(config.Get(cmdConfig.Memory).AsInt())*1024*1024)
 	telemetry.SetContextProperty(ctx, cmdConfig.DiskSize, uint64(config.Get(cmdConfig.DiskSize).AsInt())*1024*1024*1024)
 
+	// already checked by validateStartFlags
+	waitTimeout, _ := time.ParseDuration(config.Get(cmdConfig.WaitTimeout).AsString())
 	startConfig := machine.StartConfig{
 		BundlePath: config.Get(cmdConfig.Bundle).AsString(),
 		Memory:     config.Get(cmdConfig.Memory).AsInt(),
@@ -71,18 +89,66 @@ func runStart(ctx context.Context) (*machine.StartResult, error) {
 		CPUs:       config.Get(cmdConfig.CPUs).AsInt(),
 		NameServer: config.Get(cmdConfig.NameServer).AsString(),
 		PullSecret: cluster.NewInteractivePullSecretLoader(config),
+
+		Wait:          config.Get(cmdConfig.Wait).AsString(),
+		WaitTimeout:   waitTimeout,
+		WaitOperators: cluster.ParseClusterOperators(config.Get(cmdConfig.WaitOperators).AsString()),
+
+		FromStep: fromStep,
 	}
 
 	client := newMachine()
-	isRunning, _ := client.IsRunning()
+	isRunning, _ := client.IsRunning(ctx)
 
 	if !isRunning {
+		if err := checkNoOtherProfileIsRunning(ctx, client.GetName()); err != nil {
+			return nil, err
+		}
 		if err := preflight.StartPreflightChecks(config); err != nil {
 			return nil, err
 		}
 	}
 
-	return client.Start(startConfig)
+	var result *machine.StartResult
+	var err error
+	pullSecretFile := config.Get(cmdConfig.PullSecretFile).AsString()
+	if daemonClient := newDaemonClient(); daemonCanStart(daemonClient, flags, pullSecretFile) {
+		result, err = startWithDaemon(ctx, daemonClient, api.StartArgs{
+			PullSecretFile: pullSecretFile,
+			FromStep:       fromStep,
+		})
+	} else {
+		result, err = client.Start(ctx, startConfig)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, timing := range result.Timings {
+		telemetry.SetContextProperty(ctx, fmt.Sprintf("%s-duration", timing.Step), timing.Duration.Milliseconds())
+	}
+	return result, nil
+}
+
+// checkNoOtherProfileIsRunning makes sure no other instance is running, as all
+// the profiles use the same hostnames and host ports for their cluster
+func checkNoOtherProfileIsRunning(ctx context.Context, name string) error {
+	profiles, err := profile.List()
+	if err != nil {
+		return err
+	}
+	for _, other := range profiles {
+		if other == name {
+			continue
+		}
+		client, err := newProfileMachine(other)
+		if err != nil {
+			return err
+		}
+		if running, _ := client.IsRunning(ctx); running {
+			return fmt.Errorf("The '%s' profile is already running, stop it with 'crc stop --profile %s' before starting '%s'", other, other, name)
+		}
+	}
+	return nil
 }
 
 func renderStartResult(result *machine.StartResult, err error) error {
@@ -90,9 +156,25 @@ func renderStartResult(result *machine.StartResult, err error) error {
 		Success:       err == nil,
 		Error:         crcErrors.ToSerializableError(err),
 		ClusterConfig: toClusterConfig(result),
+		Timings:       toStepTimings(result),
+		showTimings:   profileTiming,
 	}, os.Stdout, outputFormat)
 }
 
+func toStepTimings(result *machine.StartResult) []stepTiming {
+	if result == nil {
+		return nil
+	}
+	var timings []stepTiming
+	for _, timing := range result.Timings {
+		timings = append(timings, stepTiming{
+			Step:       timing.Step,
+			DurationMs: timing.Duration.Milliseconds(),
+		})
+	}
+	return timings
+}
+
 func toClusterConfig(result *machine.StartResult) *clusterConfig {
 	if result == nil {
 		return nil
@@ -125,10 +207,19 @@ type credentials struct {
 	Password string `json:"password"`
 }
 
+type stepTiming struct {
+	Step       string `json:"step"`
+	DurationMs int64  `json:"durationMs"`
+}
+
 type startResult struct {
 	Success       bool                         `json:"success"`
 	Error         *crcErrors.SerializableError `json:"error,omitempty"`
 	ClusterConfig *clusterConfig               `json:"clusterConfig,omitempty"`
+	Timings       []stepTiming                 `json:"timings,omitempty"`
+
+	// showTimings adds the timings table to the plain output
+	showTimings bool
 }
 
 func (s *startResult) prettyPrintTo(writer io.Writer) error {
@@ -160,7 +251,32 @@ func (s *startResult) prettyPrintTo(writer io.Writer) error {
 			"This cluster was built from OKD - The Community Distribution of Kubernetes that powers Red Hat OpenShift.",
 			"If you find an issue, please report it at https://github.com/openshift/okd"}, "\n"))
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	if s.showTimings {
+		return printStepTimings(writer, s.Timings)
+	}
+	return nil
+}
+
+func printStepTimings(writer io.Writer, timings []stepTiming) error {
+	w := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "\nSTEP\tDURATION"); err != nil {
+		return err
+	}
+	var total time.Duration
+	for _, timing := range timings {
+		duration := time.Duration(timing.DurationMs) * time.Millisecond
+		total += duration
+		if _, err := fmt.Fprintf(w, "%s\t%s\n", timing.Step, duration); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "total\t%s\n", total); err != nil {
+		return err
+	}
+	return w.Flush()
 }
 
 func isDebugLog() bool {
@@ -180,6 +296,12 @@ func validateStartFlags() error {
 	if err := validation.ValidateBundle(config.Get(cmdConfig.Bundle).AsString()); err != nil {
 		return err
 	}
+	if err := validation.ValidateWaitCondition(config.Get(cmdConfig.Wait).AsString()); err != nil {
+		return err
+	}
+	if err := validation.ValidateTimeout(config.Get(cmdConfig.WaitTimeout).AsString()); err != nil {
+		return err
+	}
 	if config.Get(cmdConfig.NameServer).AsString() != "" {
 		if err := validation.ValidateIPAddress(config.Get(cmdConfig.NameServer).AsString()); err != nil {
 			return err
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/code-ready/crc/pkg/crc/api"
+	"github.com/code-ready/crc/pkg/crc/cluster"
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/code-ready/crc/pkg/crc/machine"
+	"github.com/code-ready/crc/pkg/crc/profile"
+	crcversion "github.com/code-ready/crc/pkg/crc/version"
+	"github.com/code-ready/machine/libmachine/state"
+	"github.com/spf13/pflag"
+)
+
+const daemonOperationPollInterval = time.Second
+
+type startDaemonClient interface {
+	Version() (api.VersionResult, error)
+	Start(args api.StartArgs) (api.Operation, error)
+	Cancel(id string) (api.Operation, error)
+	WaitForOperation(ctx context.Context, id string, interval time.Duration) (api.Operation, error)
+	Events(ctx context.Context, handler func(api.Event)) error
+}
+
+// daemonCanStart returns true when the cluster can be started by the daemon
+// installed as a systemd user service by crc setup. The daemon only knows
+// about the configuration file, the start flags are not passed to it, and it
+// cannot ask for the pull secret.
+func daemonCanStart(client startDaemonClient, flags *pflag.FlagSet, pullSecretFile string) bool {
+	if runtime.GOOS != "linux" || profileName != profile.Default || profileTiming {
+		return false
+	}
+	for _, name := range api.StartConfigSettings {
+		if flag := flags.Lookup(name); flag != nil && flag.Changed {
+			logging.Debugf("Not starting the cluster with the daemon, the --%s flag is set", name)
+			return false
+		}
+	}
+	if _, err := cluster.NewNonInteractivePullSecretLoader(config, pullSecretFile).Value(); err != nil {
+		logging.Debugf("Not starting the cluster with the daemon, the pull secret is not available: %v", err)
+		return false
+	}
+	return daemonIsAvailable(client)
+}
+
+// daemonIsAvailable returns true when the daemon answers and runs the same crc version
+func daemonIsAvailable(client startDaemonClient) bool {
+	version, err := client.Version()
+	if err != nil {
+		logging.Debugf("The crc daemon is not available: %v", err)
+		return false
+	}
+	if version.CrcVersion != crcversion.GetCRCVersion() || version.CommitSha != crcversion.GetCommitSha() {
+		logging.Debugf("The crc daemon runs version %s+%s, not starting the cluster with it", version.CrcVersion, version.CommitSha)
+		return false
+	}
+	return true
+}
+
+// startWithDaemon starts the cluster with the daemon and displays its progress
+// until it is done. The start is cancelled when ctx is cancelled.
+func startWithDaemon(ctx context.Context, client startDaemonClient, args api.StartArgs) (*machine.StartResult, error) {
+	logging.Info("Starting the OpenShift cluster with the crc daemon")
+
+	eventsCtx, stopEvents := context.WithCancel(ctx)
+	defer stopEvents()
+	go func() {
+		err := client.Events(eventsCtx, func(event api.Event) {
+			if event.Type != api.StartProgressEvent {
+				return
+			}
+			var progress api.StartProgress
+			if err := json.Unmarshal(event.Data, &progress); err == nil {
+				logging.Info(progress.Message)
+			}
+		})
+		if err != nil {
+			logging.Debugf("Cannot display the start progress: %v", err)
+		}
+	}()
+
+	op, err := client.Start(args)
+	if err != nil {
+		return nil, daemonError(err)
+	}
+	op, err = client.WaitForOperation(ctx, op.ID, daemonOperationPollInterval)
+	if err != nil {
+		if ctx.Err() != nil {
+			if _, cancelErr := client.Cancel(op.ID); cancelErr != nil {
+				logging.Debugf("Cannot cancel the start operation: %v", cancelErr)
+			}
+		}
+		return nil, daemonError(err)
+	}
+	if !op.Success {
+		return nil, errors.New(op.Error)
+	}
+
+	var result api.StartResult
+	if err := json.Unmarshal(op.Result, &result); err != nil {
+		return nil, fmt.Errorf("Unexpected start result from the daemon: %v", err)
+	}
+	status := state.None
+	if result.Status == state.Running.String() {
+		status = state.Running
+	}
+	return &machine.StartResult{
+		Status:         status,
+		ClusterConfig:  result.ClusterConfig,
+		KubeletStarted: result.KubeletStarted,
+		Timings:        result.Timings,
+	}, nil
+}
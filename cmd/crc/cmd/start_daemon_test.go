@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/code-ready/crc/pkg/crc/api"
+	"github.com/code-ready/crc/pkg/crc/machine"
+	crcversion "github.com/code-ready/crc/pkg/crc/version"
+	"github.com/code-ready/machine/libmachine/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStartDaemonClient struct {
+	version   api.VersionResult
+	result    api.Operation
+	args      api.StartArgs
+	cancelled bool
+}
+
+func (c *fakeStartDaemonClient) Version() (api.VersionResult, error) {
+	return c.version, nil
+}
+
+func (c *fakeStartDaemonClient) Start(args api.StartArgs) (api.Operation, error) {
+	c.args = args
+	return api.Operation{ID: "1", Command: "start", State: api.OperationQueued}, nil
+}
+
+func (c *fakeStartDaemonClient) Cancel(id string) (api.Operation, error) {
+	c.cancelled = true
+	return api.Operation{ID: id}, nil
+}
+
+func (c *fakeStartDaemonClient) WaitForOperation(ctx context.Context, id string, interval time.Duration) (api.Operation, error) {
+	if ctx.Err() != nil {
+		return api.Operation{ID: id, State: api.OperationRunning}, ctx.Err()
+	}
+	return c.result, nil
+}
+
+func (c *fakeStartDaemonClient) Events(ctx context.Context, handler func(api.Event)) error {
+	return nil
+}
+
+func TestStartWithDaemon(t *testing.T) {
+	result, err := json.Marshal(api.StartResult{
+		Name:   "crc",
+		Status: "Running",
+		ClusterConfig: machine.ClusterConfig{
+			ClusterAPI:    "https://api.crc.testing:6443",
+			KubeAdminPass: "secret",
+		},
+		KubeletStarted: true,
+		Timings: []machine.StepTiming{
+			{Step: "start-vm", Duration: 40 * time.Second},
+		},
+	})
+	require.NoError(t, err)
+	client := &fakeStartDaemonClient{
+		result: api.Operation{ID: "1", State: api.OperationFinished, Success: true, Result: result},
+	}
+
+	res, err := startWithDaemon(context.Background(), client, api.StartArgs{FromStep: "wait-for-cluster"})
+	assert.NoError(t, err)
+	assert.Equal(t, api.StartArgs{FromStep: "wait-for-cluster"}, client.args)
+	assert.Equal(t, &machine.StartResult{
+		Status: state.Running,
+		ClusterConfig: machine.ClusterConfig{
+			ClusterAPI:    "https://api.crc.testing:6443",
+			KubeAdminPass: "secret",
+		},
+		KubeletStarted: true,
+		Timings: []machine.StepTiming{
+			{Step: "start-vm", Duration: 40 * time.Second},
+		},
+	}, res)
+}
+
+func TestStartWithDaemonFailure(t *testing.T) {
+	client := &fakeStartDaemonClient{
+		result: api.Operation{ID: "1", State: api.OperationFinished, Error: "Preflight checks failed"},
+	}
+	_, err := startWithDaemon(context.Background(), client, api.StartArgs{})
+	assert.EqualError(t, err, "Preflight checks failed")
+}
+
+func TestStartWithDaemonCancelled(t *testing.T) {
+	client := &fakeStartDaemonClient{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := startWithDaemon(ctx, client, api.StartArgs{})
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, client.cancelled)
+}
+
+func TestDaemonIsAvailable(t *testing.T) {
+	client := &fakeStartDaemonClient{
+		version: api.VersionResult{
+			CrcVersion: crcversion.GetCRCVersion(),
+			CommitSha:  crcversion.GetCommitSha(),
+		},
+	}
+	assert.True(t, daemonIsAvailable(client))
+
+	client.version.CrcVersion = "1.0.0"
+	assert.False(t, daemonIsAvailable(client))
+}
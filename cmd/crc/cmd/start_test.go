@@ -91,3 +91,39 @@ func TestRenderActionJSONFailure(t *testing.T) {
 	}, out, jsonFormat))
 	assert.JSONEq(t, `{"success": false, "error": "broken"}`, out.String())
 }
+
+func TestRenderActionPlainTimings(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, printStepTimings(out, []stepTiming{
+		{Step: "wait-for-ssh", DurationMs: 12500},
+		{Step: "wait-for-cluster", DurationMs: 90000},
+	}))
+	assert.Equal(t, `
+STEP              DURATION
+wait-for-ssh      12.5s
+wait-for-cluster  1m30s
+total             1m42.5s
+`, out.String())
+}
+
+func TestRenderActionJSONTimings(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, render(&startResult{
+		Success:       true,
+		ClusterConfig: &clusterConfig{},
+		Timings: []stepTiming{
+			{Step: "wait-for-ssh", DurationMs: 12500},
+		},
+	}, out, jsonFormat))
+	assert.JSONEq(t, `{
+  "success": true,
+  "clusterConfig": {
+    "cacert": "",
+    "webConsoleUrl": "",
+    "url": "",
+    "adminCredentials": {"username": "", "password": ""},
+    "developerCredentials": {"username": "", "password": ""}
+  },
+  "timings": [{"step": "wait-for-ssh", "durationMs": 12500}]
+}`, out.String())
+}
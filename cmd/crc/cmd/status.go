@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"text/tabwriter"
+	"time"
 
 	"github.com/code-ready/crc/pkg/crc/constants"
 	crcErrors "github.com/code-ready/crc/pkg/crc/errors"
@@ -14,8 +16,14 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// statusWatchInterval is the time between two refreshes of crc status --watch
+const statusWatchInterval = 5 * time.Second
+
+var watchStatus bool
+
 func init() {
 	addOutputFormatFlag(statusCmd)
+	statusCmd.Flags().BoolVarP(&watchStatus, "watch", "w", false, "Refresh the status until interrupted")
 	rootCmd.AddCommand(statusCmd)
 }
 
@@ -24,7 +32,12 @@ var statusCmd = &cobra.Command{
 	Short: "Display status of the OpenShift cluster",
 	Long:  "Show details about the OpenShift cluster",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runStatus(os.Stdout, newMachine(), constants.MachineCacheDir, outputFormat)
+		if watchStatus {
+			ctx, cancel := cancelOnInterrupt(cmd.Context())
+			defer cancel()
+			return runStatusWatch(ctx, os.Stdout, newMachine(), constants.MachineCacheDir, outputFormat, statusWatchInterval)
+		}
+		return runStatus(cmd.Context(), os.Stdout, newMachine(), constants.MachineCacheDir, outputFormat)
 	},
 }
 
@@ -38,19 +51,68 @@ type status struct {
 	DiskSize         int64                        `json:"diskSize,omitempty"`
 	CacheUsage       int64                        `json:"cacheUsage,omitempty"`
 	CacheDir         string                       `json:"cacheDir,omitempty"`
+	ExpiresAt        string                       `json:"expiresAt,omitempty"`
+	ExpiryWarning    string                       `json:"expiryWarning,omitempty"`
+	CPUs             int                          `json:"cpus,omitempty"`
+	CPUUsage         float64                      `json:"cpuUsage,omitempty"`
+	MemoryUsage      int64                        `json:"memoryUsage,omitempty"`
+	MemorySize       int64                        `json:"memorySize,omitempty"`
+	UptimeSeconds    int64                        `json:"uptimeSeconds,omitempty"`
+	CertsExpiry      *certsExpiry                 `json:"certsExpiry,omitempty"`
+	Operators        []operatorStatus             `json:"operators,omitempty"`
+}
+
+type certsExpiry struct {
+	KubeletClient string `json:"kubeletClient"`
+	KubeletServer string `json:"kubeletServer"`
 }
 
-func runStatus(writer io.Writer, client machine.Client, cacheDir, outputFormat string) error {
-	status := getStatus(client, cacheDir)
+type operatorStatus struct {
+	Name        string `json:"name"`
+	Available   bool   `json:"available"`
+	Progressing bool   `json:"progressing"`
+	Degraded    bool   `json:"degraded"`
+	Disabled    bool   `json:"disabled"`
+}
+
+func runStatus(ctx context.Context, writer io.Writer, client machine.Client, cacheDir, outputFormat string) error {
+	status := getStatus(ctx, client, cacheDir)
 	return render(status, writer, outputFormat)
 }
 
-func getStatus(client machine.Client, cacheDir string) *status {
-	if err := checkIfMachineMissing(client); err != nil {
+// runStatusWatch prints the status every interval until ctx is cancelled.
+// The screen is cleared before each refresh of the plain output.
+func runStatusWatch(ctx context.Context, writer io.Writer, client machine.Client, cacheDir, outputFormat string, interval time.Duration) error {
+	for {
+		status := getStatus(ctx, client, cacheDir)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if outputFormat != jsonFormat {
+			if _, err := fmt.Fprint(writer, "\033[H\033[2J"); err != nil {
+				return err
+			}
+		}
+		if err := render(status, writer, outputFormat); err != nil {
+			// errors are part of the status, they may be transient
+			if _, err := fmt.Fprintf(writer, "Error: %v\n", err); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+func getStatus(ctx context.Context, client machine.Client, cacheDir string) *status {
+	if err := checkIfMachineMissing(ctx, client); err != nil {
 		return &status{Success: false, Error: crcErrors.ToSerializableError(err)}
 	}
 
-	clusterStatus, err := client.Status()
+	clusterStatus, err := client.Status(ctx)
 	if err != nil {
 		return &status{Success: false, Error: crcErrors.ToSerializableError(err)}
 	}
@@ -65,7 +127,7 @@ func getStatus(client machine.Client, cacheDir string) *status {
 		return &status{Success: false, Error: crcErrors.ToSerializableError(err)}
 	}
 
-	return &status{
+	result := &status{
 		Success:          true,
 		CrcStatus:        clusterStatus.CrcStatus.String(),
 		OpenShiftStatus:  clusterStatus.OpenshiftStatus,
@@ -75,6 +137,38 @@ func getStatus(client machine.Client, cacheDir string) *status {
 		CacheUsage:       size,
 		CacheDir:         cacheDir,
 	}
+	// the cluster cannot be started anymore once the bundle certificates cannot be renewed
+	if expiry := clusterStatus.BundleExpiry; expiry != nil {
+		result.ExpiresAt = expiry.ExpiresAt.Format(time.RFC3339)
+		result.ExpiryWarning = expiry.Warning
+	}
+	if stats := clusterStatus.VMStats; stats != nil {
+		result.CPUs = stats.CPUs
+		result.CPUUsage = stats.CPUUsage
+		result.MemoryUsage = stats.MemoryUsage
+		result.MemorySize = stats.MemorySize
+		result.UptimeSeconds = int64(stats.Uptime / time.Second)
+	}
+	if expiry := clusterStatus.CertsExpiry; expiry != nil {
+		result.CertsExpiry = &certsExpiry{
+			KubeletClient: expiry.KubeletClient.Format(time.RFC3339),
+			KubeletServer: expiry.KubeletServer.Format(time.RFC3339),
+		}
+	}
+	for _, operator := range clusterStatus.Operators {
+		result.Operators = append(result.Operators, operatorStatus{
+			Name:        operator.Name,
+			Available:   operator.Available,
+			Progressing: operator.Progressing,
+			Degraded:    operator.Degraded,
+			Disabled:    operator.Disabled,
+		})
+	}
+	return result
+}
+
+type statusLine struct {
+	left, right string
 }
 
 func (s *status) prettyPrintTo(writer io.Writer) error {
@@ -83,23 +177,68 @@ func (s *status) prettyPrintTo(writer io.Writer) error {
 	}
 	w := tabwriter.NewWriter(writer, 0, 0, 1, ' ', 0)
 
-	lines := []struct {
-		left, right string
-	}{
+	lines := []statusLine{
 		{"CRC VM", s.CrcStatus},
 		{"OpenShift", openshiftStatus(s)},
-		{"Disk Usage", fmt.Sprintf(
+	}
+	if s.UptimeSeconds > 0 {
+		lines = append(lines, statusLine{"Uptime", (time.Duration(s.UptimeSeconds) * time.Second).String()})
+	}
+	if s.CPUs > 0 {
+		lines = append(lines,
+			statusLine{"CPU Usage", fmt.Sprintf("%.1f%% of %d CPUs", s.CPUUsage, s.CPUs)},
+			statusLine{"Memory Usage", fmt.Sprintf(
+				"%s of %s",
+				units.HumanSize(float64(s.MemoryUsage)),
+				units.HumanSize(float64(s.MemorySize)))},
+		)
+	}
+	lines = append(lines,
+		statusLine{"Disk Usage", fmt.Sprintf(
 			"%s of %s (Inside the CRC VM)",
 			units.HumanSize(float64(s.DiskUsage)),
 			units.HumanSize(float64(s.DiskSize)))},
-		{"Cache Usage", units.HumanSize(float64(s.CacheUsage))},
-		{"Cache Directory", s.CacheDir},
+		statusLine{"Cache Usage", units.HumanSize(float64(s.CacheUsage))},
+		statusLine{"Cache Directory", s.CacheDir},
+	)
+	if s.CertsExpiry != nil {
+		lines = append(lines,
+			statusLine{"Kubelet Client Cert", fmt.Sprintf("Expires %s", s.CertsExpiry.KubeletClient)},
+			statusLine{"Kubelet Server Cert", fmt.Sprintf("Expires %s", s.CertsExpiry.KubeletServer)},
+		)
+	}
+	if s.ExpiresAt != "" {
+		lines = append(lines, statusLine{"Bundle Certs", fmt.Sprintf("Renewable until %s", s.ExpiresAt)})
 	}
 	for _, line := range lines {
 		if err := printLine(w, line.left, line.right); err != nil {
 			return err
 		}
 	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if s.ExpiryWarning != "" {
+		if _, err := fmt.Fprintf(writer, "\nWARNING: %s\n", s.ExpiryWarning); err != nil {
+			return err
+		}
+	}
+	return printOperators(writer, s.Operators)
+}
+
+func printOperators(writer io.Writer, operators []operatorStatus) error {
+	if len(operators) == 0 {
+		return nil
+	}
+	w := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "\nOPERATOR\tAVAILABLE\tPROGRESSING\tDEGRADED"); err != nil {
+		return err
+	}
+	for _, operator := range operators {
+		if _, err := fmt.Fprintf(w, "%s\t%t\t%t\t%t\n", operator.Name, operator.Available, operator.Progressing, operator.Degraded); err != nil {
+			return err
+		}
+	}
 	return w.Flush()
 }
 
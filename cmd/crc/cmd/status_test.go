@@ -2,12 +2,14 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/code-ready/crc/pkg/crc/machine/fakemachine"
 
@@ -23,13 +25,25 @@ func TestPlainStatus(t *testing.T) {
 	require.NoError(t, ioutil.WriteFile(filepath.Join(cacheDir, "crc.qcow2"), make([]byte, 10000), 0600))
 
 	out := new(bytes.Buffer)
-	assert.NoError(t, runStatus(out, fakemachine.NewClient(), cacheDir, ""))
-
-	expected := `CRC VM:          Running
-OpenShift:       Running (v4.5.1)
-Disk Usage:      10GB of 20GB (Inside the CRC VM)
-Cache Usage:     10kB
-Cache Directory: %s
+	assert.NoError(t, runStatus(context.Background(), out, fakemachine.NewClient(), cacheDir, ""))
+
+	expected := `CRC VM:              Running
+OpenShift:           Running (v4.5.1)
+Uptime:              2h3m0s
+CPU Usage:           12.5%% of 4 CPUs
+Memory Usage:        6GB of 9GB
+Disk Usage:          10GB of 20GB (Inside the CRC VM)
+Cache Usage:         10kB
+Cache Directory:     %s
+Kubelet Client Cert: Expires 2021-03-01T10:00:00Z
+Kubelet Server Cert: Expires 2021-03-01T10:05:00Z
+Bundle Certs:        Renewable until 2021-10-26T04:48:26Z
+
+WARNING: The certificates of the bundle expire in 10 days
+
+OPERATOR        AVAILABLE  PROGRESSING  DEGRADED
+authentication  true       false        false
+console         true       false        false
 `
 	assert.Equal(t, fmt.Sprintf(expected, cacheDir), out.String())
 }
@@ -42,7 +56,7 @@ func TestJsonStatus(t *testing.T) {
 	require.NoError(t, ioutil.WriteFile(filepath.Join(cacheDir, "crc.qcow2"), make([]byte, 10000), 0600))
 
 	out := new(bytes.Buffer)
-	assert.NoError(t, runStatus(out, fakemachine.NewClient(), cacheDir, jsonFormat))
+	assert.NoError(t, runStatus(context.Background(), out, fakemachine.NewClient(), cacheDir, jsonFormat))
 
 	expected := `{
   "success": true,
@@ -52,7 +66,34 @@ func TestJsonStatus(t *testing.T) {
   "diskUsage": 10000000000,
   "diskSize": 20000000000,
   "cacheUsage": 10000,
-  "cacheDir": "%s"
+  "cacheDir": "%s",
+  "expiresAt": "2021-10-26T04:48:26Z",
+  "expiryWarning": "The certificates of the bundle expire in 10 days",
+  "cpus": 4,
+  "cpuUsage": 12.5,
+  "memoryUsage": 6000000000,
+  "memorySize": 9000000000,
+  "uptimeSeconds": 7380,
+  "certsExpiry": {
+    "kubeletClient": "2021-03-01T10:00:00Z",
+    "kubeletServer": "2021-03-01T10:05:00Z"
+  },
+  "operators": [
+    {
+      "name": "authentication",
+      "available": true,
+      "progressing": false,
+      "degraded": false,
+      "disabled": false
+    },
+    {
+      "name": "console",
+      "available": true,
+      "progressing": false,
+      "degraded": false,
+      "disabled": false
+    }
+  ]
 }
 `
 	assert.Equal(t, fmt.Sprintf(expected, strings.ReplaceAll(cacheDir, `\`, `\\`)), out.String())
@@ -66,7 +107,7 @@ func TestPlainStatusWithError(t *testing.T) {
 	require.NoError(t, ioutil.WriteFile(filepath.Join(cacheDir, "crc.qcow2"), make([]byte, 10000), 0600))
 
 	out := new(bytes.Buffer)
-	assert.EqualError(t, runStatus(out, fakemachine.NewFailingClient(), cacheDir, ""), "broken")
+	assert.EqualError(t, runStatus(context.Background(), out, fakemachine.NewFailingClient(), cacheDir, ""), "broken")
 	assert.Equal(t, "", out.String())
 }
 
@@ -78,7 +119,7 @@ func TestJsonStatusWithError(t *testing.T) {
 	require.NoError(t, ioutil.WriteFile(filepath.Join(cacheDir, "crc.qcow2"), make([]byte, 10000), 0600))
 
 	out := new(bytes.Buffer)
-	assert.NoError(t, runStatus(out, fakemachine.NewFailingClient(), cacheDir, jsonFormat))
+	assert.NoError(t, runStatus(context.Background(), out, fakemachine.NewFailingClient(), cacheDir, jsonFormat))
 
 	expected := `{
   "success": false,
@@ -87,3 +128,29 @@ func TestJsonStatusWithError(t *testing.T) {
 `
 	assert.Equal(t, expected, out.String())
 }
+
+func TestStatusWatch(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	out := new(bytes.Buffer)
+	assert.NoError(t, runStatusWatch(ctx, out, fakemachine.NewClient(), cacheDir, "", time.Millisecond))
+	refreshes := strings.Count(out.String(), "\033[H\033[2J")
+	assert.Greater(t, refreshes, 1)
+	assert.Equal(t, refreshes, strings.Count(out.String(), "CRC VM:"))
+}
+
+func TestStatusWatchWithError(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	out := new(bytes.Buffer)
+	assert.NoError(t, runStatusWatch(ctx, out, fakemachine.NewFailingClient(), cacheDir, "", time.Millisecond))
+	assert.Empty(t, out.String())
+}
@@ -1,13 +1,18 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"time"
 
+	cmdConfig "github.com/code-ready/crc/cmd/crc/cmd/config"
+	"github.com/code-ready/crc/pkg/crc/constants"
 	crcErrors "github.com/code-ready/crc/pkg/crc/errors"
 	"github.com/code-ready/crc/pkg/crc/input"
 	"github.com/code-ready/crc/pkg/crc/machine"
+	"github.com/code-ready/crc/pkg/crc/validation"
 	"github.com/code-ready/machine/libmachine/state"
 	"github.com/spf13/cobra"
 )
@@ -15,47 +20,54 @@ import (
 func init() {
 	addOutputFormatFlag(stopCmd)
 	addForceFlag(stopCmd)
+	stopCmd.Flags().String(cmdConfig.StopTimeout, constants.DefaultStopTimeout, "Time given to the OpenShift cluster to shut down before powering it off")
 	rootCmd.AddCommand(stopCmd)
 }
 
 var stopCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "Stop the OpenShift cluster",
-	Long:  "Stop the OpenShift cluster",
+	Long: `Stop the OpenShift cluster.
+The kubelet and crio are stopped before shutting down the virtual machine, which is powered off if it does not shut down in time.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runStop(os.Stdout, newMachine(), outputFormat != jsonFormat, globalForce, outputFormat)
+		if err := viper.BindFlagSet(cmd.Flags()); err != nil {
+			return err
+		}
+		if err := validation.ValidateTimeout(config.Get(cmdConfig.StopTimeout).AsString()); err != nil {
+			return fmt.Errorf("Invalid %s: %v", cmdConfig.StopTimeout, err)
+		}
+		timeout, _ := time.ParseDuration(config.Get(cmdConfig.StopTimeout).AsString())
+		return runStop(cmd.Context(), os.Stdout, newMachine(), timeout, outputFormat != jsonFormat, globalForce, outputFormat)
 	},
 }
 
-func stopMachine(client machine.Client, interactive, force bool) (bool, error) {
-	if err := checkIfMachineMissing(client); err != nil {
+func stopMachine(ctx context.Context, client machine.Client, timeout time.Duration, interactive, force bool) (bool, error) {
+	if err := checkIfMachineMissing(ctx, client); err != nil {
 		return false, err
 	}
 
-	vmState, err := client.Stop()
+	result, err := client.Stop(ctx, machine.StopConfig{Timeout: timeout})
 	if err != nil {
 		if !interactive && !force {
 			return false, err
 		}
-		// Here we are checking the VM state and if it is still running then
-		// Ask user to forcefully power off it.
-		if vmState == state.Running {
-			// Most of the time force kill don't work and libvirt throw
-			// Device or resource busy error. To make sure we give some
-			// graceful time to cluster before kill it.
+		// The VM is powered off when it does not shut down in time, it can
+		// still be running if that failed or if stopping was interrupted.
+		// Ask the user whether to try powering it off again.
+		if result != nil && result.State == state.Running {
 			yes := input.PromptUserForYesOrNo("Do you want to force power off", force)
 			if yes {
-				err := client.PowerOff()
+				err := client.PowerOff(ctx)
 				return true, err
 			}
 		}
 		return false, err
 	}
-	return false, nil
+	return result.Forced, nil
 }
 
-func runStop(writer io.Writer, client machine.Client, interactive, force bool, outputFormat string) error {
-	forced, err := stopMachine(client, interactive, force)
+func runStop(ctx context.Context, writer io.Writer, client machine.Client, timeout time.Duration, interactive, force bool, outputFormat string) error {
+	forced, err := stopMachine(ctx, client, timeout, interactive, force)
 	return render(&stopResult{
 		Success: err == nil,
 		Forced:  forced,
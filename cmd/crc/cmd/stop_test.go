@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"testing"
+	"time"
 
 	"github.com/code-ready/crc/pkg/crc/machine/fakemachine"
 	"github.com/stretchr/testify/assert"
@@ -10,34 +12,34 @@ import (
 
 func TestStopPlainSuccess(t *testing.T) {
 	out := new(bytes.Buffer)
-	assert.NoError(t, runStop(out, fakemachine.NewClient(), true, false, ""))
+	assert.NoError(t, runStop(context.Background(), out, fakemachine.NewClient(), time.Minute, true, false, ""))
 	assert.Equal(t, "Stopped the OpenShift cluster\n", out.String())
 }
 
 func TestStopPlainError(t *testing.T) {
 	out := new(bytes.Buffer)
-	assert.EqualError(t, runStop(out, fakemachine.NewFailingClient(), true, false, ""), "stop failed")
+	assert.EqualError(t, runStop(context.Background(), out, fakemachine.NewFailingClient(), time.Minute, true, false, ""), "stop failed")
 }
 
 func TestStopWithForcePlainError(t *testing.T) {
 	out := new(bytes.Buffer)
-	assert.EqualError(t, runStop(out, fakemachine.NewFailingClient(), true, true, ""), "poweroff failed")
+	assert.EqualError(t, runStop(context.Background(), out, fakemachine.NewFailingClient(), time.Minute, true, true, ""), "poweroff failed")
 }
 
 func TestStopJSONSuccess(t *testing.T) {
 	out := new(bytes.Buffer)
-	assert.NoError(t, runStop(out, fakemachine.NewClient(), false, false, jsonFormat))
+	assert.NoError(t, runStop(context.Background(), out, fakemachine.NewClient(), time.Minute, false, false, jsonFormat))
 	assert.JSONEq(t, `{"success": true, "forced": false}`, out.String())
 }
 
 func TestStopJSONError(t *testing.T) {
 	out := new(bytes.Buffer)
-	assert.NoError(t, runStop(out, fakemachine.NewFailingClient(), false, false, jsonFormat))
+	assert.NoError(t, runStop(context.Background(), out, fakemachine.NewFailingClient(), time.Minute, false, false, jsonFormat))
 	assert.JSONEq(t, `{"success": false, "forced": false, "error": "stop failed"}`, out.String())
 }
 
 func TestStopWithForceJSONError(t *testing.T) {
 	out := new(bytes.Buffer)
-	assert.NoError(t, runStop(out, fakemachine.NewFailingClient(), false, true, jsonFormat))
+	assert.NoError(t, runStop(context.Background(), out, fakemachine.NewFailingClient(), time.Minute, false, true, jsonFormat))
 	assert.JSONEq(t, `{"success": false, "forced": true, "error": "poweroff failed"}`, out.String())
 }
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"github.com/code-ready/crc/pkg/crc/cluster"
 	"github.com/code-ready/crc/pkg/crc/logging"
 	"github.com/code-ready/crc/pkg/crc/machine"
 )
@@ -8,11 +10,13 @@ import (
 type AdaptedClient interface {
 	GetName() string
 
-	Delete() Result
-	GetConsoleURL() ConsoleResult
-	Start(startConfig machine.StartConfig) StartResult
-	Status() ClusterStatusResult
-	Stop() Result
+	Delete(ctx context.Context) Result
+	GetConsoleURL(ctx context.Context) ConsoleResult
+	Start(ctx context.Context, startConfig machine.StartConfig) StartResult
+	Status(ctx context.Context) ClusterStatusResult
+	Stop(ctx context.Context, stopConfig machine.StopConfig) StopResult
+	Pause(ctx context.Context) Result
+	Resume(ctx context.Context) Result
 }
 
 type Result struct {
@@ -21,12 +25,20 @@ type Result struct {
 	Error   string
 }
 
+type StopResult struct {
+	Name    string
+	Success bool
+	Error   string
+	Forced  bool
+}
+
 type StartResult struct {
 	Name           string
 	Status         string
 	Error          string
 	ClusterConfig  machine.ClusterConfig
 	KubeletStarted bool
+	Timings        []machine.StepTiming
 }
 
 type ClusterStatusResult struct {
@@ -36,6 +48,10 @@ type ClusterStatusResult struct {
 	OpenshiftVersion string
 	DiskUse          int64
 	DiskSize         int64
+	BundleExpiry     *machine.BundleExpiry    `json:",omitempty"`
+	Operators        []cluster.OperatorStatus `json:",omitempty"`
+	VMStats          *cluster.VMStats         `json:",omitempty"`
+	CertsExpiry      *machine.CertsExpiry     `json:",omitempty"`
 	Error            string
 	Success          bool
 }
@@ -54,8 +70,8 @@ func (a *Adapter) GetName() string {
 	return a.Underlying.GetName()
 }
 
-func (a *Adapter) Delete() Result {
-	err := a.Underlying.Delete()
+func (a *Adapter) Delete(ctx context.Context) Result {
+	err := a.Underlying.Delete(ctx)
 	if err != nil {
 		logging.Error(err)
 		return Result{
@@ -70,8 +86,8 @@ func (a *Adapter) Delete() Result {
 	}
 }
 
-func (a *Adapter) GetConsoleURL() ConsoleResult {
-	res, err := a.Underlying.GetConsoleURL()
+func (a *Adapter) GetConsoleURL(ctx context.Context) ConsoleResult {
+	res, err := a.Underlying.GetConsoleURL(ctx)
 	if err != nil {
 		logging.Error(err)
 		return ConsoleResult{
@@ -85,8 +101,8 @@ func (a *Adapter) GetConsoleURL() ConsoleResult {
 	}
 }
 
-func (a *Adapter) Start(startConfig machine.StartConfig) StartResult {
-	res, err := a.Underlying.Start(startConfig)
+func (a *Adapter) Start(ctx context.Context, startConfig machine.StartConfig) StartResult {
+	res, err := a.Underlying.Start(ctx, startConfig)
 	if err != nil {
 		logging.Error(err)
 		return StartResult{
@@ -99,11 +115,12 @@ func (a *Adapter) Start(startConfig machine.StartConfig) StartResult {
 		Status:         res.Status.String(),
 		ClusterConfig:  res.ClusterConfig,
 		KubeletStarted: res.KubeletStarted,
+		Timings:        res.Timings,
 	}
 }
 
-func (a *Adapter) Status() ClusterStatusResult {
-	res, err := a.Underlying.Status()
+func (a *Adapter) Status(ctx context.Context) ClusterStatusResult {
+	res, err := a.Underlying.Status(ctx)
 	if err != nil {
 		logging.Error(err)
 		return ClusterStatusResult{
@@ -119,12 +136,40 @@ func (a *Adapter) Status() ClusterStatusResult {
 		OpenshiftVersion: res.OpenshiftVersion,
 		DiskUse:          res.DiskUse,
 		DiskSize:         res.DiskSize,
+		BundleExpiry:     res.BundleExpiry,
+		Operators:        res.Operators,
+		VMStats:          res.VMStats,
+		CertsExpiry:      res.CertsExpiry,
 		Success:          true,
 	}
 }
 
-func (a *Adapter) Stop() Result {
-	_, err := a.Underlying.Stop()
+func (a *Adapter) Stop(ctx context.Context, stopConfig machine.StopConfig) StopResult {
+	res, err := a.Underlying.Stop(ctx, stopConfig)
+	if err != nil {
+		logging.Error(err)
+		return StopResult{
+			Name:    a.Underlying.GetName(),
+			Success: false,
+			Error:   err.Error(),
+		}
+	}
+	return StopResult{
+		Name:    a.Underlying.GetName(),
+		Success: true,
+		Forced:  res.Forced,
+	}
+}
+
+func (a *Adapter) Pause(ctx context.Context) Result {
+	return a.result(a.Underlying.Pause(ctx))
+}
+
+func (a *Adapter) Resume(ctx context.Context) Result {
+	return a.result(a.Underlying.Resume(ctx))
+}
+
+func (a *Adapter) result(err error) Result {
 	if err != nil {
 		logging.Error(err)
 		return Result{
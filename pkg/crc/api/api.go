@@ -3,146 +3,368 @@ package api
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net"
+	"net/http"
+	"strings"
+	"sync"
 
 	crcConfig "github.com/code-ready/crc/pkg/crc/config"
 	"github.com/code-ready/crc/pkg/crc/logging"
 	"github.com/code-ready/crc/pkg/crc/machine"
 )
 
-func CreateServer(socketPath string, config crcConfig.Storage, machine machine.Client) (Server, error) {
+// APIPrefix is the path prefix of all the routes served by the daemon.
+// It is versioned so that the API can evolve without breaking existing clients.
+const APIPrefix = "/api/v1"
+
+// DefaultMaxRequestSize is the default maximum size in bytes of a request body
+const DefaultMaxRequestSize = 1024 * 1024
+
+var errRequestTooLarge = errors.New("Request body too large")
+
+// CreateServer creates the API server listening on socketPath. forwarder can
+// be nil when ports cannot be forwarded to the VM.
+func CreateServer(socketPath string, config crcConfig.Storage, machine machine.Client, forwarder PortForwarder, maxRequestSize int64) (Server, error) {
 	listener, err := net.Listen("unix", socketPath)
 	if err != nil {
 		logging.Error("Failed to create socket: ", err.Error())
 		return Server{}, err
 	}
-	return createServerWithListener(listener, config, machine)
+	return CreateServerWithListener(listener, config, machine, forwarder, maxRequestSize)
 }
 
-func createServerWithListener(listener net.Listener, config crcConfig.Storage, machine machine.Client) (Server, error) {
+// CreateServerWithListener creates the API server serving the connections of
+// listener, for instance a socket passed by systemd
+func CreateServerWithListener(listener net.Listener, config crcConfig.Storage, machine machine.Client, forwarder PortForwarder, maxRequestSize int64) (Server, error) {
+	if maxRequestSize <= 0 {
+		return Server{}, fmt.Errorf("Invalid maximum request size: %d", maxRequestSize)
+	}
+	events := newEventBroadcaster()
 	apiServer := Server{
 		listener:               listener,
+		maxRequestSize:         maxRequestSize,
+		events:                 events,
+		operations:             newOperations(defaultOperationsHistorySize, events),
 		clusterOpsRequestsChan: make(chan clusterOpsRequest, 10),
+		queueLock:              &sync.Mutex{},
+		forwarder:              forwarder,
 		handler: &Handler{
 			Config:        config,
 			MachineClient: &Adapter{Underlying: machine},
+			events:        events,
 		},
 	}
+	apiServer.mux = apiServer.newMux()
 	return apiServer, nil
 }
 
 func (api Server) Serve() error {
-	go api.handleClusterOperations() // go routine that handles start, stop and delete calls
-	for {
-		conn, err := api.listener.Accept()
-		if err != nil {
-			if neterr, ok := err.(net.Error); ok && neterr.Temporary() {
-				logging.Errorf("accept temporary error: %v", err)
-				continue
+	go api.handleClusterOperations() // go routine that handles start, stop, delete, pause and resume calls
+	server := &http.Server{
+		Handler: api.mux,
+	}
+	return server.Serve(api.listener)
+}
+
+func (api Server) newMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc(APIPrefix+"/version", api.allowMethods(api.version, http.MethodGet))
+	mux.HandleFunc(APIPrefix+"/status", api.allowMethods(api.status, http.MethodGet))
+	mux.HandleFunc(APIPrefix+"/webconsoleurl", api.allowMethods(api.webconsoleURL, http.MethodGet))
+	mux.HandleFunc(APIPrefix+"/start", api.allowMethods(api.clusterOperation("start"), http.MethodPost))
+	mux.HandleFunc(APIPrefix+"/stop", api.allowMethods(api.clusterOperation("stop"), http.MethodPost))
+	mux.HandleFunc(APIPrefix+"/delete", api.allowMethods(api.clusterOperation("delete"), http.MethodPost))
+	mux.HandleFunc(APIPrefix+"/pause", api.allowMethods(api.clusterOperation("pause"), http.MethodPost))
+	mux.HandleFunc(APIPrefix+"/resume", api.allowMethods(api.clusterOperation("resume"), http.MethodPost))
+	mux.HandleFunc(APIPrefix+"/operations", api.allowMethods(api.listOperations, http.MethodGet))
+	mux.HandleFunc(APIPrefix+"/operations/", api.allowMethods(api.operation, http.MethodGet, http.MethodPost))
+	mux.HandleFunc(APIPrefix+"/events", api.allowMethods(api.streamEvents, http.MethodGet))
+	mux.HandleFunc(APIPrefix+"/config", api.allowMethods(api.config, http.MethodGet, http.MethodPost))
+	mux.HandleFunc(APIPrefix+"/config/", api.allowMethods(api.configKey, http.MethodGet, http.MethodPut, http.MethodDelete))
+	mux.HandleFunc(APIPrefix+"/port-forwards", api.allowMethods(api.portForwards, http.MethodGet, http.MethodPost))
+	mux.HandleFunc(APIPrefix+"/port-forwards/", api.allowMethods(api.portForward, http.MethodDelete))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Unknown route: %s", r.URL.Path))
+	})
+	return mux
+}
+
+func (api Server) allowMethods(handler http.HandlerFunc, methods ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logging.Debugf("Received request: %s %s", r.Method, r.URL.Path)
+		for _, method := range methods {
+			if r.Method == method {
+				handler(w, r)
+				return
+			}
+		}
+		methodNotAllowed(w, r, methods...)
+	}
+}
+
+func methodNotAllowed(w http.ResponseWriter, r *http.Request, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("Method %s not allowed on %s", r.Method, r.URL.Path))
+}
+
+func (api Server) version(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, api.handler.GetVersion())
+}
+
+func (api Server) status(w http.ResponseWriter, r *http.Request) {
+	result := api.handler.Status(r.Context())
+	writeJSON(w, statusCode(result.Success), result)
+}
+
+func (api Server) webconsoleURL(w http.ResponseWriter, r *http.Request) {
+	result := api.handler.GetWebconsoleInfo(r.Context())
+	writeJSON(w, statusCode(result.Success), result)
+}
+
+// clusterOperation returns a handler for start, stop, delete, pause and resume requests.
+// These are slow operations which change the VM state, so they have to run sequentially.
+// We don't want other operations querying the status of the VM to be blocked by these,
+// so they are treated by a dedicated go routine. The client immediately gets
+// the ID of the operation, which it can then use to query its progress and result.
+func (api Server) clusterOperation(command string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		args, ok := api.readBody(w, r)
+		if !ok {
+			return
+		}
+		// invalid start arguments are rejected before the operation is queued
+		if command == "start" && args != nil {
+			if _, err := parseStartArgs(args); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("Error decoding request: %v", err))
+				return
 			}
-			return err
 		}
-		api.handleConnections(conn) // handle version, status, webconsole, etc. requests
+		queued, ok := api.queueOperation(command, args)
+		if !ok {
+			logging.Error("Channel capacity reached, unable to add new request")
+			writeError(w, http.StatusServiceUnavailable, "Cluster operations channel capacity reached, unable to add new request")
+			return
+		}
+		writeJSON(w, http.StatusAccepted, queued)
+	}
+}
+
+// queueOperation adds an operation to the queue of cluster operations. It
+// returns false when the queue is full, before the operation is published.
+func (api Server) queueOperation(command string, args json.RawMessage) (*Operation, bool) {
+	// the requests are only sent to the channel here, the free capacity
+	// checked while holding the lock cannot be taken by another request
+	api.queueLock.Lock()
+	defer api.queueLock.Unlock()
+	if len(api.clusterOpsRequestsChan) == cap(api.clusterOpsRequestsChan) {
+		return nil, false
+	}
+	op := api.operations.add(command)
+	api.clusterOpsRequestsChan <- clusterOpsRequest{
+		command:     command,
+		args:        args,
+		operationID: op.ID,
 	}
+	queued, _ := api.operations.get(op.ID)
+	return &queued, true
 }
 
 func (api Server) handleClusterOperations() {
 	for req := range api.clusterOpsRequestsChan {
-		api.handleRequest(req.command, req.socket)
-	}
-}
-
-func (api Server) handleRequest(req commandRequest, conn net.Conn) {
-	defer conn.Close()
-	var result string
-
-	switch req.Command {
-	case "start":
-		result = api.handler.Start(req.Args)
-	case "stop":
-		result = api.handler.Stop()
-	case "status":
-		result = api.handler.Status()
-	case "delete":
-		result = api.handler.Delete()
-	case "version":
-		result = api.handler.GetVersion()
-	case "setconfig":
-		result = api.handler.SetConfig(req.Args)
-	case "unsetconfig":
-		result = api.handler.UnsetConfig(req.Args)
-	case "getconfig":
-		result = api.handler.GetConfig(req.Args)
-	case "webconsoleurl":
-		result = api.handler.GetWebconsoleInfo()
-	default:
-		result = encodeErrorToJSON(fmt.Sprintf("Unknown command supplied: %s", req.Command))
+		ctx, ok := api.operations.setRunning(req.operationID)
+		if !ok {
+			logging.Debugf("Skipping %s operation %s which was cancelled", req.command, req.operationID)
+			continue
+		}
+		switch req.command {
+		case "start":
+			result := api.handler.Start(ctx, req.args)
+			api.operations.setFinished(req.operationID, result.Error == "", result.Error, result)
+		case "stop":
+			result := api.handler.Stop(ctx)
+			api.operations.setFinished(req.operationID, result.Success, result.Error, result)
+		case "delete":
+			result := api.handler.Delete(ctx)
+			api.operations.setFinished(req.operationID, result.Success, result.Error, result)
+		case "pause":
+			result := api.handler.Pause(ctx)
+			api.operations.setFinished(req.operationID, result.Success, result.Error, result)
+		case "resume":
+			result := api.handler.Resume(ctx)
+			api.operations.setFinished(req.operationID, result.Success, result.Error, result)
+		}
 	}
-	writeStringToSocket(conn, result)
 }
 
-func (api Server) handleConnections(conn net.Conn) {
-	inBuffer := make([]byte, 1024)
-	var req commandRequest
-	numBytes, err := conn.Read(inBuffer)
-	if err != nil || numBytes == 0 || numBytes == cap(inBuffer) {
-		logging.Error("Error reading from socket")
+func (api Server) listOperations(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, api.operations.list())
+}
+
+// operation serves GET /operations/{id} and POST /operations/{id}/cancel
+func (api Server) operation(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, APIPrefix+"/operations/")
+	if id := strings.TrimSuffix(path, "/cancel"); id != path && !strings.Contains(id, "/") {
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, r, http.MethodPost)
+			return
+		}
+		api.cancelOperation(w, id)
 		return
 	}
-	logging.Debug("Received Request:", string(inBuffer[0:numBytes]))
-	err = json.Unmarshal(inBuffer[0:numBytes], &req)
-	if err != nil {
-		logging.Error("Error decoding request: ", err.Error())
+	if strings.Contains(path, "/") {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Unknown route: %s", r.URL.Path))
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r, http.MethodGet)
 		return
 	}
-	// start, stop and delete are slow operations, and change the VM state so they have to run sequentially.
-	// We don't want other operations querying the status of the VM to be blocked by these,
-	// so they are treated by a dedicated go routine
+	op, ok := api.operations.get(path)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Unknown operation: %s", path))
+		return
+	}
+	writeJSON(w, http.StatusOK, op)
+}
+
+func (api Server) cancelOperation(w http.ResponseWriter, id string) {
+	op, err := api.operations.cancel(id)
+	switch err {
+	case nil:
+		writeJSON(w, http.StatusAccepted, op)
+	case errUnknownOperation:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Unknown operation: %s", id))
+	default:
+		writeError(w, http.StatusConflict, fmt.Sprintf("Cannot cancel operation %s: %v", id, err))
+	}
+}
 
-	switch req.Command {
-	case "start", "stop", "delete":
-		// queue new request to channel
-		r := clusterOpsRequest{
-			command: req,
-			socket:  conn,
+func (api Server) config(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, api.handler.GetConfig(nil))
+	case http.MethodPost:
+		var args SetConfigArgs
+		if !api.decodeBody(w, r, &args) {
+			return
 		}
-		if !addRequestToChannel(r, api.clusterOpsRequestsChan) {
-			logging.Error("Channel capacity reached, unable to add new request")
-			errMsg := encodeErrorToJSON("Sockets channel capacity reached, unable to add new request")
-			writeStringToSocket(conn, errMsg)
-			conn.Close()
+		result := api.handler.SetConfig(args)
+		writeJSON(w, statusCodeFor(result.Error == "", http.StatusBadRequest), result)
+	}
+}
+
+// setConfigValue is the body of a PUT request on a configuration property
+type setConfigValue struct {
+	Value interface{} `json:"value"`
+}
+
+func (api Server) configKey(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, APIPrefix+"/config/")
+	if key == "" || strings.Contains(key, "/") {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Unknown route: %s", r.URL.Path))
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		result := api.handler.GetConfig(&GetConfigArgs{Properties: []string{key}})
+		writeJSON(w, statusCodeFor(result.Error == "", http.StatusNotFound), result)
+	case http.MethodPut:
+		var value setConfigValue
+		if !api.decodeBody(w, r, &value) {
+			return
 		}
+		result := api.handler.SetConfig(SetConfigArgs{
+			Properties: map[string]interface{}{key: value.Value},
+		})
+		writeJSON(w, statusCodeFor(result.Error == "", http.StatusBadRequest), result)
+	case http.MethodDelete:
+		result := api.handler.UnsetConfig(UnsetConfigArgs{Properties: []string{key}})
+		writeJSON(w, statusCodeFor(result.Error == "", http.StatusBadRequest), result)
+	}
+}
 
-	case "status", "version", "setconfig", "getconfig", "unsetconfig", "webconsoleurl":
-		go api.handleRequest(req, conn)
+// readBody reads the whole request body, which can be empty. When the body
+// cannot be read or is larger than the maximum request size, an error is
+// sent to the client and false is returned.
+func (api Server) readBody(w http.ResponseWriter, r *http.Request) (json.RawMessage, bool) {
+	body, err := readAtMost(r, api.maxRequestSize)
+	switch {
+	case err == errRequestTooLarge:
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body larger than the maximum size of %d bytes", api.maxRequestSize))
+		return nil, false
+	case err != nil:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Error reading request body: %v", err))
+		return nil, false
+	case len(body) == 0:
+		return nil, true
+	}
+	return body, true
+}
 
-	default:
-		err := encodeErrorToJSON(fmt.Sprintf("Unknown command supplied: %s", req.Command))
-		writeStringToSocket(conn, err)
-		conn.Close()
+// decodeBody decodes the JSON request body in v. When the body is invalid,
+// an error is sent to the client and false is returned.
+func (api Server) decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	body, ok := api.readBody(w, r)
+	if !ok {
+		return false
 	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Error decoding request: %v", err))
+		return false
+	}
+	return true
 }
 
-func writeStringToSocket(socket net.Conn, msg string) {
-	var outBuffer bytes.Buffer
-	_, err := outBuffer.WriteString(msg)
-	if err != nil {
-		logging.Error("Failed writing string to buffer", err.Error())
-		return
+func readAtMost(r *http.Request, maxSize int64) ([]byte, error) {
+	if r.ContentLength > maxSize {
+		return nil, errRequestTooLarge
 	}
-	_, err = socket.Write(outBuffer.Bytes())
+	// Read one more byte than allowed to detect oversized bodies sent without a Content-Length
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxSize+1))
 	if err != nil {
-		logging.Error("Failed writing string to socket", err.Error())
-		return
+		return nil, err
+	}
+	if int64(len(body)) > maxSize {
+		return nil, errRequestTooLarge
 	}
+	return body, nil
 }
 
-func addRequestToChannel(req clusterOpsRequest, requestsChan chan clusterOpsRequest) bool {
-	select {
-	case requestsChan <- req:
-		return true
-	default:
-		return false
+func statusCode(success bool) int {
+	return statusCodeFor(success, http.StatusInternalServerError)
+}
+
+func statusCodeFor(success bool, failureCode int) int {
+	if success {
+		return http.StatusOK
 	}
+	return failureCode
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		logging.Error(err.Error())
+		code = http.StatusInternalServerError
+		body, _ = json.Marshal(commandError{
+			Err: "Failed while encoding JSON to string",
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if _, err := w.Write(body); err != nil {
+		logging.Error("Failed writing response: ", err.Error())
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, errMsg string) {
+	writeJSON(w, code, commandError{
+		Err: errMsg,
+	})
 }
@@ -3,13 +3,20 @@
 package api
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	cmdConfig "github.com/code-ready/crc/cmd/crc/cmd/config"
 	"github.com/code-ready/crc/pkg/crc/config"
@@ -31,7 +38,7 @@ func TestApi(t *testing.T) {
 	require.NoError(t, err)
 
 	client := fakemachine.NewClient()
-	api, err := createServerWithListener(listener, setupNewInMemoryConfig(), client)
+	api, err := CreateServerWithListener(listener, setupNewInMemoryConfig(), client, nil, DefaultMaxRequestSize)
 	require.NoError(t, err)
 	go func() {
 		if err := api.Serve(); err != nil {
@@ -40,13 +47,17 @@ func TestApi(t *testing.T) {
 	}()
 
 	tt := []struct {
-		command       string
-		clientFailing bool
-		args          json.RawMessage
-		expected      map[string]interface{}
+		method         string
+		path           string
+		clientFailing  bool
+		args           json.RawMessage
+		expectedStatus int
+		expected       map[string]interface{}
 	}{
 		{
-			command: "version",
+			method:         http.MethodGet,
+			path:           "/version",
+			expectedStatus: http.StatusOK,
 			expected: map[string]interface{}{
 				"CrcVersion":       version.GetCRCVersion(),
 				"CommitSha":        version.GetCommitSha(),
@@ -55,7 +66,9 @@ func TestApi(t *testing.T) {
 			},
 		},
 		{
-			command: "status",
+			method:         http.MethodGet,
+			path:           "/status",
+			expectedStatus: http.StatusOK,
 			expected: map[string]interface{}{
 				"Name":             "crc",
 				"CrcStatus":        "Running",
@@ -63,13 +76,34 @@ func TestApi(t *testing.T) {
 				"OpenshiftVersion": "4.5.1",
 				"DiskUse":          float64(10000000000),
 				"DiskSize":         float64(20000000000),
-				"Error":            "",
-				"Success":          true,
+				"Operators": []interface{}{
+					map[string]interface{}{"Name": "authentication", "Available": true, "Degraded": false, "Progressing": false, "Disabled": false},
+					map[string]interface{}{"Name": "console", "Available": true, "Degraded": false, "Progressing": false, "Disabled": false},
+				},
+				"VMStats": map[string]interface{}{
+					"CPUs":        float64(4),
+					"CPUUsage":    12.5,
+					"MemoryUsage": float64(6000000000),
+					"MemorySize":  float64(9000000000),
+					"Uptime":      float64(7380000000000),
+				},
+				"CertsExpiry": map[string]interface{}{
+					"KubeletClient": "2021-03-01T10:00:00Z",
+					"KubeletServer": "2021-03-01T10:05:00Z",
+				},
+				"BundleExpiry": map[string]interface{}{
+					"ExpiresAt": "2021-10-26T04:48:26Z",
+					"Warning":   "The certificates of the bundle expire in 10 days",
+				},
+				"Error":   "",
+				"Success": true,
 			},
 		},
 		{
-			command:       "status",
-			clientFailing: true,
+			method:         http.MethodGet,
+			path:           "/status",
+			clientFailing:  true,
+			expectedStatus: http.StatusInternalServerError,
 			expected: map[string]interface{}{
 				"Name":             "crc",
 				"CrcStatus":        "",
@@ -82,32 +116,51 @@ func TestApi(t *testing.T) {
 			},
 		},
 		{
-			command:       "start",
-			clientFailing: true,
-			args:          json.RawMessage(`{"pull-secret":"/Users/fake/pull-secret"}`),
+			method:         http.MethodPost,
+			path:           "/status",
+			expectedStatus: http.StatusMethodNotAllowed,
 			expected: map[string]interface{}{
-				"Name":           "crc",
-				"Status":         "",
-				"Error":          "Incorrect arguments given: json: unknown field \"pull-secret\"",
-				"KubeletStarted": false,
-				"ClusterConfig": map[string]interface{}{
-					"KubeConfig":    "",
-					"KubeAdminPass": "",
-					"ClusterAPI":    "",
-					"WebConsoleURL": "",
-					"ProxyConfig":   nil,
-					"ClusterCACert": "",
-				},
+				"Err": "Method POST not allowed on /api/v1/status",
 			},
 		},
 		{
-			command: "start",
-			args:    json.RawMessage(`{"pullSecretFile":"/Users/fake/pull-secret"}`),
+			method:         http.MethodGet,
+			path:           "/unknown",
+			expectedStatus: http.StatusNotFound,
+			expected: map[string]interface{}{
+				"Err": "Unknown route: /api/v1/unknown",
+			},
+		},
+	}
+	for _, test := range tt {
+		client.Failing = test.clientFailing
+		status, payload := sendRequest(t, socket, test.method, test.path, test.args)
+		assert.Equal(t, test.expectedStatus, status)
+
+		var res map[string]interface{}
+		assert.NoError(t, json.Unmarshal(payload, &res))
+		assert.Equal(t, test.expected, res)
+	}
+}
+
+func TestStartOperation(t *testing.T) {
+	socket, cleanup := setupAPIServer(t)
+	defer cleanup()
+
+	tt := []struct {
+		args            json.RawMessage
+		expectedSuccess bool
+		expected        map[string]interface{}
+	}{
+		{
+			args:            json.RawMessage(`{"pullSecretFile":"/Users/fake/pull-secret"}`),
+			expectedSuccess: true,
 			expected: map[string]interface{}{
 				"Name":           "crc",
 				"Status":         "",
 				"Error":          "",
 				"KubeletStarted": true,
+				"Timings":        nil,
 				"ClusterConfig": map[string]interface{}{
 					"ClusterCACert": "MIIDODCCAiCgAwIBAgIIRVfCKNUa1wIwDQYJ",
 					"KubeConfig":    "/tmp/kubeconfig",
@@ -120,49 +173,124 @@ func TestApi(t *testing.T) {
 		},
 	}
 	for _, test := range tt {
-		client.Failing = test.clientFailing
-		client, err := net.Dial("unix", socket)
-		require.NoError(t, err)
+		status, payload := sendRequest(t, socket, http.MethodPost, "/start", test.args)
+		assert.Equal(t, http.StatusAccepted, status)
+		var op Operation
+		assert.NoError(t, json.Unmarshal(payload, &op))
+		assert.Equal(t, "start", op.Command)
+		assert.NotEmpty(t, op.ID)
 
-		jsonReq, err := json.Marshal(commandRequest{
-			Command: test.command,
-			Args:    test.args,
-		})
-		assert.NoError(t, err)
-		_, err = client.Write(jsonReq)
-		assert.NoError(t, err)
+		op = waitForOperation(t, socket, op.ID)
+		assert.Equal(t, test.expectedSuccess, op.Success)
+		var res map[string]interface{}
+		assert.NoError(t, json.Unmarshal(op.Result, &res))
+		assert.Equal(t, test.expected, res)
+	}
 
-		payload := make([]byte, 1024)
-		n, err := client.Read(payload)
-		assert.NoError(t, err)
+	status, payload := sendRequest(t, socket, http.MethodGet, "/operations", nil)
+	assert.Equal(t, http.StatusOK, status)
+	var ops []Operation
+	assert.NoError(t, json.Unmarshal(payload, &ops))
+	assert.Len(t, ops, 1)
 
+	status, _ = sendRequest(t, socket, http.MethodGet, "/operations/unknown", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestStartOperationWithInvalidArguments(t *testing.T) {
+	socket, cleanup := setupAPIServer(t)
+	defer cleanup()
+
+	for _, args := range []json.RawMessage{
+		json.RawMessage(`{"pull-secret":"/Users/fake/pull-secret"}`),
+		json.RawMessage(`{"pullSecretFile":`),
+	} {
+		status, payload := sendRequest(t, socket, http.MethodPost, "/start", args)
+		assert.Equal(t, http.StatusBadRequest, status)
 		var res map[string]interface{}
-		assert.NoError(t, json.Unmarshal(payload[0:n], &res))
-		assert.Equal(t, test.expected, res)
+		assert.NoError(t, json.Unmarshal(payload, &res))
+		assert.Contains(t, res["Err"], "Error decoding request: ")
 	}
+
+	status, payload := sendRequest(t, socket, http.MethodGet, "/operations", nil)
+	assert.Equal(t, http.StatusOK, status)
+	var ops []Operation
+	assert.NoError(t, json.Unmarshal(payload, &ops))
+	assert.Empty(t, ops)
 }
 
-func TestSetconfigApi(t *testing.T) {
+func TestPauseAndResumeOperations(t *testing.T) {
 	socket, cleanup := setupAPIServer(t)
-	client, err := net.Dial("unix", socket)
-	require.NoError(t, err)
 	defer cleanup()
 
-	jsonReq, err := json.Marshal(commandRequest{
-		Command: "setconfig",
-		Args:    json.RawMessage(`{"properties":{"cpus":"5"}}`),
-	})
-	assert.NoError(t, err)
-	_, err = client.Write(jsonReq)
-	assert.NoError(t, err)
+	for _, command := range []string{"pause", "resume"} {
+		op := queueOperation(t, socket, "/"+command)
+		assert.Equal(t, command, op.Command)
+
+		op = waitForOperation(t, socket, op.ID)
+		assert.True(t, op.Success)
+		var res map[string]interface{}
+		assert.NoError(t, json.Unmarshal(op.Result, &res))
+		assert.Equal(t, map[string]interface{}{
+			"Name":    "crc",
+			"Success": true,
+			"Error":   "",
+		}, res)
+	}
+}
+
+func TestOperationsHistory(t *testing.T) {
+	ops := newOperations(2, nil)
+	var ids []string
+	for i := 0; i < 4; i++ {
+		op := ops.add("stop")
+		ids = append(ids, op.ID)
+	}
+	ops.setRunning(ids[0])
+	for _, id := range ids[:3] {
+		ops.setFinished(id, true, "", Result{Success: true})
+	}
+	var remaining []string
+	for _, op := range ops.list() {
+		remaining = append(remaining, op.ID)
+	}
+	assert.Equal(t, ids[1:], remaining)
+
+	op, ok := ops.get(ids[3])
+	assert.True(t, ok)
+	assert.Equal(t, OperationQueued, op.State)
+}
+
+func TestQueueOperationWhenFull(t *testing.T) {
+	api, err := CreateServerWithListener(nil, setupNewInMemoryConfig(), fakemachine.NewClient(), nil, DefaultMaxRequestSize)
+	require.NoError(t, err)
+	for i := 0; i < cap(api.clusterOpsRequestsChan); i++ {
+		_, ok := api.queueOperation("stop", nil)
+		require.True(t, ok)
+	}
 
-	payload := make([]byte, 1024)
-	n, err := client.Read(payload)
-	assert.NoError(t, err)
+	events, unsubscribe := api.events.subscribe()
+	defer unsubscribe()
+	_, ok := api.queueOperation("stop", nil)
+	assert.False(t, ok)
+	assert.Len(t, api.operations.list(), cap(api.clusterOpsRequestsChan))
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected %s event for a dropped operation", event.Type)
+	default:
+	}
+}
 
-	var setconfigRes setOrUnsetConfigResult
-	assert.NoError(t, json.Unmarshal(payload[:n], &setconfigRes))
-	assert.Equal(t, setOrUnsetConfigResult{
+func TestSetconfigApi(t *testing.T) {
+	socket, cleanup := setupAPIServer(t)
+	defer cleanup()
+
+	status, payload := sendRequest(t, socket, http.MethodPost, "/config", json.RawMessage(`{"properties":{"cpus":"5"}}`))
+	assert.Equal(t, http.StatusOK, status)
+
+	var setconfigRes SetOrUnsetConfigResult
+	assert.NoError(t, json.Unmarshal(payload, &setconfigRes))
+	assert.Equal(t, SetOrUnsetConfigResult{
 		Error:      "",
 		Properties: []string{"cpus"},
 	}, setconfigRes)
@@ -170,34 +298,226 @@ func TestSetconfigApi(t *testing.T) {
 
 func TestGetconfigApi(t *testing.T) {
 	socket, cleanup := setupAPIServer(t)
-	client, err := net.Dial("unix", socket)
-	require.NoError(t, err)
 	defer cleanup()
 
-	jsonReq, err := json.Marshal(commandRequest{
-		Command: "getconfig",
-		Args:    json.RawMessage(`{"properties":["cpus"]}`),
-	})
-	assert.NoError(t, err)
-	_, err = client.Write(jsonReq)
-	assert.NoError(t, err)
-
-	payload := make([]byte, 1024)
-	n, err := client.Read(payload)
-	assert.NoError(t, err)
+	status, payload := sendRequest(t, socket, http.MethodGet, "/config/cpus", nil)
+	assert.Equal(t, http.StatusOK, status)
 
-	var getconfigRes getConfigResult
-	assert.NoError(t, json.Unmarshal(payload[:n], &getconfigRes))
+	var getconfigRes GetConfigResult
+	assert.NoError(t, json.Unmarshal(payload, &getconfigRes))
 
 	configs := make(map[string]interface{})
 	configs["cpus"] = 4.0
 
-	assert.Equal(t, getConfigResult{
+	assert.Equal(t, GetConfigResult{
 		Error:   "",
 		Configs: configs,
 	}, getconfigRes)
 }
 
+func TestPutAndDeleteconfigApi(t *testing.T) {
+	socket, cleanup := setupAPIServer(t)
+	defer cleanup()
+
+	status, payload := sendRequest(t, socket, http.MethodPut, "/config/nameserver", json.RawMessage(`{"value":"1.1.1.1"}`))
+	assert.Equal(t, http.StatusOK, status)
+	var setconfigRes SetOrUnsetConfigResult
+	assert.NoError(t, json.Unmarshal(payload, &setconfigRes))
+	assert.Equal(t, SetOrUnsetConfigResult{
+		Properties: []string{"nameserver"},
+	}, setconfigRes)
+
+	status, payload = sendRequest(t, socket, http.MethodPut, "/config/nameserver", json.RawMessage(`{"value":"foo"}`))
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.NoError(t, json.Unmarshal(payload, &setconfigRes))
+	assert.NotEmpty(t, setconfigRes.Error)
+
+	status, payload = sendRequest(t, socket, http.MethodDelete, "/config/nameserver", nil)
+	assert.Equal(t, http.StatusOK, status)
+	var unsetconfigRes SetOrUnsetConfigResult
+	assert.NoError(t, json.Unmarshal(payload, &unsetconfigRes))
+	assert.Equal(t, SetOrUnsetConfigResult{
+		Properties: []string{"nameserver"},
+	}, unsetconfigRes)
+
+	status, _ = sendRequest(t, socket, http.MethodGet, "/config/unknown", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestLargeRequestApi(t *testing.T) {
+	socket, cleanup := setupAPIServer(t)
+	defer cleanup()
+
+	// Requests larger than the former 1024 bytes read buffer must be accepted
+	noProxy := strings.TrimSuffix(strings.Repeat("example.com,", 400), ",")
+	body := fmt.Sprintf(`{"properties":{"no-proxy":"%s"}}`, noProxy)
+	status, payload := sendRequest(t, socket, http.MethodPost, "/config", json.RawMessage(body))
+	assert.Equal(t, http.StatusOK, status)
+
+	var setconfigRes SetOrUnsetConfigResult
+	assert.NoError(t, json.Unmarshal(payload, &setconfigRes))
+	assert.Equal(t, SetOrUnsetConfigResult{
+		Properties: []string{"no-proxy"},
+	}, setconfigRes)
+
+	status, payload = sendRequest(t, socket, http.MethodGet, "/config/no-proxy", nil)
+	assert.Equal(t, http.StatusOK, status)
+	var getconfigRes GetConfigResult
+	assert.NoError(t, json.Unmarshal(payload, &getconfigRes))
+	assert.Equal(t, noProxy, getconfigRes.Configs["no-proxy"])
+}
+
+func TestOversizedRequestApi(t *testing.T) {
+	dir, err := ioutil.TempDir("", "api")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "api.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	api, err := CreateServerWithListener(listener, setupNewInMemoryConfig(), fakemachine.NewClient(), nil, 64)
+	require.NoError(t, err)
+	go func() {
+		if err := api.Serve(); err != nil {
+			log.Error(err)
+		}
+	}()
+
+	body := fmt.Sprintf(`{"properties":{"nameserver":"%s"}}`, strings.Repeat("1", 64))
+	status, payload := sendRequest(t, socket, http.MethodPost, "/config", json.RawMessage(body))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+
+	var res map[string]interface{}
+	assert.NoError(t, json.Unmarshal(payload, &res))
+	assert.Equal(t, map[string]interface{}{
+		"Err": "Request body larger than the maximum size of 64 bytes",
+	}, res)
+}
+
+func TestStartProgressEvents(t *testing.T) {
+	socket, cleanup := setupAPIServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequest(http.MethodGet, "http://unix"+APIPrefix+"/events", nil)
+	require.NoError(t, err)
+	res, err := newHTTPClient(socket).Do(req.WithContext(ctx))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	status, _ := sendRequest(t, socket, http.MethodPost, "/start", json.RawMessage(`{"pullSecretFile":"/Users/fake/pull-secret"}`))
+	assert.Equal(t, http.StatusAccepted, status)
+
+	reader := bufio.NewReader(res.Body)
+	var types []string
+	var progress string
+	for len(types) < 4 {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			types = append(types, strings.TrimSpace(strings.TrimPrefix(line, "event: ")))
+		case strings.HasPrefix(line, "data: ") && types[len(types)-1] == StartProgressEvent:
+			progress = strings.TrimSpace(strings.TrimPrefix(line, "data: "))
+		}
+	}
+	assert.Equal(t, []string{OperationEvent, OperationEvent, StartProgressEvent, OperationEvent}, types)
+	assert.Equal(t, `{"step":"done","percentage":100,"message":"Started the OpenShift cluster"}`, progress)
+}
+
+func TestCancelOperation(t *testing.T) {
+	socket, cleanup := setupAPIServerWithClient(t, fakemachine.NewBlockingClient())
+	defer cleanup()
+
+	running := queueOperation(t, socket, "/start")
+	for running.State != OperationRunning {
+		time.Sleep(10 * time.Millisecond)
+		running = getOperation(t, socket, running.ID)
+	}
+	queued := queueOperation(t, socket, "/stop")
+	assert.Equal(t, OperationQueued, queued.State)
+
+	status, payload := sendRequest(t, socket, http.MethodPost, "/operations/"+queued.ID+"/cancel", nil)
+	assert.Equal(t, http.StatusAccepted, status)
+	var op Operation
+	assert.NoError(t, json.Unmarshal(payload, &op))
+	assert.Equal(t, OperationFinished, op.State)
+	assert.True(t, op.Cancelled)
+	assert.False(t, op.Success)
+
+	status, _ = sendRequest(t, socket, http.MethodPost, "/operations/"+running.ID+"/cancel", nil)
+	assert.Equal(t, http.StatusAccepted, status)
+	op = waitForOperation(t, socket, running.ID)
+	assert.True(t, op.Cancelled)
+	assert.False(t, op.Success)
+	assert.Contains(t, op.Error, "context canceled")
+
+	status, _ = sendRequest(t, socket, http.MethodPost, "/operations/"+running.ID+"/cancel", nil)
+	assert.Equal(t, http.StatusConflict, status)
+	status, _ = sendRequest(t, socket, http.MethodPost, "/operations/unknown/cancel", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = sendRequest(t, socket, http.MethodGet, "/operations/"+running.ID+"/cancel", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, status)
+}
+
+func queueOperation(t *testing.T, socket, path string) Operation {
+	status, payload := sendRequest(t, socket, http.MethodPost, path, nil)
+	require.Equal(t, http.StatusAccepted, status)
+	var op Operation
+	require.NoError(t, json.Unmarshal(payload, &op))
+	return op
+}
+
+func getOperation(t *testing.T, socket, id string) Operation {
+	status, payload := sendRequest(t, socket, http.MethodGet, "/operations/"+id, nil)
+	require.Equal(t, http.StatusOK, status)
+	var op Operation
+	require.NoError(t, json.Unmarshal(payload, &op))
+	return op
+}
+
+func waitForOperation(t *testing.T, socket, id string) Operation {
+	for {
+		op := getOperation(t, socket, id)
+		if op.State == OperationFinished {
+			return op
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func newHTTPClient(socket string) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+}
+
+func sendRequest(t *testing.T, socket, method, path string, body json.RawMessage) (int, []byte) {
+	client := newHTTPClient(socket)
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, "http://unix"+APIPrefix+path, reader)
+	require.NoError(t, err)
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	payload, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	return res.StatusCode, payload
+}
+
 func setupNewInMemoryConfig() config.Storage {
 	storage := config.NewEmptyInMemoryStorage()
 	cfg := config.New(&skipPreflights{
@@ -210,6 +530,14 @@ func setupNewInMemoryConfig() config.Storage {
 }
 
 func setupAPIServer(t *testing.T) (string, func()) {
+	return setupAPIServerWithClient(t, fakemachine.NewClient())
+}
+
+func setupAPIServerWithClient(t *testing.T, client *fakemachine.Client) (string, func()) {
+	return setupAPIServerWithForwarder(t, client, nil)
+}
+
+func setupAPIServerWithForwarder(t *testing.T, client *fakemachine.Client, forwarder PortForwarder) (string, func()) {
 	dir, err := ioutil.TempDir("", "api")
 	require.NoError(t, err)
 
@@ -217,8 +545,7 @@ func setupAPIServer(t *testing.T) (string, func()) {
 	listener, err := net.Listen("unix", socket)
 	require.NoError(t, err)
 
-	client := fakemachine.NewClient()
-	api, err := createServerWithListener(listener, setupNewInMemoryConfig(), client)
+	api, err := CreateServerWithListener(listener, setupNewInMemoryConfig(), client, forwarder, DefaultMaxRequestSize)
 	require.NoError(t, err)
 	go func() {
 		if err := api.Serve(); err != nil {
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"time"
+
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/code-ready/crc/pkg/crc/machine"
+)
+
+// BundleExpiryEvent is the type of the event sent when the certificates of
+// the bundle can soon no longer be renewed, its data is a machine.BundleExpiry
+const BundleExpiryEvent = "bundle-expiry"
+
+const bundleExpiryCheckInterval = 24 * time.Hour
+
+// BundleExpiryDate returns the date after which the certificates of the
+// configured bundle can no longer be renewed
+type BundleExpiryDate func() (time.Time, error)
+
+// WarnBeforeBundleExpiry checks the expiry date of the bundle once a day,
+// and logs and publishes a warning when it is close
+func (api Server) WarnBeforeBundleExpiry(expiryDate BundleExpiryDate) {
+	api.warnIfBundleExpires(expiryDate, time.Now())
+	ticker := time.NewTicker(bundleExpiryCheckInterval)
+	go func() {
+		for now := range ticker.C {
+			api.warnIfBundleExpires(expiryDate, now)
+		}
+	}()
+}
+
+// warnIfBundleExpires returns the published warning, or an empty string
+func (api Server) warnIfBundleExpires(expiryDate BundleExpiryDate, now time.Time) string {
+	expiresAt, err := expiryDate()
+	if err != nil {
+		logging.Debugf("Cannot get the expiry date of the bundle certificates: %v", err)
+		return ""
+	}
+	message := machine.BundleExpiryWarning(expiresAt, now)
+	if message == "" {
+		return ""
+	}
+	logging.Warn(message)
+	api.events.publish(BundleExpiryEvent, machine.BundleExpiry{
+		ExpiresAt: expiresAt,
+		Warning:   message,
+	})
+	return message
+}
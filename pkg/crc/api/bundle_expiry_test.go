@@ -0,0 +1,32 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/code-ready/crc/pkg/crc/config"
+	"github.com/code-ready/crc/pkg/crc/machine/fakemachine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarnIfBundleExpires(t *testing.T) {
+	api, err := CreateServerWithListener(nil, config.New(config.NewEmptyInMemoryStorage()), fakemachine.NewClient(), nil, DefaultMaxRequestSize)
+	require.NoError(t, err)
+	events, unsubscribe := api.events.subscribe()
+	defer unsubscribe()
+
+	expiresAt := time.Date(2021, 10, 26, 4, 48, 26, 0, time.UTC)
+	expiryDate := func() (time.Time, error) { return expiresAt, nil }
+
+	assert.Empty(t, api.warnIfBundleExpires(expiryDate, expiresAt.AddDate(0, -2, 0)))
+	message := api.warnIfBundleExpires(expiryDate, expiresAt.AddDate(0, 0, -5))
+	assert.Contains(t, message, "The certificates of the bundle expire in 5 days")
+
+	event := <-events
+	assert.Equal(t, BundleExpiryEvent, event.Type)
+	assert.JSONEq(t, `{"ExpiresAt": "2021-10-26T04:48:26Z", "Warning": "`+message+`"}`, string(event.Data))
+
+	assert.Empty(t, api.warnIfBundleExpires(func() (time.Time, error) { return time.Time{}, errors.New("no bundle") }, expiresAt))
+}
@@ -0,0 +1,255 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/code-ready/crc/pkg/crc/api"
+)
+
+// Client talks to the HTTP API exposed by the crc daemon
+type Client struct {
+	client *http.Client
+	base   string
+}
+
+// New returns a client for the daemon listening on the unix socket socketPath
+func New(socketPath string) *Client {
+	return &Client{
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+		base: fmt.Sprintf("http://unix%s", api.APIPrefix),
+	}
+}
+
+func (c *Client) Version() (api.VersionResult, error) {
+	var res api.VersionResult
+	err := c.do(http.MethodGet, "/version", nil, &res)
+	return res, err
+}
+
+func (c *Client) Status() (api.ClusterStatusResult, error) {
+	var res api.ClusterStatusResult
+	err := c.do(http.MethodGet, "/status", nil, &res)
+	return res, err
+}
+
+// Start queues a start of the cluster and returns the operation tracking it
+func (c *Client) Start(args api.StartArgs) (api.Operation, error) {
+	var res api.Operation
+	err := c.do(http.MethodPost, "/start", args, &res)
+	return res, err
+}
+
+// Stop queues a stop of the cluster and returns the operation tracking it
+func (c *Client) Stop() (api.Operation, error) {
+	var res api.Operation
+	err := c.do(http.MethodPost, "/stop", nil, &res)
+	return res, err
+}
+
+// Delete queues a deletion of the cluster and returns the operation tracking it
+func (c *Client) Delete() (api.Operation, error) {
+	var res api.Operation
+	err := c.do(http.MethodPost, "/delete", nil, &res)
+	return res, err
+}
+
+// Pause queues a pause of the cluster and returns the operation tracking it
+func (c *Client) Pause() (api.Operation, error) {
+	var res api.Operation
+	err := c.do(http.MethodPost, "/pause", nil, &res)
+	return res, err
+}
+
+// Resume queues a resume of the paused cluster and returns the operation tracking it
+func (c *Client) Resume() (api.Operation, error) {
+	var res api.Operation
+	err := c.do(http.MethodPost, "/resume", nil, &res)
+	return res, err
+}
+
+func (c *Client) Operations() ([]api.Operation, error) {
+	var res []api.Operation
+	err := c.do(http.MethodGet, "/operations", nil, &res)
+	return res, err
+}
+
+func (c *Client) Operation(id string) (api.Operation, error) {
+	var res api.Operation
+	err := c.do(http.MethodGet, "/operations/"+id, nil, &res)
+	return res, err
+}
+
+// Cancel cancels a queued or running operation
+func (c *Client) Cancel(id string) (api.Operation, error) {
+	var res api.Operation
+	err := c.do(http.MethodPost, "/operations/"+id+"/cancel", nil, &res)
+	return res, err
+}
+
+// WaitForOperation polls the daemon until the operation with the given ID is
+// finished or ctx is cancelled
+func (c *Client) WaitForOperation(ctx context.Context, id string, interval time.Duration) (api.Operation, error) {
+	for {
+		op, err := c.Operation(id)
+		if err != nil {
+			return op, err
+		}
+		if op.State == api.OperationFinished {
+			return op, nil
+		}
+		select {
+		case <-ctx.Done():
+			return op, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (c *Client) WebconsoleURL() (api.ConsoleResult, error) {
+	var res api.ConsoleResult
+	err := c.do(http.MethodGet, "/webconsoleurl", nil, &res)
+	return res, err
+}
+
+func (c *Client) GetAllConfig() (api.GetConfigResult, error) {
+	var res api.GetConfigResult
+	err := c.do(http.MethodGet, "/config", nil, &res)
+	return res, err
+}
+
+func (c *Client) GetConfig(key string) (api.GetConfigResult, error) {
+	var res api.GetConfigResult
+	err := c.do(http.MethodGet, "/config/"+key, nil, &res)
+	return res, err
+}
+
+func (c *Client) SetConfig(args api.SetConfigArgs) (api.SetOrUnsetConfigResult, error) {
+	var res api.SetOrUnsetConfigResult
+	err := c.do(http.MethodPost, "/config", args, &res)
+	return res, err
+}
+
+func (c *Client) UnsetConfig(key string) (api.SetOrUnsetConfigResult, error) {
+	var res api.SetOrUnsetConfigResult
+	err := c.do(http.MethodDelete, "/config/"+key, nil, &res)
+	return res, err
+}
+
+// Events subscribes to the events published by the daemon and calls
+// handler for each of them until ctx is cancelled or the daemon closes the
+// connection
+func (c *Client) Events(ctx context.Context, handler func(api.Event)) error {
+	req, err := http.NewRequest(http.MethodGet, c.base+"/events", nil)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("Cannot subscribe to daemon events: %s", res.Status)
+	}
+
+	var event api.Event
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event.Type = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.Data = json.RawMessage(strings.TrimPrefix(line, "data: "))
+		case line == "" && event.Type != "":
+			handler(event)
+			event = api.Event{}
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return scanner.Err()
+}
+
+// PortForwards lists the ports of the host forwarded to the VM
+func (c *Client) PortForwards() ([]api.PortForward, error) {
+	var res []api.PortForward
+	err := c.do(http.MethodGet, "/port-forwards", nil, &res)
+	return res, err
+}
+
+// AddPortForward forwards a port of the host to the VM until the daemon exits
+func (c *Client) AddPortForward(forward api.PortForward) (api.PortForward, error) {
+	var res api.PortForward
+	err := c.do(http.MethodPost, "/port-forwards", forward, &res)
+	return res, err
+}
+
+// RemovePortForward stops forwarding a port of the host to the VM
+func (c *Client) RemovePortForward(hostPort int) error {
+	return c.do(http.MethodDelete, fmt.Sprintf("/port-forwards/%d", hostPort), nil, nil)
+}
+
+// do sends a request to the daemon and decodes the JSON response in out.
+// out is filled even when the daemon answers with an error status code, as
+// the results carry their own error description.
+func (c *Client) do(method, path string, in interface{}, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.base+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Err string
+		}
+		if err := json.Unmarshal(data, &apiErr); err == nil && apiErr.Err != "" {
+			return fmt.Errorf("Error from the daemon (%s): %s", res.Status, apiErr.Err)
+		}
+	}
+	if out == nil && res.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("Unexpected response from the daemon (%s): %s", res.Status, string(data))
+	}
+	return nil
+}
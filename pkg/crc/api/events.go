@@ -0,0 +1,113 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/code-ready/crc/pkg/crc/machine"
+)
+
+// StartProgressEvent is the type of the events sent while a start request is running
+const StartProgressEvent = "start-progress"
+
+// Event is sent to the clients subscribed to the events route
+type Event struct {
+	Type string
+	Data json.RawMessage
+}
+
+// StartProgress is the data of a StartProgressEvent
+type StartProgress struct {
+	Step       string `json:"step"`
+	Percentage int    `json:"percentage"`
+	Message    string `json:"message"`
+}
+
+// eventBroadcaster sends the published events to all the current subscribers.
+// Slow subscribers miss events instead of blocking the publisher.
+type eventBroadcaster struct {
+	lock        sync.Mutex
+	subscribers map[chan Event]struct{}
+}
+
+func newEventBroadcaster() *eventBroadcaster {
+	return &eventBroadcaster{
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+func (b *eventBroadcaster) subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, 100)
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.subscribers[ch] = struct{}{}
+	return ch, func() {
+		b.lock.Lock()
+		defer b.lock.Unlock()
+		delete(b.subscribers, ch)
+	}
+}
+
+func (b *eventBroadcaster) publish(eventType string, data interface{}) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		logging.Errorf("Cannot encode %s event: %v", eventType, err)
+		return
+	}
+	event := Event{
+		Type: eventType,
+		Data: raw,
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			logging.Debugf("Event subscriber is too slow, dropping %s event", eventType)
+		}
+	}
+}
+
+func (b *eventBroadcaster) startProgress() machine.ProgressReporter {
+	return func(progress machine.StartProgress) {
+		b.publish(StartProgressEvent, StartProgress{
+			Step:       progress.Step,
+			Percentage: progress.Percentage,
+			Message:    progress.Message,
+		})
+	}
+}
+
+// streamEvents streams the published events to the client as server-sent events
+// until the client disconnects
+func (api Server) streamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "Streaming is not supported")
+		return
+	}
+	events, unsubscribe := api.events.subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-events:
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, event.Data); err != nil {
+				logging.Debugf("Cannot send event: %v", err)
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
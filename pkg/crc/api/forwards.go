@@ -0,0 +1,101 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrPortForwardNotFound is returned by a PortForwarder when removing a port which is not forwarded
+	ErrPortForwardNotFound = errors.New("Port is not forwarded")
+	// ErrPortForwardExists is returned by a PortForwarder when adding a port which is already forwarded
+	ErrPortForwardExists = errors.New("Port is already forwarded")
+	// ErrPortForwardReserved is returned by a PortForwarder when removing a port forwarded by crc itself
+	ErrPortForwardReserved = errors.New("Port is forwarded by crc and cannot be removed")
+)
+
+// PortForward forwards connections to HostPort on the host to VMPort in the VM
+type PortForward struct {
+	HostPort int `json:"hostPort"`
+	VMPort   int `json:"vmPort"`
+}
+
+// PortForwarder adds and removes port forwards while the VM is running
+type PortForwarder interface {
+	Expose(forward PortForward) error
+	Unexpose(hostPort int) error
+	List() ([]PortForward, error)
+}
+
+func validatePort(port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("Invalid port %d, it must be between 1 and 65535", port)
+	}
+	return nil
+}
+
+// portForwards serves GET and POST /port-forwards
+func (api Server) portForwards(w http.ResponseWriter, r *http.Request) {
+	if api.forwarder == nil {
+		writeError(w, http.StatusNotImplemented, "Port forwarding is not available with this network mode")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		forwards, err := api.forwarder.List()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if forwards == nil {
+			forwards = []PortForward{}
+		}
+		writeJSON(w, http.StatusOK, forwards)
+	case http.MethodPost:
+		var forward PortForward
+		if !api.decodeBody(w, r, &forward) {
+			return
+		}
+		for _, port := range []int{forward.HostPort, forward.VMPort} {
+			if err := validatePort(port); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		switch err := api.forwarder.Expose(forward); err {
+		case nil:
+			writeJSON(w, http.StatusCreated, forward)
+		case ErrPortForwardExists:
+			writeError(w, http.StatusConflict, fmt.Sprintf("Cannot forward port %d: %v", forward.HostPort, err))
+		default:
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("Cannot forward port %d: %v", forward.HostPort, err))
+		}
+	}
+}
+
+// portForward serves DELETE /port-forwards/{hostPort}
+func (api Server) portForward(w http.ResponseWriter, r *http.Request) {
+	if api.forwarder == nil {
+		writeError(w, http.StatusNotImplemented, "Port forwarding is not available with this network mode")
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, APIPrefix+"/port-forwards/")
+	hostPort, err := strconv.Atoi(path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Unknown route: %s", r.URL.Path))
+		return
+	}
+	switch err := api.forwarder.Unexpose(hostPort); err {
+	case nil:
+		w.WriteHeader(http.StatusNoContent)
+	case ErrPortForwardNotFound:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Cannot remove port %d: %v", hostPort, err))
+	case ErrPortForwardReserved:
+		writeError(w, http.StatusForbidden, fmt.Sprintf("Cannot remove port %d: %v", hostPort, err))
+	default:
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("Cannot remove port %d: %v", hostPort, err))
+	}
+}
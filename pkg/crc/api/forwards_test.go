@@ -0,0 +1,80 @@
+// +build !windows
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/code-ready/crc/pkg/crc/machine/fakemachine"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeForwarder struct {
+	forwards []PortForward
+}
+
+func (f *fakeForwarder) Expose(forward PortForward) error {
+	for _, existing := range f.forwards {
+		if existing.HostPort == forward.HostPort {
+			return ErrPortForwardExists
+		}
+	}
+	f.forwards = append(f.forwards, forward)
+	return nil
+}
+
+func (f *fakeForwarder) Unexpose(hostPort int) error {
+	if hostPort == 443 {
+		return ErrPortForwardReserved
+	}
+	for i, forward := range f.forwards {
+		if forward.HostPort == hostPort {
+			f.forwards = append(f.forwards[:i], f.forwards[i+1:]...)
+			return nil
+		}
+	}
+	return ErrPortForwardNotFound
+}
+
+func (f *fakeForwarder) List() ([]PortForward, error) {
+	return f.forwards, nil
+}
+
+func TestPortForwards(t *testing.T) {
+	forwarder := &fakeForwarder{forwards: []PortForward{{HostPort: 443, VMPort: 443}}}
+	socket, cleanup := setupAPIServerWithForwarder(t, fakemachine.NewClient(), forwarder)
+	defer cleanup()
+
+	status, body := sendRequest(t, socket, http.MethodPost, "/port-forwards", json.RawMessage(`{"hostPort":8080,"vmPort":30080}`))
+	assert.Equal(t, http.StatusCreated, status)
+	assert.JSONEq(t, `{"hostPort":8080,"vmPort":30080}`, string(body))
+
+	status, _ = sendRequest(t, socket, http.MethodPost, "/port-forwards", json.RawMessage(`{"hostPort":8080,"vmPort":30081}`))
+	assert.Equal(t, http.StatusConflict, status)
+
+	status, _ = sendRequest(t, socket, http.MethodPost, "/port-forwards", json.RawMessage(`{"hostPort":70000,"vmPort":30081}`))
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, body = sendRequest(t, socket, http.MethodGet, "/port-forwards", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `[{"hostPort":443,"vmPort":443},{"hostPort":8080,"vmPort":30080}]`, string(body))
+
+	status, _ = sendRequest(t, socket, http.MethodDelete, "/port-forwards/8080", nil)
+	assert.Equal(t, http.StatusNoContent, status)
+
+	status, _ = sendRequest(t, socket, http.MethodDelete, "/port-forwards/8080", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, _ = sendRequest(t, socket, http.MethodDelete, "/port-forwards/443", nil)
+	assert.Equal(t, http.StatusForbidden, status)
+}
+
+func TestPortForwardsUnavailable(t *testing.T) {
+	socket, cleanup := setupAPIServer(t)
+	defer cleanup()
+
+	status, _ := sendRequest(t, socket, http.MethodGet, "/port-forwards", nil)
+	assert.Equal(t, http.StatusNotImplemented, status)
+}
@@ -2,14 +2,15 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/code-ready/crc/cmd/crc/cmd/config"
 	"github.com/code-ready/crc/pkg/crc/cluster"
 	crcConfig "github.com/code-ready/crc/pkg/crc/config"
 	"github.com/code-ready/crc/pkg/crc/errors"
-	"github.com/code-ready/crc/pkg/crc/logging"
 	"github.com/code-ready/crc/pkg/crc/machine"
 	"github.com/code-ready/crc/pkg/crc/preflight"
 	"github.com/code-ready/crc/pkg/crc/version"
@@ -18,61 +19,93 @@ import (
 type Handler struct {
 	MachineClient AdaptedClient
 	Config        crcConfig.Storage
+
+	events *eventBroadcaster
+}
+
+func (h *Handler) Status(ctx context.Context) ClusterStatusResult {
+	return h.MachineClient.Status(ctx)
 }
 
-func (h *Handler) Status() string {
-	clusterStatus := h.MachineClient.Status()
-	return encodeStructToJSON(clusterStatus)
+func (h *Handler) Stop(ctx context.Context) StopResult {
+	// the stop timeout is validated when it is set
+	timeout, _ := time.ParseDuration(h.Config.Get(config.StopTimeout).AsString())
+	return h.MachineClient.Stop(ctx, machine.StopConfig{Timeout: timeout})
 }
 
-func (h *Handler) Stop() string {
-	commandResult := h.MachineClient.Stop()
-	return encodeStructToJSON(commandResult)
+func (h *Handler) Pause(ctx context.Context) Result {
+	return h.MachineClient.Pause(ctx)
 }
 
-func (h *Handler) Start(args json.RawMessage) string {
-	var parsedArgs startArgs
+func (h *Handler) Resume(ctx context.Context) Result {
+	return h.MachineClient.Resume(ctx)
+}
+
+func (h *Handler) Start(ctx context.Context, args json.RawMessage) StartResult {
+	var parsedArgs StartArgs
 	var err error
 	if args != nil {
 		parsedArgs, err = parseStartArgs(args)
 		if err != nil {
-			startErr := &StartResult{
+			return StartResult{
 				Name:  h.MachineClient.GetName(),
 				Error: fmt.Sprintf("Incorrect arguments given: %s", err.Error()),
 			}
-			return encodeStructToJSON(startErr)
 		}
 	}
 	if err := preflight.StartPreflightChecks(h.Config); err != nil {
-		startErr := &StartResult{
+		return StartResult{
 			Name:  h.MachineClient.GetName(),
 			Error: err.Error(),
 		}
-		return encodeStructToJSON(startErr)
 	}
 
 	startConfig := getStartConfig(h.Config, parsedArgs)
-	status := h.MachineClient.Start(startConfig)
-	return encodeStructToJSON(status)
+	if h.events != nil {
+		startConfig.Progress = h.events.startProgress()
+	}
+	return h.MachineClient.Start(ctx, startConfig)
 }
 
-func parseStartArgs(args json.RawMessage) (startArgs, error) {
-	var parsedArgs startArgs
+func parseStartArgs(args json.RawMessage) (StartArgs, error) {
+	var parsedArgs StartArgs
 	dec := json.NewDecoder(bytes.NewReader(args))
 	dec.DisallowUnknownFields()
 	if err := dec.Decode(&parsedArgs); err != nil {
-		return startArgs{}, err
+		return StartArgs{}, err
 	}
 	return parsedArgs, nil
 }
 
-func getStartConfig(cfg crcConfig.Storage, args startArgs) machine.StartConfig {
+// StartConfigSettings are the settings read by getStartConfig, the daemon
+// starts the cluster with their configured values
+var StartConfigSettings = []string{
+	config.Bundle,
+	config.Memory,
+	config.CPUs,
+	config.DiskSize,
+	config.NameServer,
+	config.Wait,
+	config.WaitTimeout,
+	config.WaitOperators,
+}
+
+func getStartConfig(cfg crcConfig.Storage, args StartArgs) machine.StartConfig {
+	// the wait timeout is validated when it is set
+	waitTimeout, _ := time.ParseDuration(cfg.Get(config.WaitTimeout).AsString())
 	return machine.StartConfig{
 		BundlePath: cfg.Get(config.Bundle).AsString(),
 		Memory:     cfg.Get(config.Memory).AsInt(),
 		CPUs:       cfg.Get(config.CPUs).AsInt(),
+		DiskSize:   cfg.Get(config.DiskSize).AsInt(),
 		NameServer: cfg.Get(config.NameServer).AsString(),
 		PullSecret: cluster.NewNonInteractivePullSecretLoader(cfg, args.PullSecretFile),
+
+		Wait:          cfg.Get(config.Wait).AsString(),
+		WaitTimeout:   waitTimeout,
+		WaitOperators: cluster.ParseClusterOperators(cfg.Get(config.WaitOperators).AsString()),
+
+		FromStep: args.FromStep,
 	}
 }
 
@@ -83,48 +116,36 @@ type VersionResult struct {
 	Success          bool
 }
 
-func (h *Handler) GetVersion() string {
-	v := &VersionResult{
+func (h *Handler) GetVersion() VersionResult {
+	return VersionResult{
 		CrcVersion:       version.GetCRCVersion(),
 		CommitSha:        version.GetCommitSha(),
 		OpenshiftVersion: version.GetBundleVersion(),
 		Success:          true,
 	}
-	return encodeStructToJSON(v)
 }
 
-func (h *Handler) Delete() string {
-	r := h.MachineClient.Delete()
-	return encodeStructToJSON(r)
+func (h *Handler) Delete(ctx context.Context) Result {
+	return h.MachineClient.Delete(ctx)
 }
 
-func (h *Handler) GetWebconsoleInfo() string {
-	r := h.MachineClient.GetConsoleURL()
-	return encodeStructToJSON(r)
+func (h *Handler) GetWebconsoleInfo(ctx context.Context) ConsoleResult {
+	return h.MachineClient.GetConsoleURL(ctx)
 }
 
-func (h *Handler) SetConfig(args json.RawMessage) string {
-	setConfigResult := setOrUnsetConfigResult{}
-	if args == nil {
+func (h *Handler) SetConfig(args SetConfigArgs) SetOrUnsetConfigResult {
+	setConfigResult := SetOrUnsetConfigResult{}
+	if len(args.Properties) == 0 {
 		setConfigResult.Error = "No config keys provided"
-		return encodeStructToJSON(setConfigResult)
+		return setConfigResult
 	}
 
 	var multiError = errors.MultiError{}
-	var a = make(map[string]interface{})
-
-	err := json.Unmarshal(args, &a)
-	if err != nil {
-		setConfigResult.Error = fmt.Sprintf("%v", err)
-		return encodeStructToJSON(setConfigResult)
-	}
-
-	configs := a["properties"].(map[string]interface{})
 
 	// successProps slice contains the properties that were successfully set
 	var successProps []string
 
-	for k, v := range configs {
+	for k, v := range args.Properties {
 		_, err := h.Config.Set(k, v)
 		if err != nil {
 			multiError.Collect(err)
@@ -138,30 +159,22 @@ func (h *Handler) SetConfig(args json.RawMessage) string {
 	}
 
 	setConfigResult.Properties = successProps
-	return encodeStructToJSON(setConfigResult)
+	return setConfigResult
 }
 
-func (h *Handler) UnsetConfig(args json.RawMessage) string {
-	unsetConfigResult := setOrUnsetConfigResult{}
-	if args == nil {
+func (h *Handler) UnsetConfig(args UnsetConfigArgs) SetOrUnsetConfigResult {
+	unsetConfigResult := SetOrUnsetConfigResult{}
+	if len(args.Properties) == 0 {
 		unsetConfigResult.Error = "No config keys provided"
-		return encodeStructToJSON(unsetConfigResult)
+		return unsetConfigResult
 	}
 
 	var multiError = errors.MultiError{}
-	var keys = make(map[string][]string)
-
-	err := json.Unmarshal(args, &keys)
-	if err != nil {
-		unsetConfigResult.Error = fmt.Sprintf("%v", err)
-		return encodeStructToJSON(unsetConfigResult)
-	}
 
 	// successProps slice contains the properties that were successfully unset
 	var successProps []string
 
-	keysToUnset := keys["properties"]
-	for _, key := range keysToUnset {
+	for _, key := range args.Properties {
 		_, err := h.Config.Unset(key)
 		if err != nil {
 			multiError.Collect(err)
@@ -173,11 +186,13 @@ func (h *Handler) UnsetConfig(args json.RawMessage) string {
 		unsetConfigResult.Error = fmt.Sprintf("%v", multiError)
 	}
 	unsetConfigResult.Properties = successProps
-	return encodeStructToJSON(unsetConfigResult)
+	return unsetConfigResult
 }
 
-func (h *Handler) GetConfig(args json.RawMessage) string {
-	configResult := getConfigResult{}
+// GetConfig returns the value of the requested configuration properties,
+// or of all the known properties when args is nil
+func (h *Handler) GetConfig(args *GetConfigArgs) GetConfigResult {
+	configResult := GetConfigResult{}
 	if args == nil {
 		allConfigs := h.Config.AllConfigs()
 		configResult.Error = ""
@@ -185,22 +200,12 @@ func (h *Handler) GetConfig(args json.RawMessage) string {
 		for k, v := range allConfigs {
 			configResult.Configs[k] = v.Value
 		}
-		return encodeStructToJSON(configResult)
+		return configResult
 	}
 
-	var a = make(map[string][]string)
-
-	err := json.Unmarshal(args, &a)
-	if err != nil {
-		configResult.Error = fmt.Sprintf("%v", err)
-		return encodeStructToJSON(configResult)
-	}
-
-	keys := a["properties"]
-
 	var configs = make(map[string]interface{})
 
-	for _, key := range keys {
+	for _, key := range args.Properties {
 		v := h.Config.Get(key)
 		if v.Invalid {
 			continue
@@ -214,25 +219,5 @@ func (h *Handler) GetConfig(args json.RawMessage) string {
 		configResult.Error = ""
 		configResult.Configs = configs
 	}
-	return encodeStructToJSON(configResult)
-}
-
-func encodeStructToJSON(v interface{}) string {
-	s, err := json.Marshal(v)
-	if err != nil {
-		logging.Error(err.Error())
-		err := commandError{
-			Err: "Failed while encoding JSON to string",
-		}
-		s, _ := json.Marshal(err)
-		return string(s)
-	}
-	return string(s)
-}
-
-func encodeErrorToJSON(errMsg string) string {
-	err := commandError{
-		Err: errMsg,
-	}
-	return encodeStructToJSON(err)
+	return configResult
 }
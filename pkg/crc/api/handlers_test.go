@@ -0,0 +1,51 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	cmdConfig "github.com/code-ready/crc/cmd/crc/cmd/config"
+	"github.com/code-ready/crc/pkg/crc/config"
+	"github.com/code-ready/crc/pkg/crc/machine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStartConfig(t *testing.T) {
+	settings := map[string]interface{}{
+		cmdConfig.Bundle:        "/path/to/crc_libvirt_4.7.0.crcbundle",
+		cmdConfig.Memory:        12288,
+		cmdConfig.CPUs:          6,
+		cmdConfig.DiskSize:      60,
+		cmdConfig.NameServer:    "192.168.1.1",
+		cmdConfig.Wait:          "none",
+		cmdConfig.WaitTimeout:   "20m",
+		cmdConfig.WaitOperators: "dns,ingress",
+	}
+	var names []string
+	for name := range settings {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, StartConfigSettings, names)
+
+	storage := config.NewEmptyInMemoryStorage()
+	cfg := config.New(storage)
+	cmdConfig.RegisterSettings(cfg)
+	for name, value := range settings {
+		require.NoError(t, storage.Set(name, value))
+	}
+
+	startConfig := getStartConfig(cfg, StartArgs{FromStep: "cluster"})
+	startConfig.PullSecret = nil
+	assert.Equal(t, machine.StartConfig{
+		BundlePath:    "/path/to/crc_libvirt_4.7.0.crcbundle",
+		Memory:        12288,
+		CPUs:          6,
+		DiskSize:      60,
+		NameServer:    "192.168.1.1",
+		Wait:          "none",
+		WaitTimeout:   20 * time.Minute,
+		WaitOperators: []string{"dns", "ingress"},
+		FromStep:      "cluster",
+	}, startConfig)
+}
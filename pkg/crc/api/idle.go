@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/code-ready/crc/cmd/crc/cmd/config"
+	crcConfig "github.com/code-ready/crc/pkg/crc/config"
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/code-ready/machine/libmachine/state"
+)
+
+const (
+	// AutoStopEvent is the type of the event sent a grace period before the daemon stops an idle cluster
+	AutoStopEvent = "auto-stop"
+	// AutoStopCancelledEvent is the type of the event sent when the cluster is used again during the grace period
+	AutoStopCancelledEvent = "auto-stop-cancelled"
+)
+
+const (
+	idleCheckInterval = time.Minute
+	// The cluster exchanges some traffic on its own, for instance DNS
+	// queries and update checks. Below this amount of bytes between two
+	// checks, the cluster is considered idle.
+	idleTrafficThreshold = 256 * 1024
+)
+
+// AutoStop is the data of an AutoStopEvent and of an AutoStopCancelledEvent
+type AutoStop struct {
+	IdleMinutes  int    `json:"idleMinutes"`
+	GraceMinutes int    `json:"graceMinutes"`
+	Message      string `json:"message"`
+}
+
+// TrafficCounter returns the number of bytes exchanged with the VM since the daemon started
+type TrafficCounter func() uint64
+
+// idleMonitor keeps track of the last time the traffic with the VM was above the threshold
+type idleMonitor struct {
+	config       crcConfig.Storage
+	counter      TrafficCounter
+	lastCount    uint64
+	lastActivity time.Time
+	// time of the stop announced by an AutoStopEvent, zero when no stop is announced
+	stopAt time.Time
+}
+
+func newIdleMonitor(config crcConfig.Storage, counter TrafficCounter, now time.Time) *idleMonitor {
+	return &idleMonitor{
+		config:       config,
+		counter:      counter,
+		lastCount:    counter(),
+		lastActivity: now,
+	}
+}
+
+// idleFor records the traffic since the previous call and returns for how long the cluster is idle
+func (m *idleMonitor) idleFor(now time.Time) time.Duration {
+	count := m.counter()
+	if count-m.lastCount > idleTrafficThreshold {
+		m.lastActivity = now
+	}
+	m.lastCount = count
+	return now.Sub(m.lastActivity)
+}
+
+// AutoStopWhenIdle stops the cluster when there was almost no traffic with
+// the VM for the number of minutes of the auto-stop-idle-minutes setting. The
+// stop is announced auto-stop-grace-minutes before, it is cancelled if the
+// cluster is used again in the meantime.
+func (api Server) AutoStopWhenIdle(config crcConfig.Storage, counter TrafficCounter) {
+	monitor := newIdleMonitor(config, counter, time.Now())
+	ticker := time.NewTicker(idleCheckInterval)
+	go func() {
+		for now := range ticker.C {
+			api.stopIfIdle(monitor, now)
+		}
+	}()
+}
+
+// stopIfIdle announces the stop of a cluster idle for too long, then queues
+// the stop operation once the grace period is over and returns it
+func (api Server) stopIfIdle(monitor *idleMonitor, now time.Time) *Operation {
+	idle := monitor.idleFor(now)
+	idleMinutes := monitor.config.Get(config.AutoStopIdleMinutes).AsInt()
+	graceMinutes := monitor.config.Get(config.AutoStopGraceMinutes).AsInt()
+	// the idle time only starts counting when auto-stop is enabled and
+	// the cluster is not being started or stopped
+	if idleMinutes <= 0 || api.operations.pending() {
+		monitor.lastActivity = now
+		monitor.stopAt = time.Time{}
+		return nil
+	}
+	if idle < time.Duration(idleMinutes)*time.Minute {
+		if !monitor.stopAt.IsZero() {
+			monitor.stopAt = time.Time{}
+			message := "The OpenShift cluster is used again, it is not stopped"
+			logging.Info(message)
+			api.events.publish(AutoStopCancelledEvent, AutoStop{
+				IdleMinutes:  idleMinutes,
+				GraceMinutes: graceMinutes,
+				Message:      message,
+			})
+		}
+		return nil
+	}
+	if status := api.handler.Status(context.Background()); status.CrcStatus != state.Running.String() {
+		monitor.lastActivity = now
+		monitor.stopAt = time.Time{}
+		return nil
+	}
+
+	if monitor.stopAt.IsZero() {
+		monitor.stopAt = now.Add(time.Duration(graceMinutes) * time.Minute)
+		message := fmt.Sprintf("The OpenShift cluster was idle for %d minutes, it will be stopped in %d minutes", idleMinutes, graceMinutes)
+		logging.Info(message)
+		api.events.publish(AutoStopEvent, AutoStop{
+			IdleMinutes:  idleMinutes,
+			GraceMinutes: graceMinutes,
+			Message:      message,
+		})
+	}
+	if now.Before(monitor.stopAt) {
+		return nil
+	}
+
+	monitor.lastActivity = now
+	monitor.stopAt = time.Time{}
+	logging.Infof("Stopping the OpenShift cluster, it was idle for %d minutes", idleMinutes)
+	op, ok := api.queueOperation("stop", nil)
+	if !ok {
+		logging.Error("Channel capacity reached, unable to stop the idle cluster")
+		return nil
+	}
+	return op
+}
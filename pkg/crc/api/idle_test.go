@@ -0,0 +1,98 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	cmdConfig "github.com/code-ready/crc/cmd/crc/cmd/config"
+	"github.com/code-ready/crc/pkg/crc/config"
+	"github.com/code-ready/crc/pkg/crc/machine/fakemachine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupIdleMonitor(t *testing.T, idleMinutes int) (Server, *idleMonitor, *uint64, time.Time) {
+	cfg := config.New(config.NewEmptyInMemoryStorage())
+	cmdConfig.RegisterSettings(cfg)
+	_, err := cfg.Set(cmdConfig.AutoStopIdleMinutes, idleMinutes)
+	require.NoError(t, err)
+
+	api, err := CreateServerWithListener(nil, cfg, fakemachine.NewClient(), nil, DefaultMaxRequestSize)
+	require.NoError(t, err)
+	var traffic uint64
+	start := time.Now()
+	return api, newIdleMonitor(cfg, func() uint64 { return traffic }, start), &traffic, start
+}
+
+func TestStopIfIdle(t *testing.T) {
+	api, monitor, _, start := setupIdleMonitor(t, 30)
+	events, unsubscribe := api.events.subscribe()
+	defer unsubscribe()
+
+	assert.Nil(t, api.stopIfIdle(monitor, start.Add(29*time.Minute)))
+	assert.Nil(t, api.stopIfIdle(monitor, start.Add(30*time.Minute)))
+	event := <-events
+	assert.Equal(t, AutoStopEvent, event.Type)
+	assert.JSONEq(t, `{"idleMinutes": 30, "graceMinutes": 5, "message": "The OpenShift cluster was idle for 30 minutes, it will be stopped in 5 minutes"}`, string(event.Data))
+	assert.Empty(t, api.operations.list())
+
+	// the stop is announced once
+	assert.Nil(t, api.stopIfIdle(monitor, start.Add(34*time.Minute)))
+	op := api.stopIfIdle(monitor, start.Add(35*time.Minute))
+	require.NotNil(t, op)
+	assert.Equal(t, "stop", op.Command)
+	assert.Equal(t, OperationQueued, op.State)
+	select {
+	case event := <-events:
+		assert.NotEqual(t, AutoStopEvent, event.Type)
+	default:
+	}
+
+	// the stop operation is still queued
+	assert.Nil(t, api.stopIfIdle(monitor, start.Add(90*time.Minute)))
+}
+
+func TestStopIfIdleCancelled(t *testing.T) {
+	api, monitor, traffic, start := setupIdleMonitor(t, 30)
+	events, unsubscribe := api.events.subscribe()
+	defer unsubscribe()
+
+	assert.Nil(t, api.stopIfIdle(monitor, start.Add(30*time.Minute)))
+	assert.Equal(t, AutoStopEvent, (<-events).Type)
+
+	*traffic += idleTrafficThreshold + 1
+	assert.Nil(t, api.stopIfIdle(monitor, start.Add(32*time.Minute)))
+	event := <-events
+	assert.Equal(t, AutoStopCancelledEvent, event.Type)
+	assert.JSONEq(t, `{"idleMinutes": 30, "graceMinutes": 5, "message": "The OpenShift cluster is used again, it is not stopped"}`, string(event.Data))
+
+	assert.Nil(t, api.stopIfIdle(monitor, start.Add(35*time.Minute)))
+	assert.Empty(t, api.operations.list())
+}
+
+func TestStopIfIdleWithoutGracePeriod(t *testing.T) {
+	api, monitor, _, start := setupIdleMonitor(t, 30)
+	_, err := monitor.config.Set(cmdConfig.AutoStopGraceMinutes, 0)
+	require.NoError(t, err)
+
+	assert.NotNil(t, api.stopIfIdle(monitor, start.Add(30*time.Minute)))
+}
+
+func TestStopIfIdleWithTraffic(t *testing.T) {
+	api, monitor, traffic, start := setupIdleMonitor(t, 30)
+
+	*traffic += idleTrafficThreshold / 2
+	assert.Nil(t, api.stopIfIdle(monitor, start.Add(10*time.Minute)))
+	*traffic += idleTrafficThreshold + 1
+	assert.Nil(t, api.stopIfIdle(monitor, start.Add(20*time.Minute)))
+	assert.Nil(t, api.stopIfIdle(monitor, start.Add(49*time.Minute)))
+	assert.Nil(t, api.stopIfIdle(monitor, start.Add(50*time.Minute)))
+	assert.NotNil(t, api.stopIfIdle(monitor, start.Add(55*time.Minute)))
+}
+
+func TestStopIfIdleDisabled(t *testing.T) {
+	api, monitor, _, start := setupIdleMonitor(t, 0)
+
+	assert.Nil(t, api.stopIfIdle(monitor, start.Add(24*time.Hour)))
+	assert.Empty(t, api.operations.list())
+}
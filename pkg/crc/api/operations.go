@@ -0,0 +1,219 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/pborman/uuid"
+)
+
+// OperationEvent is the type of the events sent when an operation changes state
+const OperationEvent = "operation"
+
+// defaultOperationsHistorySize is the number of finished operations kept in memory
+const defaultOperationsHistorySize = 50
+
+type OperationState string
+
+const (
+	OperationQueued   OperationState = "queued"
+	OperationRunning  OperationState = "running"
+	OperationFinished OperationState = "finished"
+)
+
+// Operation is a start, stop or delete request which is run asynchronously
+// by the daemon. Result is only set once the operation is finished and holds
+// a StartResult for start operations or a Result for stop and delete operations.
+type Operation struct {
+	ID         string
+	Command    string
+	State      OperationState
+	Success    bool
+	Error      string
+	Cancelled  bool            `json:",omitempty"`
+	Result     json.RawMessage `json:",omitempty"`
+	QueuedAt   time.Time
+	StartedAt  *time.Time `json:",omitempty"`
+	FinishedAt *time.Time `json:",omitempty"`
+}
+
+// operations keeps track of the queued and running operations, and of
+// the last finished ones
+type operations struct {
+	lock        sync.Mutex
+	all         []*Operation
+	historySize int
+	events      *eventBroadcaster
+	// cancelFuncs holds the cancel function of the running operations
+	cancelFuncs map[string]context.CancelFunc
+}
+
+func newOperations(historySize int, events *eventBroadcaster) *operations {
+	return &operations{
+		historySize: historySize,
+		events:      events,
+		cancelFuncs: make(map[string]context.CancelFunc),
+	}
+}
+
+func (o *operations) add(command string) *Operation {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	op := &Operation{
+		ID:       uuid.New(),
+		Command:  command,
+		State:    OperationQueued,
+		QueuedAt: time.Now(),
+	}
+	o.all = append(o.all, op)
+	o.evict()
+	o.publish(op)
+	return op
+}
+
+// setRunning marks the operation as running and returns the context it must run with.
+// It returns false when the operation is unknown or was cancelled while it was queued.
+func (o *operations) setRunning(id string) (context.Context, bool) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	op := o.find(id)
+	if op == nil || op.State != OperationQueued {
+		return nil, false
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	o.cancelFuncs[id] = cancel
+	now := time.Now()
+	op.State = OperationRunning
+	op.StartedAt = &now
+	o.publish(op)
+	return ctx, true
+}
+
+func (o *operations) setFinished(id string, success bool, errMsg string, result interface{}) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if cancel, ok := o.cancelFuncs[id]; ok {
+		cancel()
+		delete(o.cancelFuncs, id)
+	}
+	op := o.find(id)
+	if op == nil {
+		return
+	}
+	raw, err := json.Marshal(result)
+	if err != nil {
+		logging.Errorf("Cannot encode result of operation %s: %v", id, err)
+	}
+	now := time.Now()
+	op.State = OperationFinished
+	op.Success = success
+	op.Error = errMsg
+	op.Result = raw
+	op.FinishedAt = &now
+	o.evict()
+	o.publish(op)
+}
+
+var (
+	errUnknownOperation  = errors.New("Unknown operation")
+	errOperationFinished = errors.New("Operation is already finished")
+)
+
+// cancel cancels a queued or running operation. A queued operation is
+// immediately marked as finished, a running one finishes once its
+// context cancellation has been handled.
+func (o *operations) cancel(id string) (Operation, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	op := o.find(id)
+	if op == nil {
+		return Operation{}, errUnknownOperation
+	}
+	switch op.State {
+	case OperationQueued:
+		now := time.Now()
+		op.State = OperationFinished
+		op.Error = "Operation cancelled"
+		op.FinishedAt = &now
+		op.Cancelled = true
+		o.evict()
+		o.publish(op)
+	case OperationRunning:
+		op.Cancelled = true
+		o.cancelFuncs[id]()
+	default:
+		return *op, errOperationFinished
+	}
+	return *op, nil
+}
+
+func (o *operations) get(id string) (Operation, bool) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	op := o.find(id)
+	if op == nil {
+		return Operation{}, false
+	}
+	return *op, true
+}
+
+func (o *operations) list() []Operation {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	ops := make([]Operation, 0, len(o.all))
+	for _, op := range o.all {
+		ops = append(ops, *op)
+	}
+	return ops
+}
+
+// pending returns true when an operation is queued or running
+func (o *operations) pending() bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	for _, op := range o.all {
+		if op.State != OperationFinished {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *operations) find(id string) *Operation {
+	for _, op := range o.all {
+		if op.ID == id {
+			return op
+		}
+	}
+	return nil
+}
+
+// evict drops the oldest finished operations when there are more than historySize of them.
+// Queued and running operations are never evicted.
+func (o *operations) evict() {
+	finished := 0
+	for _, op := range o.all {
+		if op.State == OperationFinished {
+			finished++
+		}
+	}
+	kept := o.all[:0]
+	for _, op := range o.all {
+		if op.State == OperationFinished && finished > o.historySize {
+			finished--
+			continue
+		}
+		kept = append(kept, op)
+	}
+	o.all = kept
+}
+
+func (o *operations) publish(op *Operation) {
+	if o.events != nil {
+		o.events.publish(OperationEvent, *op)
+	}
+}
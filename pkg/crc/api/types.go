@@ -1,8 +1,11 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net"
+	"net/http"
+	"sync"
 )
 
 type commandError struct {
@@ -12,47 +15,68 @@ type commandError struct {
 type Server struct {
 	handler                RequestHandler
 	listener               net.Listener
+	maxRequestSize         int64
+	mux                    *http.ServeMux
+	events                 *eventBroadcaster
+	operations             *operations
 	clusterOpsRequestsChan chan clusterOpsRequest
+	// queueLock serializes the requests sent to clusterOpsRequestsChan
+	queueLock *sync.Mutex
+	forwarder PortForwarder
 }
 
 type RequestHandler interface {
-	Start(json.RawMessage) string
-	Stop() string
-	Status() string
-	Delete() string
-	GetVersion() string
-	SetConfig(json.RawMessage) string
-	UnsetConfig(json.RawMessage) string
-	GetConfig(json.RawMessage) string
-	GetWebconsoleInfo() string
-}
-
-// clusterOpsRequest struct is used to store the command request and associated socket
-type clusterOpsRequest struct {
-	command commandRequest
-	socket  net.Conn
+	Start(context.Context, json.RawMessage) StartResult
+	Stop(context.Context) StopResult
+	Status(context.Context) ClusterStatusResult
+	Delete(context.Context) Result
+	Pause(context.Context) Result
+	Resume(context.Context) Result
+	GetVersion() VersionResult
+	SetConfig(SetConfigArgs) SetOrUnsetConfigResult
+	UnsetConfig(UnsetConfigArgs) SetOrUnsetConfigResult
+	GetConfig(*GetConfigArgs) GetConfigResult
+	GetWebconsoleInfo(context.Context) ConsoleResult
 }
 
-// commandRequest struct is used to decode the json request from tray
-type commandRequest struct {
-	Command string          `json:"command"`
-	Args    json.RawMessage `json:"args,omitempty"`
+// clusterOpsRequest struct is used to store a start, stop, delete, pause or resume request
+// and the ID of the operation tracking it
+type clusterOpsRequest struct {
+	command     string
+	args        json.RawMessage
+	operationID string
 }
 
-// setOrUnsetConfigResult struct is used to return the result of
+// SetOrUnsetConfigResult struct is used to return the result of
 // setconfig/unsetconfig command
-type setOrUnsetConfigResult struct {
+type SetOrUnsetConfigResult struct {
 	Error      string
 	Properties []string
 }
 
-// getConfigResult struct is used to return the result of getconfig command
-type getConfigResult struct {
+// GetConfigResult struct is used to return the result of getconfig command
+type GetConfigResult struct {
 	Error   string
 	Configs map[string]interface{}
 }
 
-// startArgs is used to get the pull secret file path as argument for start handler
-type startArgs struct {
+// SetConfigArgs is used to get the configuration properties to set
+type SetConfigArgs struct {
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// UnsetConfigArgs is used to get the configuration properties to unset
+type UnsetConfigArgs struct {
+	Properties []string `json:"properties"`
+}
+
+// GetConfigArgs is used to get the configuration properties to read
+type GetConfigArgs struct {
+	Properties []string `json:"properties"`
+}
+
+// StartArgs is used to get the pull secret file path and the step to resume from as arguments for start handler
+type StartArgs struct {
 	PullSecretFile string `json:"pullSecretFile"`
+	FromStep       string `json:"fromStep"`
 }
@@ -1,6 +1,7 @@
 package cluster
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -11,8 +12,8 @@ import (
 	"github.com/code-ready/crc/pkg/crc/ssh"
 )
 
-func waitForPendingCSRs(ocConfig oc.Config, signerName string) error {
-	return crcerrors.RetryAfter(8*time.Minute, func() error {
+func waitForPendingCSRs(ctx context.Context, ocConfig oc.Config, signerName string) error {
+	return crcerrors.RetryAfter(ctx, 8*time.Minute, func() error {
 		output, _, err := ocConfig.RunOcCommand("get", "csr")
 		if err != nil {
 			return &crcerrors.RetriableError{Err: err}
@@ -24,22 +25,13 @@ func waitForPendingCSRs(ocConfig oc.Config, signerName string) error {
 	}, time.Second*5)
 }
 
-func ApproveCSRAndWaitForCertsRenewal(sshRunner *ssh.Runner, ocConfig oc.Config, client, server bool) error {
+func ApproveCSRAndWaitForCertsRenewal(ctx context.Context, sshRunner *ssh.Runner, ocConfig oc.Config, client, server bool) error {
 	// First, kubelet starts and tries to connect to API server. If its certificate is expired, it asks for a new one
 	// Admin needs to approve it. The Kubernetes controller manager will then issue the cert, kubelet will fetch it and use it.
 	// Kubelet stores the cert in /var/lib/kubelet/pki/kubelet-client-current.pem
 	if client {
 		logging.Info("Kubelet client certificate has expired, renewing it... [will take up to 8 minutes]")
-		if err := waitForPendingCSRs(ocConfig, kubeletClientSignerName); err != nil {
-			logging.Debugf("Error waiting for pending kube-apiserver-client-kubelet CSR: %v", err)
-			return err
-		}
-		if err := approveNodeCSR(ocConfig, kubeletClientSignerName); err != nil {
-			logging.Debugf("Error approving pending kube-apiserver-client-kubelet CSR: %v", err)
-			return err
-		}
-		if err := crcerrors.RetryAfter(5*time.Minute, waitForCertRenewal(sshRunner, KubeletClientCert), time.Second*5); err != nil {
-			logging.Debugf("Error approving pending kube-apiserver-client-kubelet CSR: %v", err)
+		if err := approveKubeletClientCSR(ctx, sshRunner, ocConfig); err != nil {
 			return err
 		}
 	}
@@ -49,16 +41,36 @@ func ApproveCSRAndWaitForCertsRenewal(sshRunner *ssh.Runner, ocConfig oc.Config,
 	// This CSR is automatically approved by the cluster-machine-approver. The k8s controller manager issues the cert and kubelet fetches it.
 	if server {
 		logging.Info("Kubelet serving certificate has expired, waiting for automatic renewal... [will take up to 8 minutes]")
-		return crcerrors.RetryAfter(5*time.Minute, waitForCertRenewal(sshRunner, KubeletServerCert), time.Second*5)
+		return crcerrors.RetryAfter(ctx, 5*time.Minute, waitForCertRenewal(sshRunner, KubeletServerCert), time.Second*5)
+	}
+	return nil
+}
+
+// approveKubeletClientCSR approves the CSR of the kubelet client certificate
+// and waits for kubelet to fetch the certificate
+func approveKubeletClientCSR(ctx context.Context, sshRunner *ssh.Runner, ocConfig oc.Config) error {
+	if err := waitForPendingCSRs(ctx, ocConfig, kubeletClientSignerName); err != nil {
+		logging.Debugf("Error waiting for pending kube-apiserver-client-kubelet CSR: %v", err)
+		return err
+	}
+	if err := approveNodeCSR(ocConfig, kubeletClientSignerName); err != nil {
+		logging.Debugf("Error approving pending kube-apiserver-client-kubelet CSR: %v", err)
+		return err
+	}
+	if err := crcerrors.RetryAfter(ctx, 5*time.Minute, waitForCertRenewal(sshRunner, KubeletClientCert), time.Second*5); err != nil {
+		logging.Debugf("Error approving pending kube-apiserver-client-kubelet CSR: %v", err)
+		return err
 	}
 	return nil
 }
 
+// waitForCertRenewal returns nil once cert exists and is valid, kubelet may
+// not have written the new certificate yet
 func waitForCertRenewal(sshRunner *ssh.Runner, cert string) func() error {
 	return func() error {
 		expired, err := checkCertValidity(sshRunner, cert)
 		if err != nil {
-			return err
+			return &crcerrors.RetriableError{Err: err}
 		}
 		if !expired {
 			return nil
@@ -0,0 +1,196 @@
+package cluster
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	crcerrors "github.com/code-ready/crc/pkg/crc/errors"
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/code-ready/crc/pkg/crc/oc"
+	"github.com/code-ready/crc/pkg/crc/ssh"
+	"github.com/code-ready/crc/pkg/crc/systemd"
+)
+
+const (
+	apiServingCert = "/etc/kubernetes/static-pod-resources/kube-apiserver-certs/secrets/external-loadbalancer-serving-certkey/tls.crt"
+
+	ingressServingCertNamespace = "openshift-ingress"
+	ingressServingCertSecret    = "router-certs-default"
+
+	kubeletServingSignerName = "kubernetes.io/kubelet-serving"
+
+	aggregatorClientSignerNamespace = "openshift-kube-apiserver-operator"
+	aggregatorClientSignerSecret    = "aggregator-client-signer"
+)
+
+// Certificate gives the expiry date of a certificate of the cluster. Location
+// is the path of the certificate in the VM or the secret holding it.
+type Certificate struct {
+	Name      string
+	Location  string
+	ExpiresAt time.Time
+}
+
+// GetCertificates returns the expiry dates of the certificates which must
+// be valid for the cluster to start and to be reachable from the host
+func GetCertificates(sshRunner *ssh.Runner, ocConfig oc.Config) ([]Certificate, error) {
+	var certs []Certificate
+	for _, cert := range []struct{ name, path string }{
+		{"Kubelet client", KubeletClientCert},
+		{"Kubelet server", KubeletServerCert},
+		{"Aggregator client CA", AggregatorClientCert},
+		{"API server serving", apiServingCert},
+	} {
+		expiryDate, err := getCertExpiryDate(sshRunner, cert.path)
+		if err != nil {
+			return nil, fmt.Errorf("Cannot get the expiry date of %s: %v", cert.path, err)
+		}
+		certs = append(certs, Certificate{
+			Name:      cert.name,
+			Location:  cert.path,
+			ExpiresAt: expiryDate,
+		})
+	}
+
+	expiryDate, err := getSecretCertExpiryDate(ocConfig, ingressServingCertNamespace, ingressServingCertSecret)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot get the expiry date of the ingress certificate: %v", err)
+	}
+	return append(certs, Certificate{
+		Name:      "Ingress serving",
+		Location:  fmt.Sprintf("secret %s/%s", ingressServingCertNamespace, ingressServingCertSecret),
+		ExpiresAt: expiryDate,
+	}), nil
+}
+
+func getSecretCertExpiryDate(ocConfig oc.Config, namespace, name string) (time.Time, error) {
+	output, stderr, err := ocConfig.RunOcCommandPrivate("get", "secret", name, "-n", namespace, "-o", "json")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%v: %s", err, stderr)
+	}
+	var secret struct {
+		Data map[string][]byte `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(output), &secret); err != nil {
+		return time.Time{}, err
+	}
+	block, _ := pem.Decode(secret.Data["tls.crt"])
+	if block == nil {
+		return time.Time{}, fmt.Errorf("secret %s/%s does not contain a PEM certificate", namespace, name)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
+
+// RenewKubeletCerts forces the renewal of the kubelet certificates of a
+// running cluster. The current certificates are moved aside and kubelet is
+// restarted, it then asks for new ones. They are put back if the renewal
+// fails, so that the cluster keeps working until they expire.
+func RenewKubeletCerts(ctx context.Context, sshRunner *ssh.Runner, ocConfig oc.Config) error {
+	certs := []string{KubeletClientCert, KubeletServerCert}
+	sd := systemd.NewInstanceSystemdCommander(sshRunner)
+	if err := sd.Stop("kubelet"); err != nil {
+		return err
+	}
+	for _, cert := range certs {
+		if _, stderr, err := sshRunner.Run(fmt.Sprintf("sudo mv -f %[1]s %[1]s.old", cert)); err != nil {
+			restoreKubeletCerts(sshRunner, certs)
+			return fmt.Errorf("Cannot move %s aside: %v: %s", cert, err, stderr)
+		}
+	}
+	if err := sd.Start("kubelet"); err != nil {
+		restoreKubeletCerts(sshRunner, certs)
+		return err
+	}
+
+	if err := waitForKubeletCerts(ctx, sshRunner, ocConfig); err != nil {
+		restoreKubeletCerts(sshRunner, certs)
+		if restartErr := sd.Restart("kubelet"); restartErr != nil {
+			logging.Debugf("Cannot restart kubelet: %v", restartErr)
+		}
+		return err
+	}
+	for _, cert := range certs {
+		if _, _, err := sshRunner.Run(fmt.Sprintf("sudo rm -f %s.old", cert)); err != nil {
+			logging.Debugf("Cannot remove %s.old: %v", cert, err)
+		}
+	}
+	return nil
+}
+
+func waitForKubeletCerts(ctx context.Context, sshRunner *ssh.Runner, ocConfig oc.Config) error {
+	logging.Info("Waiting for a new kubelet client certificate... [will take up to 8 minutes]")
+	if err := approveKubeletClientCSR(ctx, sshRunner, ocConfig); err != nil {
+		return fmt.Errorf("Error waiting for the kubelet client certificate: %v", err)
+	}
+
+	// the kubelet serving CSR is usually approved by the cluster-machine-approver,
+	// it is approved here in case the approver is not running yet
+	logging.Info("Waiting for a new kubelet serving certificate...")
+	return crcerrors.RetryAfter(ctx, 5*time.Minute, func() error {
+		if err := approveNodeCSR(ocConfig, kubeletServingSignerName); err != nil {
+			logging.Debugf("Error approving pending kubelet-serving CSR: %v", err)
+		}
+		return waitForCertRenewal(sshRunner, KubeletServerCert)()
+	}, time.Second*5)
+}
+
+// RenewAggregatorClientCA forces the kube-apiserver-operator to regenerate
+// the aggregator client signer, it then updates the CA bundle read by the API
+// servers. The openshift-apiserver pods are restarted to load the new CA, as
+// done by 'crc start' when the CA expired.
+func RenewAggregatorClientCA(ctx context.Context, sshRunner *ssh.Runner, ocConfig oc.Config) error {
+	checksum, err := aggregatorClientCAChecksum(sshRunner)
+	if err != nil {
+		return err
+	}
+	patch := `{"metadata":{"annotations":{"auth.openshift.io/certificate-not-after":null}}}`
+	if _, stderr, err := ocConfig.RunOcCommand("patch", "secret", aggregatorClientSignerSecret, "-n", aggregatorClientSignerNamespace,
+		"-p", fmt.Sprintf("'%s'", patch), "--type", "merge"); err != nil {
+		return fmt.Errorf("Cannot force the renewal of the aggregator client signer: %v: %s", err, stderr)
+	}
+
+	logging.Info("Waiting for a new aggregator client CA... [will take up to 8 minutes]")
+	if err := crcerrors.RetryAfter(ctx, 8*time.Minute, func() error {
+		newChecksum, err := aggregatorClientCAChecksum(sshRunner)
+		if err != nil {
+			return &crcerrors.RetriableError{Err: err}
+		}
+		if newChecksum == checksum {
+			return &crcerrors.RetriableError{Err: fmt.Errorf("%s is not updated yet", AggregatorClientCert)}
+		}
+		return nil
+	}, 5*time.Second); err != nil {
+		return fmt.Errorf("Error waiting for the aggregator client CA: %v", err)
+	}
+	return DeleteOpenshiftAPIServerPods(ctx, ocConfig)
+}
+
+func aggregatorClientCAChecksum(sshRunner *ssh.Runner) (string, error) {
+	output, stderr, err := sshRunner.Run(fmt.Sprintf("sudo sha256sum %s", AggregatorClientCert))
+	if err != nil {
+		return "", fmt.Errorf("Cannot read %s: %v: %s", AggregatorClientCert, err, stderr)
+	}
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("Cannot read the checksum of %s", AggregatorClientCert)
+	}
+	return fields[0], nil
+}
+
+// restoreKubeletCerts puts back the certificates moved aside by RenewKubeletCerts
+func restoreKubeletCerts(sshRunner *ssh.Runner, certs []string) {
+	for _, cert := range certs {
+		if _, stderr, err := sshRunner.Run(fmt.Sprintf("if sudo test -e %[1]s.old; then sudo mv -f %[1]s.old %[1]s; fi", cert)); err != nil {
+			logging.Warnf("Cannot restore %s: %v: %s", cert, err, stderr)
+		}
+	}
+}
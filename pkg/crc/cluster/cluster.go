@@ -1,10 +1,10 @@
 package cluster
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -21,7 +21,7 @@ import (
 // #nosec G101
 const vmPullSecretPath = "/var/lib/kubelet/config.json"
 
-func WaitForSSH(sshRunner *ssh.Runner) error {
+func WaitForSSH(ctx context.Context, sshRunner *ssh.Runner) error {
 	checkSSHConnectivity := func() error {
 		_, _, err := sshRunner.Run("exit 0")
 		if err != nil {
@@ -30,7 +30,7 @@ func WaitForSSH(sshRunner *ssh.Runner) error {
 		return nil
 	}
 
-	return errors.RetryAfter(300*time.Second, checkSSHConnectivity, time.Second)
+	return errors.RetryAfter(ctx, 300*time.Second, checkSSHConnectivity, time.Second)
 }
 
 const (
@@ -55,11 +55,7 @@ func CheckCertsValidity(sshRunner *ssh.Runner) (map[string]bool, error) {
 }
 
 func checkCertValidity(sshRunner *ssh.Runner, cert string) (bool, error) {
-	output, _, err := sshRunner.Run(fmt.Sprintf(`date --date="$(sudo openssl x509 -in %s -noout -enddate | cut -d= -f 2)" --iso-8601=seconds`, cert))
-	if err != nil {
-		return false, err
-	}
-	expiryDate, err := time.Parse(time.RFC3339, strings.TrimSpace(output))
+	expiryDate, err := getCertExpiryDate(sshRunner, cert)
 	if err != nil {
 		return false, err
 	}
@@ -70,6 +66,27 @@ func checkCertValidity(sshRunner *ssh.Runner, cert string) (bool, error) {
 	return false, nil
 }
 
+func getCertExpiryDate(sshRunner *ssh.Runner, cert string) (time.Time, error) {
+	output, _, err := sshRunner.Run(fmt.Sprintf(`date --date="$(sudo openssl x509 -in %s -noout -enddate | cut -d= -f 2)" --iso-8601=seconds`, cert))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(output))
+}
+
+// GetKubeletCertsExpiryDates returns the expiry dates of the kubelet client and server certificates
+func GetKubeletCertsExpiryDates(sshRunner *ssh.Runner) (time.Time, time.Time, error) {
+	clientExpiry, err := getCertExpiryDate(sshRunner, KubeletClientCert)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	serverExpiry, err := getCertExpiryDate(sshRunner, KubeletServerCert)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return clientExpiry, serverExpiry, nil
+}
+
 // Return size of disk, used space in bytes and the mountpoint
 func GetRootPartitionUsage(sshRunner *ssh.Runner) (int64, int64, error) {
 	cmd := "df -B1 --output=size,used,target /sysroot | tail -1"
@@ -91,8 +108,8 @@ func GetRootPartitionUsage(sshRunner *ssh.Runner) (int64, int64, error) {
 	return diskSize, diskUsage, nil
 }
 
-func EnsurePullSecretPresentInTheCluster(ocConfig oc.Config, pullSec PullSecretLoader) error {
-	if err := WaitForOpenshiftResource(ocConfig, "secret"); err != nil {
+func EnsurePullSecretPresentInTheCluster(ctx context.Context, ocConfig oc.Config, pullSec PullSecretLoader) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "secret"); err != nil {
 		return err
 	}
 
@@ -125,8 +142,8 @@ func EnsurePullSecretPresentInTheCluster(ocConfig oc.Config, pullSec PullSecretL
 	return nil
 }
 
-func EnsureClusterIDIsNotEmpty(ocConfig oc.Config) error {
-	if err := WaitForOpenshiftResource(ocConfig, "clusterversion"); err != nil {
+func EnsureClusterIDIsNotEmpty(ctx context.Context, ocConfig oc.Config) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "clusterversion"); err != nil {
 		return err
 	}
 
@@ -151,16 +168,13 @@ func EnsureClusterIDIsNotEmpty(ocConfig oc.Config) error {
 	return nil
 }
 
-func AddProxyConfigToCluster(sshRunner *ssh.Runner, ocConfig oc.Config, proxy *network.ProxyConfig) error {
-	type trustedCA struct {
-		Name string `json:"name"`
-	}
-
+// AddProxyConfigToCluster sets the proxy of the cluster. The proxy CA is part
+// of the bundle set by EnsureTrustedCABundleInCluster.
+func AddProxyConfigToCluster(ctx context.Context, ocConfig oc.Config, proxy *network.ProxyConfig) error {
 	type proxySpecConfig struct {
-		HTTPProxy  string    `json:"httpProxy"`
-		HTTPSProxy string    `json:"httpsProxy"`
-		NoProxy    string    `json:"noProxy"`
-		TrustedCA  trustedCA `json:"trustedCA"`
+		HTTPProxy  string `json:"httpProxy"`
+		HTTPSProxy string `json:"httpsProxy"`
+		NoProxy    string `json:"noProxy"`
 	}
 
 	type patchSpec struct {
@@ -175,19 +189,10 @@ func AddProxyConfigToCluster(sshRunner *ssh.Runner, ocConfig oc.Config, proxy *n
 		},
 	}
 
-	if err := WaitForOpenshiftResource(ocConfig, "proxy"); err != nil {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "proxy"); err != nil {
 		return err
 	}
 
-	if proxy.ProxyCACert != "" {
-		trustedCAName := "user-ca-bundle"
-		logging.Debug("Adding proxy CA cert to cluster")
-		if err := addProxyCACertToCluster(sshRunner, ocConfig, proxy, trustedCAName); err != nil {
-			return err
-		}
-		patch.Spec.TrustedCA = trustedCA{Name: trustedCAName}
-	}
-
 	patchEncode, err := json.Marshal(patch)
 	if err != nil {
 		return fmt.Errorf("Failed to encode to json: %v", err)
@@ -201,34 +206,6 @@ func AddProxyConfigToCluster(sshRunner *ssh.Runner, ocConfig oc.Config, proxy *n
 	return nil
 }
 
-func addProxyCACertToCluster(sshRunner *ssh.Runner, ocConfig oc.Config, proxy *network.ProxyConfig, trustedCAName string) error {
-	proxyConfigMapFileName := fmt.Sprintf("/tmp/%s.json", trustedCAName)
-	proxyCABundleTemplate := `{
-  "apiVersion": "v1",
-  "data": {
-    "ca-bundle.crt": "%s"
-  },
-  "kind": "ConfigMap",
-  "metadata": {
-    "name": "%s",
-    "namespace": "openshift-config"
-  }
-}
-`
-	// Replace the carriage return ("\n" or "\r\n") with literal `\n` string
-	re := regexp.MustCompile(`\r?\n`)
-	p := fmt.Sprintf(proxyCABundleTemplate, re.ReplaceAllString(proxy.ProxyCACert, `\n`), trustedCAName)
-	err := sshRunner.CopyData([]byte(p), proxyConfigMapFileName, 0644)
-	if err != nil {
-		return err
-	}
-	cmdArgs := []string{"apply", "-f", proxyConfigMapFileName}
-	if _, stderr, err := ocConfig.RunOcCommand(cmdArgs...); err != nil {
-		return fmt.Errorf("Failed to add proxy cert details %v: %s", err, stderr)
-	}
-	return nil
-}
-
 // AddProxyToKubeletAndCriO adds the systemd drop-in proxy configuration file to the instance,
 // both services (kubelet and crio) need to be restarted after this change.
 // Since proxy operator is not able to make changes to in the kubelet/crio side,
@@ -245,26 +222,7 @@ Environment=NO_PROXY=.cluster.local,.svc,10.217.0.0/22,10.217.4.0/23,192.168.126
 	if err != nil {
 		return err
 	}
-	err = sshRunner.CopyData([]byte(p), "/etc/systemd/system/kubelet.service.d/10-default-env.conf", 0644)
-	if err != nil {
-		return err
-	}
-
-	if proxy.ProxyCACert != "" {
-		logging.Debug("Adding proxy CA cert to instance")
-		return addProxyCACertToInstance(sshRunner, proxy)
-	}
-	return nil
-}
-
-func addProxyCACertToInstance(sshRunner *ssh.Runner, proxy *network.ProxyConfig) error {
-	if err := sshRunner.CopyData([]byte(proxy.ProxyCACert), "/etc/pki/ca-trust/source/anchors/openshift-config-user-ca-bundle.crt", 0600); err != nil {
-		return err
-	}
-	if _, _, err := sshRunner.Run("sudo update-ca-trust"); err != nil {
-		return err
-	}
-	return nil
+	return sshRunner.CopyData([]byte(p), "/etc/systemd/system/kubelet.service.d/10-default-env.conf", 0644)
 }
 
 type PullSecretMemoizer struct {
@@ -295,7 +253,7 @@ func EnsurePullSecretPresentOnInstanceDisk(sshRunner *ssh.Runner, pullSecret Pul
 	return sshRunner.CopyData([]byte(content), vmPullSecretPath, 0600)
 }
 
-func WaitForRequestHeaderClientCaFile(sshRunner *ssh.Runner) error {
+func WaitForRequestHeaderClientCaFile(ctx context.Context, sshRunner *ssh.Runner) error {
 	lookupRequestHeaderClientCa := func() error {
 		expired, err := checkCertValidity(sshRunner, AggregatorClientCert)
 		if err != nil {
@@ -306,10 +264,10 @@ func WaitForRequestHeaderClientCaFile(sshRunner *ssh.Runner) error {
 		}
 		return nil
 	}
-	return errors.RetryAfter(8*time.Minute, lookupRequestHeaderClientCa, 2*time.Second)
+	return errors.RetryAfter(ctx, 8*time.Minute, lookupRequestHeaderClientCa, 2*time.Second)
 }
 
-func WaitForAPIServer(ocConfig oc.Config) error {
+func WaitForAPIServer(ctx context.Context, ocConfig oc.Config) error {
 	logging.Debugf("Waiting for apiserver availability")
 	waitForAPIServer := func() error {
 		stdout, stderr, err := ocConfig.RunOcCommand("get", "nodes")
@@ -320,11 +278,11 @@ func WaitForAPIServer(ocConfig oc.Config) error {
 		logging.Debug(stdout)
 		return nil
 	}
-	return errors.RetryAfter(3*time.Minute, waitForAPIServer, time.Second)
+	return errors.RetryAfter(ctx, 3*time.Minute, waitForAPIServer, time.Second)
 }
 
-func DeleteOpenshiftAPIServerPods(ocConfig oc.Config) error {
-	if err := WaitForOpenshiftResource(ocConfig, "pod"); err != nil {
+func DeleteOpenshiftAPIServerPods(ctx context.Context, ocConfig oc.Config) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "pod"); err != nil {
 		return err
 	}
 
@@ -337,7 +295,7 @@ func DeleteOpenshiftAPIServerPods(ocConfig oc.Config) error {
 		return nil
 	}
 
-	return errors.RetryAfter(60*time.Second, deleteOpenshiftAPIServerPods, time.Second)
+	return errors.RetryAfter(ctx, 60*time.Second, deleteOpenshiftAPIServerPods, time.Second)
 }
 
 func CheckProxySettingsForOperator(ocConfig oc.Config, proxy *network.ProxyConfig, deployment, namespace string) (bool, error) {
@@ -1,9 +1,14 @@
 package cluster
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
+	crcerrors "github.com/code-ready/crc/pkg/crc/errors"
 	"github.com/code-ready/crc/pkg/crc/logging"
 	"github.com/code-ready/crc/pkg/crc/oc"
 
@@ -25,18 +30,82 @@ func GetClusterOperatorStatus(ocConfig oc.Config, operator string) (*Status, err
 }
 
 func GetClusterOperatorsStatus(ocConfig oc.Config, monitoringEnabled bool) (*Status, error) {
+	return getStatus(ocConfig, ignoredClusterOperators(monitoringEnabled), []string{})
+}
+
+func ignoredClusterOperators(monitoringEnabled bool) []string {
 	ignoredOperators := defaultIgnoredClusterOperators
 	if !monitoringEnabled {
 		ignoredOperators = append(ignoredOperators, "monitoring")
 	}
-	return getStatus(ocConfig, ignoredOperators, []string{})
+	return ignoredOperators
 }
 
-func getStatus(ocConfig oc.Config, ignoreClusterOperators, selector []string) (*Status, error) {
+// WaitForClusterOperators polls the cluster operators until they are all available and
+// not progressing, or until timeout. When operators is empty, all the cluster operators
+// are checked, otherwise only the listed ones are.
+func WaitForClusterOperators(ctx context.Context, ocConfig oc.Config, monitoringEnabled bool, operators []string, timeout, interval time.Duration) error {
+	ignoredOperators := ignoredClusterOperators(monitoringEnabled)
+	return crcerrors.RetryAfter(ctx, timeout, func() error {
+		status, err := getStatus(ocConfig, ignoredOperators, operators)
+		if err != nil {
+			return &crcerrors.RetriableError{Err: err}
+		}
+		if !status.Available || status.Progressing {
+			return &crcerrors.RetriableError{Err: fmt.Errorf("cluster operators are not ready yet (available: %t, progressing: %t)", status.Available, status.Progressing)}
+		}
+		return nil
+	}, interval)
+}
+
+// ParseClusterOperators splits a comma separated list of cluster operator names
+func ParseClusterOperators(list string) []string {
+	var operators []string
+	for _, operator := range strings.Split(list, ",") {
+		if operator = strings.TrimSpace(operator); operator != "" {
+			operators = append(operators, operator)
+		}
+	}
+	return operators
+}
+
+// OperatorStatus is the status of a single cluster operator
+type OperatorStatus struct {
+	Name string
+	Status
+}
+
+// GetClusterOperatorsStatuses returns the status of each cluster operator
+// taken into account by GetClusterOperatorsStatus
+func GetClusterOperatorsStatuses(ocConfig oc.Config, monitoringEnabled bool) ([]OperatorStatus, error) {
+	return getOperatorStatuses(ocConfig, ignoredClusterOperators(monitoringEnabled), []string{})
+}
+
+// AggregateStatus combines the status of several cluster operators: the
+// cluster is available only when all of them are available, and degraded,
+// progressing or disabled as soon as one of them is.
+func AggregateStatus(statuses []OperatorStatus) *Status {
 	cs := &Status{
 		Available: true,
 	}
+	for _, status := range statuses {
+		cs.Available = cs.Available && status.Available
+		cs.Degraded = cs.Degraded || status.Degraded
+		cs.Progressing = cs.Progressing || status.Progressing
+		cs.Disabled = cs.Disabled || status.Disabled
+	}
+	return cs
+}
+
+func getStatus(ocConfig oc.Config, ignoreClusterOperators, selector []string) (*Status, error) {
+	statuses, err := getOperatorStatuses(ocConfig, ignoreClusterOperators, selector)
+	if err != nil {
+		return nil, err
+	}
+	return AggregateStatus(statuses), nil
+}
 
+func getOperatorStatuses(ocConfig oc.Config, ignoreClusterOperators, selector []string) ([]OperatorStatus, error) {
 	data, _, err := ocConfig.RunOcCommandPrivate("get", "co", "-ojson")
 	if err != nil {
 		return nil, err
@@ -47,7 +116,7 @@ func getStatus(ocConfig oc.Config, ignoreClusterOperators, selector []string) (*
 		return nil, err
 	}
 
-	found := false
+	var statuses []OperatorStatus
 	for _, c := range co.Items {
 		if contains(c.ObjectMeta.Name, ignoreClusterOperators) {
 			continue
@@ -55,7 +124,12 @@ func getStatus(ocConfig oc.Config, ignoreClusterOperators, selector []string) (*
 		if len(selector) > 0 && !contains(c.ObjectMeta.Name, selector) {
 			continue
 		}
-		found = true
+		cs := OperatorStatus{
+			Name: c.ObjectMeta.Name,
+			Status: Status{
+				Available: true,
+			},
+		}
 		for _, con := range c.Status.Conditions {
 			switch con.Type {
 			case "Available":
@@ -84,11 +158,12 @@ func getStatus(ocConfig oc.Config, ignoreClusterOperators, selector []string) (*
 				logging.Debugf("Unexpected operator status for %s: %s", c.ObjectMeta.Name, con.Type)
 			}
 		}
+		statuses = append(statuses, cs)
 	}
-	if !found {
+	if len(statuses) == 0 {
 		return nil, errors.New("no cluster operator found")
 	}
-	return cs, nil
+	return statuses, nil
 }
 
 func contains(value string, list []string) bool {
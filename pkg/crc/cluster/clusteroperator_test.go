@@ -1,9 +1,11 @@
 package cluster
 
 import (
+	"context"
 	"io/ioutil"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/code-ready/crc/pkg/crc/oc"
 	"github.com/stretchr/testify/assert"
@@ -31,6 +33,22 @@ func TestGetClusterOperatorsStatusProgressing(t *testing.T) {
 	assert.Equal(t, progressing, status)
 }
 
+func TestGetClusterOperatorsStatuses(t *testing.T) {
+	statuses, err := GetClusterOperatorsStatuses(ocConfig("co-progressing.json"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, progressing, AggregateStatus(statuses))
+	for _, status := range statuses {
+		switch status.Name {
+		case "authentication":
+			assert.Equal(t, *progressing, status.Status)
+		case "cloud-credential":
+			assert.Equal(t, *available, status.Status)
+		case "monitoring":
+			assert.Fail(t, "monitoring operator must be ignored")
+		}
+	}
+}
+
 func TestGetClusterOperatorStatus(t *testing.T) {
 	status, err := GetClusterOperatorStatus(ocConfig("co.json"), "authentication")
 	assert.NoError(t, err)
@@ -50,6 +68,17 @@ func TestGetClusterOperatorStatusNotFound(t *testing.T) {
 	assert.EqualError(t, err, "no cluster operator found")
 }
 
+func TestWaitForClusterOperators(t *testing.T) {
+	assert.NoError(t, WaitForClusterOperators(context.Background(), ocConfig("co.json"), false, nil, time.Second, time.Millisecond))
+	assert.NoError(t, WaitForClusterOperators(context.Background(), ocConfig("co-progressing.json"), false, []string{"cloud-credential"}, time.Second, time.Millisecond))
+	assert.Error(t, WaitForClusterOperators(context.Background(), ocConfig("co-progressing.json"), false, nil, 10*time.Millisecond, time.Millisecond))
+}
+
+func TestParseClusterOperators(t *testing.T) {
+	assert.Nil(t, ParseClusterOperators(""))
+	assert.Equal(t, []string{"authentication", "console"}, ParseClusterOperators("authentication, console,"))
+}
+
 func ocConfig(s string) oc.Config {
 	return oc.Config{
 		Runner: &mockRunner{file: filepath.Join("testdata", s)},
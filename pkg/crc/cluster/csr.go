@@ -1,6 +1,7 @@
 package cluster
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -11,7 +12,7 @@ import (
 	k8scerts "k8s.io/api/certificates/v1beta1"
 )
 
-func WaitForOpenshiftResource(ocConfig oc.Config, resource string) error {
+func WaitForOpenshiftResource(ctx context.Context, ocConfig oc.Config, resource string) error {
 	logging.Debugf("Waiting for availability of resource type '%s'", resource)
 	waitForAPIServer := func() error {
 		stdout, stderr, err := ocConfig.RunOcCommand("get", resource)
@@ -22,7 +23,7 @@ func WaitForOpenshiftResource(ocConfig oc.Config, resource string) error {
 		logging.Debug(stdout)
 		return nil
 	}
-	return crcerrors.RetryAfter(80*time.Second, waitForAPIServer, time.Second)
+	return crcerrors.RetryAfter(ctx, 80*time.Second, waitForAPIServer, time.Second)
 }
 
 // approveNodeCSR approves the certificate for the node.
@@ -0,0 +1,273 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/code-ready/crc/pkg/crc/network"
+	"github.com/code-ready/crc/pkg/crc/oc"
+	"github.com/code-ready/crc/pkg/crc/ssh"
+)
+
+const (
+	// the machine config operator does not manage the crc node, the
+	// registries configuration of crio is written by crc in a drop-in file
+	nodeRegistriesConf = "/etc/containers/registries.conf.d/999-crc-registries.conf"
+
+	registryMirrorsPolicy     = "crc-registry-mirrors"
+	registryMirrorsPolicyFile = "/tmp/crc-registry-mirrors.json"
+	// records the insecure registries set by crc in the cluster image config
+	insecureRegistriesAnnotation = "crc-insecure-registries"
+)
+
+// RegistriesConfig is the registry mirrors and insecure registries used by crio and the cluster
+type RegistriesConfig struct {
+	Mirrors  []network.RegistryMirror
+	Insecure []string
+}
+
+func (registries *RegistriesConfig) isInsecure(location string) bool {
+	for _, registry := range registries.Insecure {
+		if location == registry || strings.HasPrefix(location, registry+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// mirrorsBySource returns the sources in the order of the configuration and their mirrors
+func (registries *RegistriesConfig) mirrorsBySource() ([]string, map[string][]string) {
+	var sources []string
+	mirrors := make(map[string][]string)
+	for _, mirror := range registries.Mirrors {
+		if _, ok := mirrors[mirror.Source]; !ok {
+			sources = append(sources, mirror.Source)
+		}
+		mirrors[mirror.Source] = append(mirrors[mirror.Source], mirror.Mirror)
+	}
+	return sources, mirrors
+}
+
+// registriesConf returns the content of the registries.conf drop-in file of
+// crio, or an empty string when there is nothing to configure. Unlike image
+// content source policies, the mirrors are also used to pull images by tag.
+func registriesConf(registries *RegistriesConfig) string {
+	sources, mirrors := registries.mirrorsBySource()
+	locations := sources
+	for _, registry := range registries.Insecure {
+		if _, ok := mirrors[registry]; !ok {
+			mirrors[registry] = nil
+			locations = append(locations, registry)
+		}
+	}
+
+	var conf strings.Builder
+	for _, location := range locations {
+		fmt.Fprintf(&conf, "[[registry]]\nlocation = %q\ninsecure = %t\n", location, registries.isInsecure(location))
+		for _, mirror := range mirrors[location] {
+			fmt.Fprintf(&conf, "\n[[registry.mirror]]\nlocation = %q\ninsecure = %t\n", mirror, registries.isInsecure(mirror))
+		}
+		conf.WriteString("\n")
+	}
+	return conf.String()
+}
+
+// EnsureRegistriesOnInstance writes the registries configuration of crio, or
+// removes it when there is nothing to configure. It returns true when the
+// configuration changed, crio must then be restarted.
+func EnsureRegistriesOnInstance(sshRunner *ssh.Runner, registries *RegistriesConfig) (bool, error) {
+	conf := registriesConf(registries)
+	current, _, err := sshRunner.Run(fmt.Sprintf("sudo cat %s 2>/dev/null || true", nodeRegistriesConf))
+	if err != nil {
+		return false, err
+	}
+	if current == conf {
+		return false, nil
+	}
+	if conf == "" {
+		logging.Debug("Removing registries configuration from instance")
+		if _, stderr, err := sshRunner.Run(fmt.Sprintf("sudo rm -f %s", nodeRegistriesConf)); err != nil {
+			return false, fmt.Errorf("Failed to remove %s: %v: %s", nodeRegistriesConf, err, stderr)
+		}
+		return true, nil
+	}
+	logging.Debug("Adding registries configuration to instance")
+	if _, stderr, err := sshRunner.Run(fmt.Sprintf("sudo mkdir -p %s", path.Dir(nodeRegistriesConf))); err != nil {
+		return false, fmt.Errorf("Failed to create %s: %v: %s", path.Dir(nodeRegistriesConf), err, stderr)
+	}
+	if err := sshRunner.CopyData([]byte(conf), nodeRegistriesConf, 0644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// EnsureRegistriesInCluster sets the registry mirrors with an image content
+// source policy and the insecure registries in the cluster image config
+func EnsureRegistriesInCluster(ctx context.Context, sshRunner *ssh.Runner, ocConfig oc.Config, registries *RegistriesConfig) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "image.config.openshift.io"); err != nil {
+		return err
+	}
+	if err := ensureRegistryMirrorsInCluster(sshRunner, ocConfig, registries); err != nil {
+		return err
+	}
+	return ensureInsecureRegistriesInCluster(ocConfig, registries.Insecure)
+}
+
+func ensureRegistryMirrorsInCluster(sshRunner *ssh.Runner, ocConfig oc.Config, registries *RegistriesConfig) error {
+	if len(registries.Mirrors) == 0 {
+		if _, stderr, err := ocConfig.RunOcCommand("delete", "imagecontentsourcepolicy", registryMirrorsPolicy, "--ignore-not-found"); err != nil {
+			return fmt.Errorf("Failed to remove the registry mirrors %v: %s", err, stderr)
+		}
+		return nil
+	}
+
+	type repositoryDigestMirrors struct {
+		Source  string   `json:"source"`
+		Mirrors []string `json:"mirrors"`
+	}
+	var digestMirrors []repositoryDigestMirrors
+	sources, mirrors := registries.mirrorsBySource()
+	for _, source := range sources {
+		digestMirrors = append(digestMirrors, repositoryDigestMirrors{
+			Source:  source,
+			Mirrors: mirrors[source],
+		})
+	}
+	policy, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "operator.openshift.io/v1alpha1",
+		"kind":       "ImageContentSourcePolicy",
+		"metadata": map[string]string{
+			"name": registryMirrorsPolicy,
+		},
+		"spec": map[string]interface{}{
+			"repositoryDigestMirrors": digestMirrors,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	logging.Debug("Adding registry mirrors to cluster")
+	if err := sshRunner.CopyData(policy, registryMirrorsPolicyFile, 0644); err != nil {
+		return err
+	}
+	if _, stderr, err := ocConfig.RunOcCommand("apply", "-f", registryMirrorsPolicyFile); err != nil {
+		return fmt.Errorf("Failed to add the registry mirrors %v: %s", err, stderr)
+	}
+	return nil
+}
+
+// ensureInsecureRegistriesInCluster sets the insecure registries of the
+// cluster image config. The registries set by crc are recorded in an
+// annotation so that they can be removed later without touching the
+// registries configured by the user.
+func ensureInsecureRegistriesInCluster(ocConfig oc.Config, insecure []string) error {
+	output, stderr, err := ocConfig.RunOcCommand("get", "image.config.openshift.io", "cluster", "-o", "json")
+	if err != nil {
+		return fmt.Errorf("Failed to get the cluster image config %v: %s", err, stderr)
+	}
+	var imageConfig struct {
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+		Spec struct {
+			RegistrySources struct {
+				InsecureRegistries []string `json:"insecureRegistries"`
+			} `json:"registrySources"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal([]byte(output), &imageConfig); err != nil {
+		return err
+	}
+	previous, annotated := imageConfig.Metadata.Annotations[insecureRegistriesAnnotation]
+	if !annotated && len(insecure) == 0 {
+		return nil
+	}
+	current := imageConfig.Spec.RegistrySources.InsecureRegistries
+	wanted := mergeInsecureRegistries(current, splitRegistries(previous), insecure)
+
+	var annotation, insecureRegistries interface{}
+	if len(insecure) > 0 {
+		annotation = strings.Join(insecure, ",")
+	}
+	if len(wanted) > 0 {
+		insecureRegistries = wanted
+	}
+	if annotated && annotation == previous && equalRegistries(current, wanted) {
+		return nil
+	}
+	if len(insecure) == 0 {
+		logging.Debug("Removing insecure registries from cluster")
+	} else {
+		logging.Debug("Adding insecure registries to cluster")
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				insecureRegistriesAnnotation: annotation,
+			},
+		},
+		"spec": map[string]interface{}{
+			"registrySources": map[string]interface{}{
+				"insecureRegistries": insecureRegistries,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	cmdArgs := []string{"patch", "image.config.openshift.io", "cluster", "-p", fmt.Sprintf("'%s'", string(patch)), "--type", "merge"}
+	if _, stderr, err := ocConfig.RunOcCommand(cmdArgs...); err != nil {
+		return fmt.Errorf("Failed to set the insecure registries %v: %s", err, stderr)
+	}
+	return nil
+}
+
+// mergeInsecureRegistries returns the insecure registries of the cluster
+// without the ones previously set by crc, followed by the ones crc sets now
+func mergeInsecureRegistries(current, previous, insecure []string) []string {
+	var merged []string
+	seen := make(map[string]bool)
+	add := func(registry string) {
+		if !seen[registry] {
+			seen[registry] = true
+			merged = append(merged, registry)
+		}
+	}
+	removed := make(map[string]bool)
+	for _, registry := range previous {
+		removed[registry] = true
+	}
+	for _, registry := range current {
+		if !removed[registry] {
+			add(registry)
+		}
+	}
+	for _, registry := range insecure {
+		add(registry)
+	}
+	return merged
+}
+
+func splitRegistries(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func equalRegistries(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
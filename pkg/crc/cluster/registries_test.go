@@ -0,0 +1,53 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/code-ready/crc/pkg/crc/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistriesConf(t *testing.T) {
+	assert.Equal(t, "", registriesConf(&RegistriesConfig{}))
+
+	assert.Equal(t, `[[registry]]
+location = "registry.redhat.io"
+insecure = false
+
+[[registry.mirror]]
+location = "mirror.lan/redhat"
+insecure = true
+
+[[registry.mirror]]
+location = "mirror.example.com/redhat"
+insecure = false
+
+[[registry]]
+location = "quay.io"
+insecure = false
+
+[[registry.mirror]]
+location = "mirror.example.com/quay"
+insecure = false
+
+[[registry]]
+location = "mirror.lan"
+insecure = true
+
+`, registriesConf(&RegistriesConfig{
+		Mirrors: []network.RegistryMirror{
+			{Source: "registry.redhat.io", Mirror: "mirror.lan/redhat"},
+			{Source: "quay.io", Mirror: "mirror.example.com/quay"},
+			{Source: "registry.redhat.io", Mirror: "mirror.example.com/redhat"},
+		},
+		Insecure: []string{"mirror.lan", "mirror.lan"},
+	}))
+}
+
+func TestMergeInsecureRegistries(t *testing.T) {
+	assert.Equal(t, []string{"user.lan", "mirror.lan"}, mergeInsecureRegistries([]string{"user.lan"}, nil, []string{"mirror.lan"}))
+	assert.Equal(t, []string{"user.lan", "new.lan"}, mergeInsecureRegistries([]string{"old.lan", "user.lan"}, []string{"old.lan"}, []string{"new.lan"}))
+	assert.Equal(t, []string{"user.lan"}, mergeInsecureRegistries([]string{"user.lan", "old.lan"}, []string{"old.lan"}, nil))
+	assert.Equal(t, []string{"mirror.lan"}, mergeInsecureRegistries([]string{"mirror.lan"}, []string{"mirror.lan"}, []string{"mirror.lan", "mirror.lan"}))
+	assert.Empty(t, mergeInsecureRegistries([]string{"old.lan"}, []string{"old.lan"}, nil))
+}
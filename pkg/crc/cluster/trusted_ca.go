@@ -0,0 +1,283 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/code-ready/crc/pkg/crc/oc"
+	"github.com/code-ready/crc/pkg/crc/ssh"
+)
+
+const (
+	// userCABundle is the config map of the openshift-config namespace
+	// referenced by the trusted CA of the cluster proxy, as done by the
+	// installer for its additionalTrustBundle
+	userCABundle        = "user-ca-bundle"
+	nodeUserCABundle    = "/etc/pki/ca-trust/source/anchors/openshift-config-user-ca-bundle.crt"
+	userCABundleDataKey = "ca-bundle.crt"
+
+	// registryCABundle is the config map of the openshift-config namespace
+	// referenced by the additional trusted CA of the cluster image config.
+	// It has one key per registry, used when importing images.
+	registryCABundle = "crc-registry-ca-bundle"
+
+	// exists in the VM when crc set trusted CAs in the cluster, they are
+	// only looked up in the cluster to be removed when it exists
+	clusterTrustedCAMarker = "/var/lib/crc/cluster-trusted-ca"
+)
+
+// TrustedCABundle concatenates the certificates to trust in addition to the
+// system ones, empty certificates are ignored
+func TrustedCABundle(certs ...string) string {
+	var bundle strings.Builder
+	for _, cert := range certs {
+		cert = strings.TrimSpace(cert)
+		if cert == "" {
+			continue
+		}
+		bundle.WriteString(cert)
+		bundle.WriteString("\n")
+	}
+	return bundle.String()
+}
+
+// EnsureTrustedCABundleOnInstance installs bundle in the trust store of the
+// node, or removes it when bundle is empty. It returns true when the trust
+// store changed, the services using it must then be restarted.
+func EnsureTrustedCABundleOnInstance(sshRunner *ssh.Runner, bundle string) (bool, error) {
+	current, _, err := sshRunner.Run(fmt.Sprintf("sudo cat %s 2>/dev/null || true", nodeUserCABundle))
+	if err != nil {
+		return false, err
+	}
+	if current == bundle {
+		return false, nil
+	}
+	if bundle == "" {
+		logging.Debug("Removing additional trusted CAs from instance")
+		if _, stderr, err := sshRunner.Run(fmt.Sprintf("sudo rm -f %s", nodeUserCABundle)); err != nil {
+			return false, fmt.Errorf("Failed to remove %s: %v: %s", nodeUserCABundle, err, stderr)
+		}
+	} else {
+		logging.Debug("Adding additional trusted CAs to instance")
+		if err := sshRunner.CopyData([]byte(bundle), nodeUserCABundle, 0644); err != nil {
+			return false, err
+		}
+	}
+	if _, stderr, err := sshRunner.Run("sudo update-ca-trust"); err != nil {
+		return false, fmt.Errorf("Failed to update the trust store: %v: %s", err, stderr)
+	}
+	return true, nil
+}
+
+type proxyTrustedCA struct {
+	Spec struct {
+		TrustedCA struct {
+			Name string `json:"name"`
+		} `json:"trustedCA"`
+	} `json:"spec"`
+}
+
+type imageAdditionalTrustedCA struct {
+	Spec struct {
+		AdditionalTrustedCA *struct {
+			Name string `json:"name"`
+		} `json:"additionalTrustedCA"`
+	} `json:"spec"`
+}
+
+// EnsureTrustedCABundleInCluster makes the cluster trust bundle, through the
+// trusted CA of the cluster proxy, and through the additional trusted CA of
+// the cluster image config for the image imports from registries. When bundle
+// is empty, they are removed if they were set by crc.
+func EnsureTrustedCABundleInCluster(ctx context.Context, sshRunner *ssh.Runner, ocConfig oc.Config, bundle string, registries []string) error {
+	if bundle == "" {
+		return removeTrustedCABundleFromCluster(ctx, sshRunner, ocConfig)
+	}
+	if _, stderr, err := sshRunner.Run(fmt.Sprintf("sudo mkdir -p %[1]s && sudo touch %[2]s", path.Dir(clusterTrustedCAMarker), clusterTrustedCAMarker)); err != nil {
+		return fmt.Errorf("Failed to create %s: %v: %s", clusterTrustedCAMarker, err, stderr)
+	}
+
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "proxy"); err != nil {
+		return err
+	}
+	logging.Debug("Adding additional trusted CAs to cluster")
+	if err := applyConfigMap(sshRunner, ocConfig, userCABundle, map[string]string{userCABundleDataKey: bundle}); err != nil {
+		return fmt.Errorf("Failed to add the trusted CA bundle %v", err)
+	}
+	if err := setProxyTrustedCA(ocConfig, userCABundle); err != nil {
+		return err
+	}
+
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "image.config.openshift.io"); err != nil {
+		return err
+	}
+	if len(registries) == 0 {
+		return removeRegistryCABundleFromCluster(ocConfig)
+	}
+	current, err := getImageAdditionalTrustedCA(ocConfig)
+	if err != nil {
+		return err
+	}
+	if current != "" && current != registryCABundle {
+		logging.Warnf("The cluster image config already uses the %s config map for its additional trusted CAs, not changing it", current)
+		return nil
+	}
+	logging.Debug("Adding additional trusted CAs for registries to cluster")
+	if err := applyConfigMap(sshRunner, ocConfig, registryCABundle, registryCABundleData(bundle, registries)); err != nil {
+		return fmt.Errorf("Failed to add the registries trusted CA bundle %v", err)
+	}
+	return setImageAdditionalTrustedCA(ocConfig, registryCABundle)
+}
+
+// registryCABundleData returns the content of the config map referenced by
+// the image config, the ':' of the port of the registries is replaced by '..'
+func registryCABundleData(bundle string, registries []string) map[string]string {
+	data := make(map[string]string)
+	for _, registry := range registries {
+		data[strings.Replace(registry, ":", "..", 1)] = bundle
+	}
+	return data
+}
+
+func applyConfigMap(sshRunner *ssh.Runner, ocConfig oc.Config, name string, data map[string]string) error {
+	configMap, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]string{
+			"name":      name,
+			"namespace": "openshift-config",
+		},
+		"data": data,
+	})
+	if err != nil {
+		return err
+	}
+	file := fmt.Sprintf("/tmp/%s.json", name)
+	if err := sshRunner.CopyData(configMap, file, 0644); err != nil {
+		return err
+	}
+	if _, stderr, err := ocConfig.RunOcCommand("apply", "-f", file); err != nil {
+		return fmt.Errorf("%v: %s", err, stderr)
+	}
+	return nil
+}
+
+func removeTrustedCABundleFromCluster(ctx context.Context, sshRunner *ssh.Runner, ocConfig oc.Config) error {
+	stdout, _, err := sshRunner.Run(fmt.Sprintf("if sudo test -e %s; then echo set; fi", clusterTrustedCAMarker))
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(stdout) != "set" {
+		return nil
+	}
+
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "proxy"); err != nil {
+		return err
+	}
+	output, stderr, err := ocConfig.RunOcCommand("get", "proxy", "cluster", "-o", "json")
+	if err != nil {
+		return fmt.Errorf("Failed to get the cluster proxy %v: %s", err, stderr)
+	}
+	var proxy proxyTrustedCA
+	if err := json.Unmarshal([]byte(output), &proxy); err != nil {
+		return err
+	}
+	if proxy.Spec.TrustedCA.Name == userCABundle {
+		logging.Debug("Removing additional trusted CAs from cluster")
+		if err := setProxyTrustedCA(ocConfig, ""); err != nil {
+			return err
+		}
+		if _, stderr, err := ocConfig.RunOcCommand("delete", "configmap", userCABundle, "-n", "openshift-config", "--ignore-not-found"); err != nil {
+			return fmt.Errorf("Failed to remove the trusted CA bundle %v: %s", err, stderr)
+		}
+	}
+
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "image.config.openshift.io"); err != nil {
+		return err
+	}
+	if err := removeRegistryCABundleFromCluster(ocConfig); err != nil {
+		return err
+	}
+	if _, stderr, err := sshRunner.Run(fmt.Sprintf("sudo rm -f %s", clusterTrustedCAMarker)); err != nil {
+		return fmt.Errorf("Failed to remove %s: %v: %s", clusterTrustedCAMarker, err, stderr)
+	}
+	return nil
+}
+
+// removeRegistryCABundleFromCluster removes the additional trusted CA of the
+// cluster image config when it was set by crc
+func removeRegistryCABundleFromCluster(ocConfig oc.Config) error {
+	current, err := getImageAdditionalTrustedCA(ocConfig)
+	if err != nil {
+		return err
+	}
+	if current != registryCABundle {
+		return nil
+	}
+
+	logging.Debug("Removing additional trusted CAs for registries from cluster")
+	if err := setImageAdditionalTrustedCA(ocConfig, ""); err != nil {
+		return err
+	}
+	if _, stderr, err := ocConfig.RunOcCommand("delete", "configmap", registryCABundle, "-n", "openshift-config", "--ignore-not-found"); err != nil {
+		return fmt.Errorf("Failed to remove the registries trusted CA bundle %v: %s", err, stderr)
+	}
+	return nil
+}
+
+// getImageAdditionalTrustedCA returns the name of the config map referenced
+// by the additional trusted CA of the cluster image config
+func getImageAdditionalTrustedCA(ocConfig oc.Config) (string, error) {
+	output, stderr, err := ocConfig.RunOcCommand("get", "image.config.openshift.io", "cluster", "-o", "json")
+	if err != nil {
+		return "", fmt.Errorf("Failed to get the cluster image config %v: %s", err, stderr)
+	}
+	var imageConfig imageAdditionalTrustedCA
+	if err := json.Unmarshal([]byte(output), &imageConfig); err != nil {
+		return "", err
+	}
+	if imageConfig.Spec.AdditionalTrustedCA == nil {
+		return "", nil
+	}
+	return imageConfig.Spec.AdditionalTrustedCA.Name, nil
+}
+
+func setProxyTrustedCA(ocConfig oc.Config, name string) error {
+	var patch proxyTrustedCA
+	patch.Spec.TrustedCA.Name = name
+	patchEncode, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	cmdArgs := []string{"patch", "proxy", "cluster", "-p", fmt.Sprintf("'%s'", string(patchEncode)), "--type", "merge"}
+	if _, stderr, err := ocConfig.RunOcCommand(cmdArgs...); err != nil {
+		return fmt.Errorf("Failed to set the trusted CA of the cluster proxy %v: %s", err, stderr)
+	}
+	return nil
+}
+
+// setImageAdditionalTrustedCA sets the additional trusted CA of the cluster
+// image config, or removes it when name is empty
+func setImageAdditionalTrustedCA(ocConfig oc.Config, name string) error {
+	var additionalTrustedCA interface{}
+	if name != "" {
+		additionalTrustedCA = map[string]string{"name": name}
+	}
+	patchEncode, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"additionalTrustedCA": additionalTrustedCA,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	cmdArgs := []string{"patch", "image.config.openshift.io", "cluster", "-p", fmt.Sprintf("'%s'", string(patchEncode)), "--type", "merge"}
+	if _, stderr, err := ocConfig.RunOcCommand(cmdArgs...); err != nil {
+		return fmt.Errorf("Failed to set the additional trusted CA of the cluster image config %v: %s", err, stderr)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrustedCABundle(t *testing.T) {
+	assert.Equal(t, "", TrustedCABundle())
+	assert.Equal(t, "", TrustedCABundle("", "\n"))
+	assert.Equal(t, "proxy-ca\ncorporate-ca\n", TrustedCABundle("proxy-ca\n\n", "", "corporate-ca"))
+}
+
+func TestRegistryCABundleData(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"registry.example.com": "corporate-ca\n",
+		"192.168.1.10..5000":   "corporate-ca\n",
+	}, registryCABundleData("corporate-ca\n", []string{"registry.example.com", "192.168.1.10:5000"}))
+}
@@ -0,0 +1,108 @@
+package cluster
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/code-ready/crc/pkg/crc/ssh"
+)
+
+// VMStats describes the resources used by the VM
+type VMStats struct {
+	CPUs int
+	// CPUUsage is the percentage of the CPU time spent outside of the idle task
+	CPUUsage float64
+	// MemoryUsage and MemorySize are in bytes
+	MemoryUsage int64
+	MemorySize  int64
+	Uptime      time.Duration
+}
+
+// The CPU usage is computed from two samples of /proc/stat taken half a second apart
+const vmStatsCommand = "nproc && cat /proc/uptime && grep -E '^(MemTotal|MemAvailable):' /proc/meminfo && head -1 /proc/stat && sleep 0.5 && head -1 /proc/stat"
+
+// GetVMStats returns the CPU and memory usage and the uptime of the VM
+func GetVMStats(sshRunner *ssh.Runner) (*VMStats, error) {
+	out, _, err := sshRunner.Run(vmStatsCommand)
+	if err != nil {
+		return nil, err
+	}
+	return parseVMStats(out)
+}
+
+func parseVMStats(output string) (*VMStats, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 6 {
+		return nil, fmt.Errorf("unexpected VM stats: %q", output)
+	}
+	var stats VMStats
+	var err error
+	if stats.CPUs, err = strconv.Atoi(strings.TrimSpace(lines[0])); err != nil {
+		return nil, err
+	}
+	uptime, err := strconv.ParseFloat(strings.Fields(lines[1])[0], 64)
+	if err != nil {
+		return nil, err
+	}
+	stats.Uptime = time.Duration(uptime) * time.Second
+
+	memTotal, err := parseMeminfoLine(lines[2])
+	if err != nil {
+		return nil, err
+	}
+	memAvailable, err := parseMeminfoLine(lines[3])
+	if err != nil {
+		return nil, err
+	}
+	stats.MemorySize = memTotal
+	stats.MemoryUsage = memTotal - memAvailable
+
+	idle1, total1, err := parseCPUStatLine(lines[4])
+	if err != nil {
+		return nil, err
+	}
+	idle2, total2, err := parseCPUStatLine(lines[5])
+	if err != nil {
+		return nil, err
+	}
+	if total2 > total1 {
+		stats.CPUUsage = 100 * (1 - float64(idle2-idle1)/float64(total2-total1))
+	}
+	return &stats, nil
+}
+
+// parseMeminfoLine returns the value in bytes of a /proc/meminfo line such as "MemTotal: 9958528 kB"
+func parseMeminfoLine(line string) (int64, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 3 || fields[2] != "kB" {
+		return 0, fmt.Errorf("unexpected meminfo line: %q", line)
+	}
+	value, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return value * 1024, nil
+}
+
+// parseCPUStatLine returns the idle and total times of the "cpu" line of /proc/stat
+func parseCPUStatLine(line string) (uint64, uint64, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return 0, 0, fmt.Errorf("unexpected stat line: %q", line)
+	}
+	var idle, total uint64
+	for i, field := range fields[1:] {
+		value, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		// idle and iowait
+		if i == 3 || i == 4 {
+			idle += value
+		}
+		total += value
+	}
+	return idle, total, nil
+}
@@ -0,0 +1,40 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVMStats(t *testing.T) {
+	stats, err := parseVMStats(`4
+7384.52 25021.13
+MemTotal:        9958528 kB
+MemAvailable:    3672256 kB
+cpu  100 0 100 700 100 0 0 0 0 0
+cpu  150 0 150 950 150 0 0 0 0 0
+`)
+	require.NoError(t, err)
+	assert.Equal(t, &VMStats{
+		CPUs:        4,
+		CPUUsage:    25,
+		MemoryUsage: (9958528 - 3672256) * 1024,
+		MemorySize:  9958528 * 1024,
+		Uptime:      7384 * time.Second,
+	}, stats)
+}
+
+func TestParseVMStatsInvalid(t *testing.T) {
+	_, err := parseVMStats("4\n")
+	assert.Error(t, err)
+	_, err = parseVMStats(`4
+7384.52 25021.13
+MemTotal:        9958528 kB
+MemAvailable:    3672256 kB
+intr 100
+cpu  150 0 150 950 150 0 0 0 0 0
+`)
+	assert.EqualError(t, err, `unexpected stat line: "intr 100"`)
+}
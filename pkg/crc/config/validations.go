@@ -81,6 +81,19 @@ func ValidatePath(value interface{}) (bool, string) {
 	return true, ""
 }
 
+// ValidatePathList checks if all the paths of a comma separated list exist
+func ValidatePathList(value interface{}) (bool, string) {
+	for _, path := range strings.Split(cast.ToString(value), ",") {
+		if path == "" {
+			continue
+		}
+		if err := validation.ValidatePath(path); err != nil {
+			return false, err.Error()
+		}
+	}
+	return true, ""
+}
+
 // ValidateURI checks if given URI is valid
 func ValidateURI(value interface{}) (bool, string) {
 	if err := network.ValidateProxyURL(cast.ToString(value)); err != nil {
@@ -97,6 +110,39 @@ func ValidateNoProxy(value interface{}) (bool, string) {
 	return true, ""
 }
 
+// ValidateWaitCondition checks if the start wait condition is valid
+func ValidateWaitCondition(value interface{}) (bool, string) {
+	if err := validation.ValidateWaitCondition(cast.ToString(value)); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+// ValidateTimeout checks if the value is a valid positive duration
+func ValidateTimeout(value interface{}) (bool, string) {
+	if err := validation.ValidateTimeout(cast.ToString(value)); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+// ValidateMinutes checks if the value is a number of minutes
+func ValidateMinutes(value interface{}) (bool, string) {
+	v, err := cast.ToIntE(value)
+	if err != nil || v < 0 {
+		return false, "requires integer value >= 0"
+	}
+	return true, ""
+}
+
+// ValidateClusterOperators checks if the value is a comma separated list of cluster operator names
+func ValidateClusterOperators(value interface{}) (bool, string) {
+	if strings.Contains(cast.ToString(value), " ") {
+		return false, "cluster operator list must be comma separated and can't contain spaces"
+	}
+	return true, ""
+}
+
 func ValidateYesNo(value interface{}) (bool, string) {
 	if cast.ToString(value) == "yes" || cast.ToString(value) == "no" {
 		return true, ""
@@ -31,6 +31,13 @@ func NewViperStorage(configFile, envPrefix string) (*ViperStorage, error) {
 	}, nil
 }
 
+// SetConfigFile changes the file the settings are read from and written to
+func (c *ViperStorage) SetConfigFile(configFile string) {
+	c.storeLock.Lock()
+	defer c.storeLock.Unlock()
+	c.configFile = configFile
+}
+
 func (c *ViperStorage) viperInstance() (*viper.Viper, error) {
 	if err := ensureConfigFileExists(c.configFile); err != nil {
 		return nil, err
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	"github.com/YourFin/binappend"
 	"github.com/code-ready/crc/pkg/crc/version"
@@ -16,6 +17,22 @@ const (
 	DefaultMemory   = 9216
 	DefaultDiskSize = 31
 
+	// Conditions the start command can wait for once the kubelet is started
+	WaitNone      = "none"
+	WaitOperators = "operators"
+
+	DefaultWait        = WaitOperators
+	DefaultWaitTimeout = "10m"
+
+	// Time given to the VM to shut down before it is powered off
+	DefaultStopTimeout = "3m"
+
+	// Minutes between the auto-stop event and the stop of an idle cluster
+	DefaultAutoStopGraceMinutes = 5
+
+	// How long before the bundle certificates can no longer be renewed users are warned about it
+	BundleExpiryWarningPeriod = 30 * 24 * time.Hour
+
 	DefaultSSHUser = "core"
 	DefaultSSHPort = 22
 
@@ -34,9 +51,12 @@ const (
 	CRCWindowsTrayDownloadURL = "https://github.com/code-ready/tray-windows/releases/download/v%s/crc-tray-windows.zip"
 	DefaultContext            = "admin"
 
-	VSockGateway = "192.168.127.1"
 	VsockSSHPort = 2222
 
+	// Names of the sockets passed by systemd to the socket activated daemon
+	DaemonAPISocketName     = "crc-api"
+	DaemonNetworkSocketName = "crc-network"
+
 	OkdPullSecret = `{"auths":{"fake":{"auth": "Zm9vOmJhcgo="}}}` // #nosec G101
 
 	ClusterDomain = ".crc.testing"
@@ -104,6 +124,7 @@ var (
 	DefaultBundlePath  = defaultBundlePath()
 	DaemonSocketPath   = filepath.Join(CrcBaseDir, "crc.sock")
 	NetworkSocketPath  = filepath.Join(CrcBaseDir, "network.sock")
+	DaemonBundlePath   = filepath.Join(CrcBaseDir, "daemon-bundle")
 )
 
 func defaultBundlePath() string {
@@ -165,17 +186,17 @@ func contains(arr []string, str string) bool {
 	return false
 }
 
-func GetPublicKeyPath() string {
-	return filepath.Join(MachineInstanceDir, DefaultName, "id_ecdsa.pub")
+func GetPublicKeyPath(name string) string {
+	return filepath.Join(MachineInstanceDir, name, "id_ecdsa.pub")
 }
 
-func GetPrivateKeyPath() string {
-	return filepath.Join(MachineInstanceDir, DefaultName, "id_ecdsa")
+func GetPrivateKeyPath(name string) string {
+	return filepath.Join(MachineInstanceDir, name, "id_ecdsa")
 }
 
 // For backward compatibility to v 1.20.0
-func GetRsaPrivateKeyPath() string {
-	return filepath.Join(MachineInstanceDir, DefaultName, "id_rsa")
+func GetRsaPrivateKeyPath(name string) string {
+	return filepath.Join(MachineInstanceDir, name, "id_rsa")
 }
 
 // TODO: follow the same pattern as oc and podman above
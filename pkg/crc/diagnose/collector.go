@@ -0,0 +1,157 @@
+package diagnose
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/code-ready/crc/pkg/crc/ssh"
+	"github.com/pkg/errors"
+)
+
+// Writer stores the collected files in the diagnostics archive
+type Writer interface {
+	Write(filename string, bin []byte) error
+}
+
+// Collector gathers one or several files of diagnostics
+type Collector interface {
+	Collect(Writer) error
+}
+
+func File(path string) *FileCollector {
+	return &FileCollector{Source: path, Target: filepath.Base(path)}
+}
+
+type FileCollector struct {
+	Source, Target string
+}
+
+func (collector *FileCollector) Collect(w Writer) error {
+	bin, err := ioutil.ReadFile(collector.Source)
+	if err != nil {
+		return err
+	}
+	return w.Write(collector.Target, bin)
+}
+
+// DataCollector writes data gathered before the collection, such as the configuration
+type DataCollector struct {
+	Data   []byte
+	Target string
+}
+
+func (collector *DataCollector) Collect(w Writer) error {
+	return w.Write(collector.Target, collector.Data)
+}
+
+// TreeCollector lists the files of a directory with their size and permissions
+type TreeCollector struct {
+	Dir    string
+	Target string
+}
+
+func (collector *TreeCollector) Collect(w Writer) error {
+	var list []string
+	err := filepath.Walk(collector.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		list = append(list, fmt.Sprintf("%s: %d %s", path, info.Size(), info.Mode().String()))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return w.Write(collector.Target, []byte(strings.Join(list, "\n")))
+}
+
+func Command(command, target string) *CommandCollector {
+	return &CommandCollector{
+		Command: command,
+		Target:  target,
+	}
+}
+
+// CommandCollector runs a shell command on the host
+type CommandCollector struct {
+	Command, Target string
+}
+
+func (collector *CommandCollector) Collect(w Writer) error {
+	// #nosec G204
+	out, err := exec.Command("/bin/sh", "-c", collector.Command).CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "collecting %s", collector.Target)
+	}
+	return w.Write(collector.Target, out)
+}
+
+func VMFile(runner *ssh.Runner, file string) *VMCommandCollector {
+	return VMCommand(runner, fmt.Sprintf("sudo cat %s", file), filepath.Base(file))
+}
+
+func VMCommand(runner *ssh.Runner, command, target string) *VMCommandCollector {
+	return &VMCommandCollector{
+		Runner:  runner,
+		Command: command,
+		Target:  target,
+	}
+}
+
+// VMCommandCollector runs a command in the VM, its output is stored in the vm directory
+type VMCommandCollector struct {
+	Runner          *ssh.Runner
+	Command, Target string
+}
+
+func (collector *VMCommandCollector) Collect(w Writer) error {
+	out, _, err := collector.Runner.Run(collector.Command)
+	if err != nil {
+		return errors.Wrapf(err, "collecting %s", collector.Target)
+	}
+	return w.Write(path.Join("vm", collector.Target), []byte(out))
+}
+
+// ContainerLogCollector collects the description and the logs of the
+// containers of a container engine of the VM, podman or crictl
+type ContainerLogCollector struct {
+	Runner  *ssh.Runner
+	Process string
+}
+
+func (collector *ContainerLogCollector) Collect(w Writer) error {
+	out, _, err := collector.Runner.Run(fmt.Sprintf("sudo %s ps -a -q", collector.Process))
+	if err != nil {
+		return err
+	}
+	for _, id := range strings.Split(strings.TrimSpace(out), "\n") {
+		if id == "" {
+			continue
+		}
+		inspect, _, err := collector.Runner.Run(fmt.Sprintf("sudo %s inspect %s", collector.Process, id))
+		if err != nil {
+			logging.Errorf("error while inspecting %s: %v", id, err)
+			continue
+		}
+		if err := w.Write(path.Join("vm", collector.Process, fmt.Sprintf("%s-inspect.txt", id)), []byte(inspect)); err != nil {
+			logging.Errorf("error while inspecting %s: %v", id, err)
+			continue
+		}
+		logs, _, err := collector.Runner.Run(fmt.Sprintf("sudo %s logs --tail 200 %s 2>&1", collector.Process, id))
+		if err != nil {
+			logging.Errorf("error while getting logs %s: %v", id, err)
+			continue
+		}
+		if err := w.Write(path.Join("vm", collector.Process, fmt.Sprintf("%s-logs.txt", id)), []byte(logs)); err != nil {
+			logging.Errorf("error while getting logs %s: %v", id, err)
+			continue
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package diagnose
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"golang.org/x/sync/semaphore"
+)
+
+// zipWriter scrubs the secrets of the collected files before adding them to the archive
+type zipWriter struct {
+	writer    *zip.Writer
+	scrubber  *Scrubber
+	writeLock sync.Mutex
+}
+
+func (zw *zipWriter) Write(filename string, bin []byte) error {
+	zw.writeLock.Lock()
+	defer zw.writeLock.Unlock()
+
+	f, err := zw.writer.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(zw.scrubber.Scrub(bin))
+	return err
+}
+
+// Run writes the files gathered by the collectors to a zip archive. A
+// failing collector is logged and does not prevent the others to run.
+func Run(filename string, collectors []Collector, scrubber *Scrubber) error {
+	zipFile, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer zipFile.Close()
+
+	writer := zip.NewWriter(zipFile)
+	zw := &zipWriter{
+		writer:   writer,
+		scrubber: scrubber,
+	}
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	sem := semaphore.NewWeighted(4)
+	for i := range collectors {
+		wg.Add(1)
+		collector := collectors[i]
+		go func() {
+			defer wg.Done()
+			_ = sem.Acquire(context.Background(), 1)
+			defer sem.Release(1)
+			if err := collector.Collect(zw); err != nil {
+				logging.Errorf("error while collecting: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	logging.Debugf("diagnose took %s", time.Since(start))
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return zipFile.Close()
+}
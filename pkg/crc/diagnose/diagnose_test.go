@@ -0,0 +1,53 @@
+package diagnose
+
+import (
+	"archive/zip"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingCollector struct{}
+
+func (collector *failingCollector) Collect(w Writer) error {
+	return errors.New("collector failed")
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "diagnose")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	source := filepath.Join(dir, "crc.log")
+	require.NoError(t, ioutil.WriteFile(source, []byte("using secret s3cr3t-value"), 0600))
+
+	archive := filepath.Join(dir, "diagnose.zip")
+	assert.NoError(t, Run(archive, []Collector{
+		File(source),
+		&failingCollector{},
+		&DataCollector{Data: []byte("config"), Target: "config.txt"},
+	}, NewScrubber("s3cr3t-value")))
+
+	reader, err := zip.OpenReader(archive)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	content := make(map[string]string)
+	for _, file := range reader.File {
+		f, err := file.Open()
+		require.NoError(t, err)
+		bin, err := ioutil.ReadAll(f)
+		require.NoError(t, err)
+		f.Close()
+		content[file.Name] = string(bin)
+	}
+	assert.Equal(t, map[string]string{
+		"crc.log":    "using secret <redacted>",
+		"config.txt": "config",
+	}, content)
+}
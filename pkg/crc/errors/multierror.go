@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -80,12 +81,16 @@ func (r RetriableError) Error() string {
 	return "Temporary error: " + r.Err.Error()
 }
 
-// RetryAfter retries for a certain duration, after a delay
-func RetryAfter(limit time.Duration, callback func() error, d time.Duration) error {
+// RetryAfter retries for a certain duration, after a delay.
+// It gives up early and returns the context error when ctx is cancelled.
+func RetryAfter(ctx context.Context, limit time.Duration, callback func() error, d time.Duration) error {
 	m := MultiError{}
 	timeLimit := time.Now().Add(limit)
 	attempt := 0
 	for time.Now().Before(timeLimit) || attempt < 2 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		logging.Debugf("retry loop: attempt %d", attempt)
 		err := callback()
 		if err == nil {
@@ -98,7 +103,11 @@ func RetryAfter(limit time.Duration, callback func() error, d time.Duration) err
 			return m
 		}
 		logging.Debugf("error: %v - sleeping %s", err, d)
-		time.Sleep(d)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
+		}
 	}
 	logging.Debugf("RetryAfter timeout after %d tries", attempt)
 	return m
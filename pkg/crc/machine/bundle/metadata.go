@@ -114,6 +114,20 @@ func (bundle *CrcBundleInfo) GetBundleBuildTime() (time.Time, error) {
 	return time.Parse(time.RFC3339, strings.TrimSpace(bundle.BuildInfo.BuildTime))
 }
 
+// The signers of the cluster created by snc are valid for one year. Once they
+// expired, the kubelet certificates cannot be renewed and the bundle is unusable.
+const certsRenewableFor = 365 * 24 * time.Hour
+
+// GetCertsExpiryDate returns the date after which the certificates of a cluster
+// created from the bundle can no longer be renewed
+func (bundle *CrcBundleInfo) GetCertsExpiryDate() (time.Time, error) {
+	buildTime, err := bundle.GetBundleBuildTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return buildTime.Add(certsRenewableFor), nil
+}
+
 func (bundle *CrcBundleInfo) GetOpenshiftVersion() string {
 	return bundle.ClusterInfo.OpenShiftVersion
 }
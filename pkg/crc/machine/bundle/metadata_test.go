@@ -3,6 +3,7 @@ package bundle
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -98,3 +99,12 @@ func TestUnmarshalMarshal(t *testing.T) {
 	assert.NoError(t, err)
 	assert.JSONEq(t, string(bin), reference)
 }
+
+func TestGetCertsExpiryDate(t *testing.T) {
+	expiryDate, err := parsedReference.GetCertsExpiryDate()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 10, 26, 4, 48, 26, 0, time.UTC), expiryDate.UTC())
+
+	_, err = (&CrcBundleInfo{}).GetCertsExpiryDate()
+	assert.Error(t, err)
+}
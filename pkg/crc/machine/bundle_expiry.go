@@ -0,0 +1,43 @@
+package machine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/code-ready/crc/pkg/crc/constants"
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/code-ready/crc/pkg/crc/machine/bundle"
+)
+
+const bundleUpgradeHint = "Download the latest CodeReady Containers release and run 'crc setup', 'crc delete' and 'crc start' to use its bundle"
+
+// getBundleExpiry returns when the certificates of the cluster created from
+// the bundle can no longer be renewed, or nil when it is unknown
+func getBundleExpiry(bundleInfo *bundle.CrcBundleInfo, now time.Time) *BundleExpiry {
+	expiresAt, err := bundleInfo.GetCertsExpiryDate()
+	if err != nil {
+		logging.Debugf("Cannot get the expiry date of the bundle certificates: %v", err)
+		return nil
+	}
+	return &BundleExpiry{
+		ExpiresAt: expiresAt,
+		Warning:   BundleExpiryWarning(expiresAt, now),
+	}
+}
+
+// BundleExpiryWarning returns a message telling how to replace the bundle
+// when its certificates expire in less than constants.BundleExpiryWarningPeriod, and
+// an empty string otherwise
+func BundleExpiryWarning(expiresAt, now time.Time) string {
+	remaining := expiresAt.Sub(now)
+	switch {
+	case remaining <= 0:
+		return fmt.Sprintf("The certificates of the bundle expired on %s, the cluster cannot be started anymore. %s",
+			expiresAt.Format("2006-01-02"), bundleUpgradeHint)
+	case remaining < constants.BundleExpiryWarningPeriod:
+		return fmt.Sprintf("The certificates of the bundle expire in %d days, on %s, the cluster cannot be started after that. %s",
+			int(remaining.Hours()/24), expiresAt.Format("2006-01-02"), bundleUpgradeHint)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,18 @@
+package machine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundleExpiryWarning(t *testing.T) {
+	expiresAt := time.Date(2021, 10, 26, 4, 48, 26, 0, time.UTC)
+
+	assert.Empty(t, BundleExpiryWarning(expiresAt, expiresAt.AddDate(0, -2, 0)))
+	assert.Equal(t, "The certificates of the bundle expire in 10 days, on 2021-10-26, the cluster cannot be started after that. "+bundleUpgradeHint,
+		BundleExpiryWarning(expiresAt, expiresAt.Add(-10*24*time.Hour-time.Hour)))
+	assert.Equal(t, "The certificates of the bundle expired on 2021-10-26, the cluster cannot be started anymore. "+bundleUpgradeHint,
+		BundleExpiryWarning(expiresAt, expiresAt.Add(time.Minute)))
+}
@@ -0,0 +1,38 @@
+package machine
+
+import (
+	"context"
+
+	"github.com/code-ready/crc/pkg/crc/cluster"
+	"github.com/code-ready/crc/pkg/crc/oc"
+	"github.com/pkg/errors"
+)
+
+// Certificates returns the expiry dates of the certificates of the running cluster
+func (client *client) Certificates(ctx context.Context) ([]cluster.Certificate, error) {
+	sshRunner, err := client.runningVMSSHRunner()
+	if err != nil {
+		return nil, err
+	}
+	defer sshRunner.Close()
+	return cluster.GetCertificates(sshRunner, oc.UseOCWithSSH(sshRunner))
+}
+
+// RenewCertificates forces the renewal of the kubelet certificates and of
+// the aggregator client CA of the running cluster. The API server and ingress
+// certificates are rotated by the cluster operators.
+func (client *client) RenewCertificates(ctx context.Context) error {
+	sshRunner, err := client.runningVMSSHRunner()
+	if err != nil {
+		return err
+	}
+	defer sshRunner.Close()
+	ocConfig := oc.UseOCWithSSH(sshRunner)
+	if err := cluster.RenewKubeletCerts(ctx, sshRunner, ocConfig); err != nil {
+		return errors.Wrap(err, "Failed to renew the kubelet certificates")
+	}
+	if err := cluster.RenewAggregatorClientCA(ctx, sshRunner, ocConfig); err != nil {
+		return errors.Wrap(err, "Failed to renew the aggregator client CA")
+	}
+	return nil
+}
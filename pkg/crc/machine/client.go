@@ -1,24 +1,40 @@
 package machine
 
 import (
+	"context"
 	cmdConfig "github.com/code-ready/crc/cmd/crc/cmd/config"
+	"github.com/code-ready/crc/pkg/crc/cluster"
 	crcConfig "github.com/code-ready/crc/pkg/crc/config"
 	"github.com/code-ready/crc/pkg/crc/network"
-	"github.com/code-ready/machine/libmachine/state"
 )
 
 type Client interface {
 	GetName() string
 
-	Delete() error
-	Exists() (bool, error)
-	GetConsoleURL() (*ConsoleResult, error)
-	IP() (string, error)
-	PowerOff() error
-	Start(startConfig StartConfig) (*StartResult, error)
-	Status() (*ClusterStatusResult, error)
-	Stop() (state.State, error)
-	IsRunning() (bool, error)
+	Delete(ctx context.Context) error
+	Exists(ctx context.Context) (bool, error)
+	GetConsoleURL(ctx context.Context) (*ConsoleResult, error)
+	IP(ctx context.Context) (string, error)
+	ConnectionDetails(ctx context.Context) (*ConnectionDetails, error)
+	PowerOff(ctx context.Context) error
+	Start(ctx context.Context, startConfig StartConfig) (*StartResult, error)
+	Status(ctx context.Context) (*ClusterStatusResult, error)
+	Stop(ctx context.Context, stopConfig StopConfig) (*StopResult, error)
+	IsRunning(ctx context.Context) (bool, error)
+	Pause(ctx context.Context) error
+	Resume(ctx context.Context) error
+
+	SaveSnapshot(ctx context.Context, name string) error
+	RestoreSnapshot(ctx context.Context, name string) error
+	DeleteSnapshot(ctx context.Context, name string) error
+	ListSnapshots(ctx context.Context) ([]Snapshot, error)
+
+	AddMount(ctx context.Context, hostDir, vmDir string) error
+	RemoveMount(ctx context.Context, vmDir string) error
+	ListMounts(ctx context.Context) ([]Mount, error)
+
+	Certificates(ctx context.Context) ([]cluster.Certificate, error)
+	RenewCertificates(ctx context.Context) error
 }
 
 type client struct {
@@ -47,6 +63,10 @@ func (client *client) networkMode() network.Mode {
 	return network.ParseMode(client.config.Get(cmdConfig.NetworkMode).AsString())
 }
 
+func (client *client) virtualNetwork() network.VirtualNetwork {
+	return cmdConfig.VirtualNetwork(client.config)
+}
+
 func (client *client) monitoringEnabled() bool {
 	return client.config.Get(cmdConfig.EnableClusterMonitoring).AsBool()
 }
@@ -1,9 +1,13 @@
 package machine
 
-import "github.com/pkg/errors"
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
 
 // Return console URL if the VM is present.
-func (client *client) GetConsoleURL() (*ConsoleResult, error) {
+func (client *client) GetConsoleURL(ctx context.Context) (*ConsoleResult, error) {
 	// Here we are only checking if the VM exist and not the status of the VM.
 	// We might need to improve and use crc status logic, only
 	// return if the Openshift is running as part of status.
@@ -1,11 +1,12 @@
 package machine
 
 import (
+	"context"
 	"github.com/code-ready/crc/pkg/crc/logging"
 	"github.com/pkg/errors"
 )
 
-func (client *client) Delete() error {
+func (client *client) Delete(ctx context.Context) error {
 	libMachineAPIClient, cleanup := createLibMachineClient()
 	defer logging.BackupLogFile()
 	defer cleanup()
@@ -15,6 +16,12 @@ func (client *client) Delete() error {
 		return errors.Wrap(err, "Cannot load machine")
 	}
 
+	if paused, err := isPaused(host); err == nil && paused {
+		if err := discardPausedState(host); err != nil {
+			return errors.Wrap(err, "Cannot discard the state of the paused machine")
+		}
+	}
+
 	if err := host.Driver.Remove(); err != nil {
 		return errors.Wrap(err, "Driver cannot remove machine")
 	}
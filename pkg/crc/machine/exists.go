@@ -1,8 +1,11 @@
 package machine
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
-func (client *client) Exists() (bool, error) {
+func (client *client) Exists(ctx context.Context) (bool, error) {
 	libMachineAPIClient, cleanup := createLibMachineClient()
 	defer cleanup()
 	exists, err := libMachineAPIClient.Exists(client.name)
@@ -1,8 +1,11 @@
 package fakemachine
 
 import (
+	"context"
 	"errors"
+	"time"
 
+	"github.com/code-ready/crc/pkg/crc/cluster"
 	"github.com/code-ready/crc/pkg/crc/machine"
 	"github.com/code-ready/crc/pkg/crc/network"
 	"github.com/code-ready/machine/libmachine/state"
@@ -18,8 +21,17 @@ func NewFailingClient() *Client {
 	}
 }
 
+// NewBlockingClient returns a client whose Start method only returns
+// once its context is cancelled
+func NewBlockingClient() *Client {
+	return &Client{
+		Blocking: true,
+	}
+}
+
 type Client struct {
-	Failing bool
+	Failing  bool
+	Blocking bool
 }
 
 var DummyClusterConfig = machine.ClusterConfig{
@@ -35,14 +47,14 @@ func (c *Client) GetName() string {
 	return "crc"
 }
 
-func (c *Client) Delete() error {
+func (c *Client) Delete(ctx context.Context) error {
 	if c.Failing {
 		return errors.New("delete failed")
 	}
 	return nil
 }
 
-func (c *Client) GetConsoleURL() (*machine.ConsoleResult, error) {
+func (c *Client) GetConsoleURL(ctx context.Context) (*machine.ConsoleResult, error) {
 	if c.Failing {
 		return nil, errors.New("console failed")
 	}
@@ -56,35 +68,58 @@ func (c *Client) GetProxyConfig(machineName string) (*network.ProxyConfig, error
 	return nil, errors.New("not implemented")
 }
 
-func (c *Client) IP() (string, error) {
+func (c *Client) IP(ctx context.Context) (string, error) {
 	return "", errors.New("not implemented")
 }
 
-func (c *Client) PowerOff() error {
+func (c *Client) ConnectionDetails(ctx context.Context) (*machine.ConnectionDetails, error) {
+	if c.Failing {
+		return nil, errors.New("VM is not running")
+	}
+	return &machine.ConnectionDetails{
+		IP:          "192.168.130.11",
+		SSHPort:     22,
+		SSHUsername: "core",
+		SSHKeys:     []string{"/home/user/.crc/machines/crc/id_ecdsa"},
+	}, nil
+}
+
+func (c *Client) PowerOff(ctx context.Context) error {
 	if c.Failing {
 		return errors.New("poweroff failed")
 	}
 	return nil
 }
 
-func (c *Client) Start(startConfig machine.StartConfig) (*machine.StartResult, error) {
+func (c *Client) Start(ctx context.Context, startConfig machine.StartConfig) (*machine.StartResult, error) {
 	if c.Failing {
 		return nil, errors.New("Failed to start")
 	}
+	if c.Blocking {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if startConfig.Progress != nil {
+		startConfig.Progress(machine.StartProgress{
+			Step:       machine.StepDone,
+			Percentage: 100,
+			Message:    "Started the OpenShift cluster",
+		})
+	}
 	return &machine.StartResult{
 		ClusterConfig:  DummyClusterConfig,
 		KubeletStarted: true,
 	}, nil
 }
 
-func (c *Client) Stop() (state.State, error) {
+func (c *Client) Stop(ctx context.Context, stopConfig machine.StopConfig) (*machine.StopResult, error) {
 	if c.Failing {
-		return state.Running, errors.New("stop failed")
+		return &machine.StopResult{State: state.Running}, errors.New("stop failed")
 	}
-	return state.Stopped, nil
+	return &machine.StopResult{State: state.Stopped}, nil
 }
 
-func (c *Client) Status() (*machine.ClusterStatusResult, error) {
+func (c *Client) Status(ctx context.Context) (*machine.ClusterStatusResult, error) {
 	if c.Failing {
 		return nil, errors.New("broken")
 	}
@@ -94,13 +129,131 @@ func (c *Client) Status() (*machine.ClusterStatusResult, error) {
 		OpenshiftVersion: "4.5.1",
 		DiskUse:          10_000_000_000,
 		DiskSize:         20_000_000_000,
+		BundleExpiry: &machine.BundleExpiry{
+			ExpiresAt: time.Date(2021, 10, 26, 4, 48, 26, 0, time.UTC),
+			Warning:   "The certificates of the bundle expire in 10 days",
+		},
+		Operators: []cluster.OperatorStatus{
+			{Name: "authentication", Status: cluster.Status{Available: true}},
+			{Name: "console", Status: cluster.Status{Available: true}},
+		},
+		VMStats: &cluster.VMStats{
+			CPUs:        4,
+			CPUUsage:    12.5,
+			MemoryUsage: 6_000_000_000,
+			MemorySize:  9_000_000_000,
+			Uptime:      2*time.Hour + 3*time.Minute,
+		},
+		CertsExpiry: &machine.CertsExpiry{
+			KubeletClient: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC),
+			KubeletServer: time.Date(2021, 3, 1, 10, 5, 0, 0, time.UTC),
+		},
 	}, nil
 }
 
-func (c *Client) Exists() (bool, error) {
+func (c *Client) Exists(ctx context.Context) (bool, error) {
 	return true, nil
 }
 
-func (c *Client) IsRunning() (bool, error) {
+func (c *Client) IsRunning(ctx context.Context) (bool, error) {
 	return true, nil
 }
+
+func (c *Client) SaveSnapshot(ctx context.Context, name string) error {
+	if c.Failing {
+		return errors.New("snapshot failed")
+	}
+	return nil
+}
+
+func (c *Client) RestoreSnapshot(ctx context.Context, name string) error {
+	if c.Failing {
+		return errors.New("restore failed")
+	}
+	return nil
+}
+
+func (c *Client) DeleteSnapshot(ctx context.Context, name string) error {
+	if c.Failing {
+		return errors.New("snapshot deletion failed")
+	}
+	return nil
+}
+
+func (c *Client) ListSnapshots(ctx context.Context) ([]machine.Snapshot, error) {
+	if c.Failing {
+		return nil, errors.New("broken")
+	}
+	return []machine.Snapshot{
+		{
+			Name:       "clean",
+			CreatedAt:  time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC),
+			BundleName: "crc_libvirt_4.6.6.crcbundle",
+		},
+	}, nil
+}
+
+func (c *Client) AddMount(ctx context.Context, hostDir, vmDir string) error {
+	if c.Failing {
+		return errors.New("mount failed")
+	}
+	return nil
+}
+
+func (c *Client) RemoveMount(ctx context.Context, vmDir string) error {
+	if c.Failing {
+		return errors.New("unmount failed")
+	}
+	return nil
+}
+
+func (c *Client) ListMounts(ctx context.Context) ([]machine.Mount, error) {
+	if c.Failing {
+		return nil, errors.New("broken")
+	}
+	return []machine.Mount{
+		{
+			HostDir: "/home/user/src",
+			VMDir:   "/mnt/src",
+		},
+	}, nil
+}
+
+func (c *Client) Certificates(ctx context.Context) ([]cluster.Certificate, error) {
+	if c.Failing {
+		return nil, errors.New("broken")
+	}
+	return []cluster.Certificate{
+		{
+			Name:      "Kubelet client",
+			Location:  cluster.KubeletClientCert,
+			ExpiresAt: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			Name:      "Aggregator client CA",
+			Location:  cluster.AggregatorClientCert,
+			ExpiresAt: time.Date(2099, 3, 1, 10, 0, 0, 0, time.UTC),
+		},
+	}, nil
+}
+
+func (c *Client) RenewCertificates(ctx context.Context) error {
+	if c.Failing {
+		return errors.New("renewal failed")
+	}
+	return nil
+}
+
+func (c *Client) Pause(ctx context.Context) error {
+	if c.Failing {
+		return errors.New("pause failed")
+	}
+	return nil
+}
+
+func (c *Client) Resume(ctx context.Context) error {
+	if c.Failing {
+		return errors.New("resume failed")
+	}
+	return nil
+}
@@ -1,8 +1,12 @@
 package machine
 
-import "github.com/pkg/errors"
+import (
+	"context"
 
-func (client *client) IP() (string, error) {
+	"github.com/pkg/errors"
+)
+
+func (client *client) IP(ctx context.Context) (string, error) {
 	libMachineAPIClient, cleanup := createLibMachineClient()
 	defer cleanup()
 	host, err := libMachineAPIClient.Load(client.name)
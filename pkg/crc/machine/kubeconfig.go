@@ -25,13 +25,18 @@ import (
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
-const (
-	adminContext     = "crc-admin"
-	developerContext = "crc-developer"
-)
+// kubeconfigName scopes the clusters and users entries written to the
+// kubeconfig file for profiles other than the default one, so that several
+// profiles can be used with the same kubeconfig file
+func kubeconfigName(name, entry string) string {
+	if name == constants.DefaultName {
+		return entry
+	}
+	return fmt.Sprintf("%s-%s", name, entry)
+}
 
-func eventuallyWriteKubeconfig(ocConfig oc.Config, ip string, clusterConfig *ClusterConfig) error {
-	if err := errors.RetryAfter(60*time.Second, func() error {
+func eventuallyWriteKubeconfig(ctx gocontext.Context, name string, ocConfig oc.Config, ip string, clusterConfig *ClusterConfig) error {
+	if err := errors.RetryAfter(ctx, 60*time.Second, func() error {
 		status, err := cluster.GetClusterOperatorStatus(ocConfig, "authentication")
 		if err != nil {
 			return &errors.RetriableError{Err: err}
@@ -42,7 +47,7 @@ func eventuallyWriteKubeconfig(ocConfig oc.Config, ip string, clusterConfig *Clu
 		return &errors.RetriableError{Err: goerrors.New("cluster operator authentication not ready")}
 	}, 2*time.Second); err != nil {
 		logging.Warn("Skipping the kubeconfig update. Cluster operator authentication still not ready after 2min.")
-	} else if err := WriteKubeconfig(ip, clusterConfig); err != nil {
+	} else if err := WriteKubeconfig(name, ip, clusterConfig); err != nil {
 		return err
 	}
 	return nil
@@ -52,7 +57,7 @@ func isReady(status *cluster.Status) bool {
 	return status.Available && !status.Progressing && !status.Degraded && !status.Disabled
 }
 
-func WriteKubeconfig(ip string, clusterConfig *ClusterConfig) error {
+func WriteKubeconfig(name string, ip string, clusterConfig *ClusterConfig) error {
 	kubeconfig := getGlobalKubeConfigPath()
 	dir := filepath.Dir(kubeconfig)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -74,15 +79,18 @@ func WriteKubeconfig(ip string, clusterConfig *ClusterConfig) error {
 	if err != nil {
 		return err
 	}
-	cfg.Clusters[host] = &api.Cluster{
+	clusterName := kubeconfigName(name, host)
+	cfg.Clusters[clusterName] = &api.Cluster{
 		Server:                   clusterConfig.ClusterAPI,
 		CertificateAuthorityData: ca,
 	}
 
-	if err := addContext(cfg, ip, clusterConfig, ca, adminContext, "kubeadmin", clusterConfig.KubeAdminPass); err != nil {
+	adminContext := fmt.Sprintf("%s-admin", name)
+	if err := addContext(cfg, ip, clusterConfig, ca, clusterName, adminContext, kubeconfigName(name, "kubeadmin"), "kubeadmin", clusterConfig.KubeAdminPass); err != nil {
 		return err
 	}
-	if err := addContext(cfg, ip, clusterConfig, ca, developerContext, "developer", "developer"); err != nil {
+	developerContext := fmt.Sprintf("%s-developer", name)
+	if err := addContext(cfg, ip, clusterConfig, ca, clusterName, developerContext, kubeconfigName(name, "developer"), "developer", "developer"); err != nil {
 		return err
 	}
 
@@ -113,11 +121,7 @@ func hostname(clusterAPI string) (string, error) {
 	return p.Host, nil
 }
 
-func addContext(cfg *api.Config, ip string, clusterConfig *ClusterConfig, ca []byte, context, username, password string) error {
-	host, err := hostname(clusterConfig.ClusterAPI)
-	if err != nil {
-		return err
-	}
+func addContext(cfg *api.Config, ip string, clusterConfig *ClusterConfig, ca []byte, clusterName, context, authInfo, username, password string) error {
 	roots := x509.NewCertPool()
 	ok := roots.AppendCertsFromPEM(ca)
 	if !ok {
@@ -143,12 +147,12 @@ func addContext(cfg *api.Config, ip string, clusterConfig *ClusterConfig, ca []b
 	if err != nil {
 		return err
 	}
-	cfg.AuthInfos[username] = &api.AuthInfo{
+	cfg.AuthInfos[authInfo] = &api.AuthInfo{
 		Token: token,
 	}
 	cfg.Contexts[context] = &api.Context{
-		Cluster:   host,
-		AuthInfo:  username,
+		Cluster:   clusterName,
+		AuthInfo:  authInfo,
 		Namespace: "default",
 	}
 	return nil
@@ -0,0 +1,21 @@
+package libvirt
+
+import (
+	"fmt"
+
+	crcos "github.com/code-ready/crc/pkg/os"
+)
+
+// ConnectionURI is the libvirt connection used by crc-driver-libvirt to
+// manage the domains, the driver does not make it configurable
+const ConnectionURI = "qemu:///system"
+
+// Virsh runs a virsh command on the connection used by the driver, for the
+// operations which the driver plugin does not provide
+func Virsh(args ...string) (string, error) {
+	stdout, stderr, err := crcos.RunWithDefaultLocale("virsh", append([]string{"--connect", ConnectionURI}, args...)...)
+	if err != nil {
+		return "", fmt.Errorf("%v: %s", err, stderr)
+	}
+	return stdout, nil
+}
@@ -0,0 +1,168 @@
+package machine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/code-ready/crc/pkg/crc/logging"
+	crcssh "github.com/code-ready/crc/pkg/crc/ssh"
+	"github.com/code-ready/crc/pkg/libmachine"
+	"github.com/code-ready/crc/pkg/libmachine/host"
+	"github.com/code-ready/crc/pkg/libmachine/persist"
+	"github.com/code-ready/machine/libmachine/state"
+	"github.com/pkg/errors"
+)
+
+// mountTagPrefix identifies the shared directories managed by crc in the VM definition
+const mountTagPrefix = "crc-"
+
+// mountTag returns the tag of the shared directory mounted at vmDir. Tags
+// are limited to 36 characters by virtiofs.
+func mountTag(vmDir string) string {
+	sum := sha256.Sum256([]byte(vmDir))
+	return mountTagPrefix + hex.EncodeToString(sum[:])[:12]
+}
+
+func validateMount(hostDir, vmDir string) error {
+	if !filepath.IsAbs(hostDir) {
+		return fmt.Errorf("Host directory %s must be an absolute path", hostDir)
+	}
+	info, err := os.Stat(hostDir)
+	if err != nil {
+		return fmt.Errorf("Cannot share %s: %v", hostDir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("Cannot share %s: it is not a directory", hostDir)
+	}
+	if !path.IsAbs(vmDir) || path.Clean(vmDir) != vmDir || vmDir == "/" {
+		return fmt.Errorf("VM directory %s must be a clean absolute path other than /", vmDir)
+	}
+	if strings.ContainsAny(vmDir, "'\n") {
+		return fmt.Errorf("VM directory %s cannot contain quotes or new lines", vmDir)
+	}
+	return nil
+}
+
+func findMount(mounts []persist.Mount, vmDir string) int {
+	for i, mount := range mounts {
+		if mount.VMDir == vmDir {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddMount records a directory of the host to share with the VM. Shared
+// directories are attached to the VM and mounted when it starts, a running
+// VM must be restarted for a new mount to be available.
+func (client *client) AddMount(ctx context.Context, hostDir, vmDir string) error {
+	if err := validateMount(hostDir, vmDir); err != nil {
+		return err
+	}
+	if err := sharedDirectoriesSupported(); err != nil {
+		return err
+	}
+	libMachineAPIClient, cleanup := createLibMachineClient()
+	defer cleanup()
+	mounts, err := libMachineAPIClient.LoadMounts(client.name)
+	if err != nil {
+		return errors.Wrap(err, "Cannot load mounts")
+	}
+	if findMount(mounts, vmDir) >= 0 {
+		return fmt.Errorf("%s is already mounted in the VM", vmDir)
+	}
+	mounts = append(mounts, persist.Mount{
+		HostDir: hostDir,
+		VMDir:   vmDir,
+		Tag:     mountTag(vmDir),
+	})
+	return libMachineAPIClient.SaveMounts(client.name, mounts)
+}
+
+// RemoveMount stops sharing the directory mounted at vmDir. It is unmounted
+// right away when the VM is running.
+func (client *client) RemoveMount(ctx context.Context, vmDir string) error {
+	libMachineAPIClient, cleanup := createLibMachineClient()
+	defer cleanup()
+	mounts, err := libMachineAPIClient.LoadMounts(client.name)
+	if err != nil {
+		return errors.Wrap(err, "Cannot load mounts")
+	}
+	i := findMount(mounts, vmDir)
+	if i < 0 {
+		return fmt.Errorf("%s is not mounted in the VM", vmDir)
+	}
+
+	host, err := libMachineAPIClient.Load(client.name)
+	if err != nil {
+		return errors.Wrap(err, "Cannot load machine")
+	}
+	if vmState, err := host.Driver.GetState(); err == nil && vmState == state.Running {
+		if err := client.unmountInRunningVM(host, vmDir); err != nil {
+			logging.Warnf("Cannot unmount %s, it will be unmounted when the VM stops: %v", vmDir, err)
+		}
+	}
+	mounts = append(mounts[:i], mounts[i+1:]...)
+	return libMachineAPIClient.SaveMounts(client.name, mounts)
+}
+
+func (client *client) ListMounts(ctx context.Context) ([]Mount, error) {
+	libMachineAPIClient, cleanup := createLibMachineClient()
+	defer cleanup()
+	mounts, err := libMachineAPIClient.LoadMounts(client.name)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot load mounts")
+	}
+	var ret []Mount
+	for _, mount := range mounts {
+		ret = append(ret, Mount{
+			HostDir: mount.HostDir,
+			VMDir:   mount.VMDir,
+		})
+	}
+	return ret, nil
+}
+
+func (client *client) unmountInRunningVM(host *host.Host, vmDir string) error {
+	sshRunner, err := client.sshRunner(host)
+	if err != nil {
+		return err
+	}
+	defer sshRunner.Close()
+	_, stderr, err := sshRunner.Run(fmt.Sprintf("if mountpoint -q '%[1]s'; then sudo umount '%[1]s'; fi", vmDir))
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, stderr)
+	}
+	return nil
+}
+
+// configureSharedDirectories attaches the recorded mounts to the stopped VM
+func configureSharedDirectories(api libmachine.API, host *host.Host) error {
+	mounts, err := api.LoadMounts(host.Name)
+	if err != nil {
+		return errors.Wrap(err, "Cannot load mounts")
+	}
+	return updateSharedDirectories(host, mounts)
+}
+
+// mountSharedDirectories mounts the directories shared with the VM once it is running
+func mountSharedDirectories(api libmachine.API, name string, sshRunner *crcssh.Runner) error {
+	mounts, err := api.LoadMounts(name)
+	if err != nil {
+		return errors.Wrap(err, "Cannot load mounts")
+	}
+	for _, mount := range mounts {
+		logging.Infof("Mounting %s in %s", mount.HostDir, mount.VMDir)
+		command := fmt.Sprintf("sudo mkdir -p '%[1]s' && (mountpoint -q '%[1]s' || sudo mount -t virtiofs %s '%[1]s')", mount.VMDir, mount.Tag)
+		if _, stderr, err := sshRunner.Run(command); err != nil {
+			return fmt.Errorf("Cannot mount %s in %s: %v: %s", mount.HostDir, mount.VMDir, err, stderr)
+		}
+	}
+	return nil
+}
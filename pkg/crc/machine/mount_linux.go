@@ -0,0 +1,141 @@
+package machine
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/code-ready/crc/pkg/crc/machine/libvirt"
+	"github.com/code-ready/crc/pkg/libmachine/host"
+	"github.com/code-ready/crc/pkg/libmachine/persist"
+	crcos "github.com/code-ready/crc/pkg/os"
+	libvirtxml "github.com/libvirt/libvirt-go-xml"
+)
+
+// virtiofs file systems are supported by libvirt since 6.2.0
+const minVirtiofsLibvirtVersion = "6.2.0"
+
+// locations of virtiofsd in the distributions packages, libvirt also finds it in $PATH
+var virtiofsdPaths = []string{
+	"/usr/libexec/virtiofsd",
+	"/usr/lib/qemu/virtiofsd",
+	"/usr/lib/virtiofsd",
+}
+
+// sharedDirectoriesSupported checks that libvirt supports virtiofs and that
+// virtiofsd is installed, so that sharing directories does not fail when
+// the VM is started
+func sharedDirectoriesSupported() error {
+	stdout, _, err := crcos.RunWithDefaultLocale("virsh", "-v")
+	if err != nil {
+		return fmt.Errorf("Failed to run virsh: %v", err)
+	}
+	version, err := semver.NewVersion(strings.TrimSpace(stdout))
+	if err != nil {
+		return fmt.Errorf("Unable to parse installed libvirt version %v", err)
+	}
+	if version.LessThan(semver.MustParse(minVirtiofsLibvirtVersion)) {
+		return fmt.Errorf("Sharing directories with the VM needs libvirt %s or newer, libvirt %s is installed", minVirtiofsLibvirtVersion, version)
+	}
+	if !virtiofsdInstalled() {
+		return fmt.Errorf("Sharing directories with the VM needs virtiofsd, it is usually installed with the qemu-kvm, qemu-system-common or virtiofsd packages")
+	}
+	return nil
+}
+
+func virtiofsdInstalled() bool {
+	for _, path := range virtiofsdPaths {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	_, err := exec.LookPath("virtiofsd")
+	return err == nil
+}
+
+// updateSharedDirectories replaces the virtiofs file systems managed by crc
+// in the persistent definition of the libvirt domain. virtiofs needs the
+// memory of the domain to be shared with the virtiofsd daemon. The driver
+// plugin cannot change the definition, it is done with virsh on the domain
+// and the connection of the driver.
+func updateSharedDirectories(host *host.Host, mounts []persist.Mount) error {
+	stdout, err := libvirt.Virsh("dumpxml", "--inactive", host.Driver.GetMachineName())
+	if err != nil {
+		return err
+	}
+	domain := &libvirtxml.Domain{}
+	if err := domain.Unmarshal(stdout); err != nil {
+		return err
+	}
+	if !setSharedDirectories(domain, mounts) {
+		return nil
+	}
+	xml, err := domain.Marshal()
+	if err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile("", "crc-domain-*.xml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(xml); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	_, err = libvirt.Virsh("define", file.Name())
+	return err
+}
+
+// setSharedDirectories updates the domain definition, it returns false when
+// the domain does not need to be changed
+func setSharedDirectories(domain *libvirtxml.Domain, mounts []persist.Mount) bool {
+	if domain.Devices == nil {
+		domain.Devices = &libvirtxml.DomainDeviceList{}
+	}
+	var filesystems []libvirtxml.DomainFilesystem
+	removed := 0
+	for _, filesystem := range domain.Devices.Filesystems {
+		if filesystem.Target != nil && strings.HasPrefix(filesystem.Target.Dir, mountTagPrefix) {
+			removed++
+			continue
+		}
+		filesystems = append(filesystems, filesystem)
+	}
+	if removed == 0 && len(mounts) == 0 {
+		return false
+	}
+	for _, mount := range mounts {
+		filesystems = append(filesystems, libvirtxml.DomainFilesystem{
+			AccessMode: "passthrough",
+			Driver: &libvirtxml.DomainFilesystemDriver{
+				Type: "virtiofs",
+			},
+			Source: &libvirtxml.DomainFilesystemSource{
+				Mount: &libvirtxml.DomainFilesystemSourceMount{
+					Dir: mount.HostDir,
+				},
+			},
+			Target: &libvirtxml.DomainFilesystemTarget{
+				Dir: mount.Tag,
+			},
+		})
+	}
+	domain.Devices.Filesystems = filesystems
+
+	if len(mounts) > 0 {
+		if domain.MemoryBacking == nil {
+			domain.MemoryBacking = &libvirtxml.DomainMemoryBacking{}
+		}
+		domain.MemoryBacking.MemorySource = &libvirtxml.DomainMemorySource{Type: "memfd"}
+		domain.MemoryBacking.MemoryAccess = &libvirtxml.DomainMemoryAccess{Mode: "shared"}
+	}
+	return true
+}
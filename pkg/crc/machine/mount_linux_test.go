@@ -0,0 +1,40 @@
+package machine
+
+import (
+	"testing"
+
+	"github.com/code-ready/crc/pkg/libmachine/persist"
+	libvirtxml "github.com/libvirt/libvirt-go-xml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetSharedDirectories(t *testing.T) {
+	domain := &libvirtxml.Domain{
+		Devices: &libvirtxml.DomainDeviceList{
+			Filesystems: []libvirtxml.DomainFilesystem{
+				{Target: &libvirtxml.DomainFilesystemTarget{Dir: "user"}},
+			},
+		},
+	}
+	assert.False(t, setSharedDirectories(domain, nil))
+
+	assert.True(t, setSharedDirectories(domain, []persist.Mount{
+		{HostDir: "/home/user/src", VMDir: "/mnt/src", Tag: "crc-0123456789ab"},
+	}))
+	require.Len(t, domain.Devices.Filesystems, 2)
+	assert.Equal(t, "user", domain.Devices.Filesystems[0].Target.Dir)
+	filesystem := domain.Devices.Filesystems[1]
+	assert.Equal(t, "virtiofs", filesystem.Driver.Type)
+	assert.Equal(t, "/home/user/src", filesystem.Source.Mount.Dir)
+	assert.Equal(t, "crc-0123456789ab", filesystem.Target.Dir)
+	assert.Equal(t, "shared", domain.MemoryBacking.MemoryAccess.Mode)
+
+	xml, err := domain.Marshal()
+	require.NoError(t, err)
+	assert.Contains(t, xml, `<source dir="/home/user/src"></source>`)
+
+	assert.True(t, setSharedDirectories(domain, nil))
+	require.Len(t, domain.Devices.Filesystems, 1)
+	assert.Equal(t, "user", domain.Devices.Filesystems[0].Target.Dir)
+}
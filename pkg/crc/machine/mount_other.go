@@ -0,0 +1,22 @@
+//go:build !linux
+// +build !linux
+
+package machine
+
+import (
+	"errors"
+
+	"github.com/code-ready/crc/pkg/libmachine/host"
+	"github.com/code-ready/crc/pkg/libmachine/persist"
+)
+
+func sharedDirectoriesSupported() error {
+	return errors.New("Sharing directories with the VM is only supported with the libvirt driver")
+}
+
+func updateSharedDirectories(host *host.Host, mounts []persist.Mount) error {
+	if len(mounts) > 0 {
+		return sharedDirectoriesSupported()
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package machine
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMount(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mount")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "file")
+	require.NoError(t, ioutil.WriteFile(file, nil, 0600))
+
+	assert.NoError(t, validateMount(dir, "/mnt/src"))
+	assert.Error(t, validateMount("src", "/mnt/src"))
+	assert.Error(t, validateMount(filepath.Join(dir, "missing"), "/mnt/src"))
+	assert.Error(t, validateMount(file, "/mnt/src"))
+	assert.Error(t, validateMount(dir, "mnt/src"))
+	assert.Error(t, validateMount(dir, "/mnt/../src"))
+	assert.Error(t, validateMount(dir, "/"))
+	assert.Error(t, validateMount(dir, "/mnt/it's"))
+}
+
+func TestMountTag(t *testing.T) {
+	assert.Equal(t, mountTag("/mnt/src"), mountTag("/mnt/src"))
+	assert.NotEqual(t, mountTag("/mnt/src"), mountTag("/mnt/src2"))
+	assert.Regexp(t, "^crc-[0-9a-f]{12}$", mountTag("/mnt/src"))
+}
@@ -0,0 +1,84 @@
+package machine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/code-ready/crc/pkg/crc/cluster"
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/code-ready/crc/pkg/crc/oc"
+	"github.com/pkg/errors"
+)
+
+// Pause saves the memory of the VM to disk and stops it, freeing the CPU
+// and memory it was using without shutting down the guest
+func (client *client) Pause(ctx context.Context) error {
+	libMachineAPIClient, cleanup := createLibMachineClient()
+	defer cleanup()
+	host, err := client.loadRunningHost(libMachineAPIClient)
+	if err != nil {
+		return err
+	}
+
+	logging.Info("Pausing the OpenShift cluster...")
+	if err := pauseVM(host); err != nil {
+		return errors.Wrap(err, "Cannot pause machine")
+	}
+	return nil
+}
+
+// Resume restarts a paused VM. As the guest was not shut down, the start
+// steps done by Start are not needed, only the clock and the certificates
+// have to be checked as time passed while the VM was paused.
+func (client *client) Resume(ctx context.Context) error {
+	libMachineAPIClient, cleanup := createLibMachineClient()
+	defer cleanup()
+	host, err := libMachineAPIClient.Load(client.name)
+	if err != nil {
+		return errors.Wrap(err, "Cannot load machine")
+	}
+	paused, err := isPaused(host)
+	if err != nil {
+		return errors.Wrap(err, "Cannot get machine state")
+	}
+	if !paused {
+		return errors.New("The OpenShift cluster is not paused")
+	}
+
+	logging.Info("Resuming the OpenShift cluster...")
+	if err := host.Start(ctx); err != nil {
+		return errors.Wrap(err, "Error resuming machine")
+	}
+
+	sshRunner, err := client.sshRunner(host)
+	if err != nil {
+		return err
+	}
+	defer sshRunner.Close()
+	if err := cluster.WaitForSSH(ctx, sshRunner); err != nil {
+		return errors.Wrap(err, "Failed to connect to the CRC VM with SSH -- host might be unreachable")
+	}
+
+	logging.Info("Synchronizing the clock of the CodeReady Containers VM")
+	if _, _, err := sshRunner.Run(fmt.Sprintf("sudo date -u -s @%d", time.Now().Unix())); err != nil {
+		return errors.Wrap(err, "Failed to synchronize the VM clock")
+	}
+
+	logging.Info("Verifying validity of the kubelet certificates ...")
+	certsExpired, err := cluster.CheckCertsValidity(sshRunner)
+	if err != nil {
+		return errors.Wrap(err, "Failed to check certificate validity")
+	}
+	for cert, expired := range certsExpired {
+		if expired {
+			return fmt.Errorf("Certificate %s expired while the cluster was paused, run 'crc stop' and 'crc start' to renew it", cert)
+		}
+	}
+
+	if err := cluster.WaitForAPIServer(ctx, oc.UseOCWithSSH(sshRunner)); err != nil {
+		return errors.Wrap(err, "Error waiting for apiserver")
+	}
+	logging.Info("The OpenShift cluster is running")
+	return nil
+}
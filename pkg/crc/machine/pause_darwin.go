@@ -0,0 +1,19 @@
+package machine
+
+import (
+	"errors"
+
+	"github.com/code-ready/crc/pkg/libmachine/host"
+)
+
+func pauseVM(host *host.Host) error {
+	return errors.New("Pausing the OpenShift cluster is not supported with the hyperkit driver")
+}
+
+func isPaused(host *host.Host) (bool, error) {
+	return false, nil
+}
+
+func discardPausedState(host *host.Host) error {
+	return nil
+}
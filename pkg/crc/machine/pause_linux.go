@@ -0,0 +1,32 @@
+package machine
+
+import (
+	"strings"
+
+	"github.com/code-ready/crc/pkg/crc/machine/libvirt"
+	"github.com/code-ready/crc/pkg/libmachine/host"
+)
+
+// libvirt managed save writes the memory of the domain to disk and stops
+// it, the next start of the domain restores it. The libvirt driver reports
+// such a domain as stopped. The driver plugin has no operation for it, it
+// is done with virsh on the domain and the connection of the driver.
+func pauseVM(host *host.Host) error {
+	_, err := libvirt.Virsh("managedsave", host.Driver.GetMachineName())
+	return err
+}
+
+func isPaused(host *host.Host) (bool, error) {
+	stdout, err := libvirt.Virsh("domstate", "--reason", host.Driver.GetMachineName())
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(stdout) == "shut off (saved)", nil
+}
+
+// discardPausedState removes the memory saved by pauseVM, libvirt refuses
+// to undefine a domain which still has it
+func discardPausedState(host *host.Host) error {
+	_, err := libvirt.Virsh("managedsave-remove", host.Driver.GetMachineName())
+	return err
+}
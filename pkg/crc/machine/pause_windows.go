@@ -0,0 +1,32 @@
+package machine
+
+import (
+	"fmt"
+
+	"github.com/code-ready/crc/pkg/libmachine/host"
+	"github.com/code-ready/crc/pkg/os/windows/powershell"
+	"github.com/code-ready/machine/libmachine/state"
+)
+
+// Save-VM writes the memory of the VM to disk and stops it, Start-VM
+// restores it
+func pauseVM(host *host.Host) error {
+	_, stderr, err := powershell.Execute("Hyper-V\\Save-VM", "-Name", host.Name)
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, stderr)
+	}
+	return nil
+}
+
+func isPaused(host *host.Host) (bool, error) {
+	vmState, err := host.Driver.GetState()
+	if err != nil {
+		return false, err
+	}
+	return vmState == state.Saved, nil
+}
+
+// discardPausedState is not needed as Remove-VM also removes the saved memory
+func discardPausedState(host *host.Host) error {
+	return nil
+}
@@ -1,8 +1,12 @@
 package machine
 
-import "github.com/pkg/errors"
+import (
+	"context"
 
-func (client *client) PowerOff() error {
+	"github.com/pkg/errors"
+)
+
+func (client *client) PowerOff(ctx context.Context) error {
 	libMachineAPIClient, cleanup := createLibMachineClient()
 	defer cleanup()
 
@@ -11,7 +15,7 @@ func (client *client) PowerOff() error {
 		return errors.Wrap(err, "Cannot load machine")
 	}
 
-	if err := host.Kill(); err != nil {
+	if err := host.Kill(ctx); err != nil {
 		return errors.Wrap(err, "Cannot kill machine")
 	}
 	return nil
@@ -0,0 +1,31 @@
+package machine
+
+// Steps reported through StartConfig.Progress
+const (
+	StepLoadBundle       = "load-bundle"
+	StepCreateVM         = "create-vm"
+	StepStartVM          = "start-vm"
+	StepWaitForSSH       = "wait-for-ssh"
+	StepConfigureVM      = "configure-vm"
+	StepCheckDNS         = "check-dns"
+	StepMountDirectories = "mount-directories"
+	StepCheckCerts       = "check-certs"
+	StepStartKubelet     = "start-kubelet"
+	StepRenewCerts       = "renew-certs"
+	StepWaitForAPIServer = "wait-for-apiserver"
+	StepConfigureCluster = "configure-cluster"
+	StepWaitForCluster   = "wait-for-cluster"
+	StepUpdateKubeconfig = "update-kubeconfig"
+	StepDone             = "done"
+)
+
+func (startConfig StartConfig) reportProgress(step string, percentage int, message string) {
+	if startConfig.Progress == nil {
+		return
+	}
+	startConfig.Progress(StartProgress{
+		Step:       step,
+		Percentage: percentage,
+		Message:    message,
+	})
+}
@@ -0,0 +1,23 @@
+package machine
+
+import (
+	cmdConfig "github.com/code-ready/crc/cmd/crc/cmd/config"
+	"github.com/code-ready/crc/pkg/crc/cluster"
+	"github.com/code-ready/crc/pkg/crc/network"
+)
+
+// registriesConfig returns the registry mirrors and the insecure registries of the configuration
+func (client *client) registriesConfig() (*cluster.RegistriesConfig, error) {
+	mirrors, err := network.ParseRegistryMirrors(client.config.Get(cmdConfig.RegistryMirrors).AsString())
+	if err != nil {
+		return nil, err
+	}
+	insecure, err := network.ParseInsecureRegistries(client.config.Get(cmdConfig.InsecureRegistries).AsString())
+	if err != nil {
+		return nil, err
+	}
+	return &cluster.RegistriesConfig{
+		Mirrors:  mirrors,
+		Insecure: insecure,
+	}, nil
+}
@@ -0,0 +1,34 @@
+package machine
+
+import (
+	"testing"
+
+	cmdConfig "github.com/code-ready/crc/cmd/crc/cmd/config"
+	crcConfig "github.com/code-ready/crc/pkg/crc/config"
+	"github.com/code-ready/crc/pkg/crc/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistriesConfig(t *testing.T) {
+	cfg := crcConfig.New(crcConfig.NewEmptyInMemoryStorage())
+	cmdConfig.RegisterSettings(cfg)
+	client := NewClient("crc", false, cfg).(*client)
+
+	registries, err := client.registriesConfig()
+	assert.NoError(t, err)
+	assert.Empty(t, registries.Mirrors)
+	assert.Empty(t, registries.Insecure)
+
+	_, err = cfg.Set(cmdConfig.RegistryMirrors, "registry.redhat.io=mirror.lan/redhat")
+	require.NoError(t, err)
+	_, err = cfg.Set(cmdConfig.InsecureRegistries, "mirror.lan")
+	require.NoError(t, err)
+	registries, err = client.registriesConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []network.RegistryMirror{{Source: "registry.redhat.io", Mirror: "mirror.lan/redhat"}}, registries.Mirrors)
+	assert.Equal(t, []string{"mirror.lan"}, registries.Insecure)
+
+	_, err = cfg.Set(cmdConfig.RegistryMirrors, "registry.redhat.io")
+	assert.Error(t, err)
+}
@@ -0,0 +1,160 @@
+package machine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/code-ready/crc/pkg/libmachine"
+	"github.com/code-ready/crc/pkg/libmachine/host"
+	"github.com/code-ready/crc/pkg/libmachine/persist"
+	"github.com/code-ready/machine/libmachine/state"
+	"github.com/pkg/errors"
+)
+
+var validSnapshotName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,63}$`)
+
+func validateSnapshotName(name string) error {
+	if !validSnapshotName.MatchString(name) {
+		return fmt.Errorf("Invalid snapshot name '%s': it must start with a letter or a digit and only contain letters, digits, '_', '.' and '-'", name)
+	}
+	return nil
+}
+
+func findSnapshot(snapshots []persist.Snapshot, name string) int {
+	for i, snapshot := range snapshots {
+		if snapshot.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// loadStoppedHost loads the machine and makes sure it is stopped, as the
+// disk image cannot be snapshotted or reverted while the VM uses it
+func (client *client) loadStoppedHost(api *libmachine.Client) (*host.Host, error) {
+	host, err := api.Load(client.name)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot load machine")
+	}
+	vmState, err := host.Driver.GetState()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot get machine state")
+	}
+	if paused, err := isPaused(host); err == nil && paused {
+		return nil, errors.New("The OpenShift cluster is paused, run 'crc resume' and 'crc stop' before managing its snapshots")
+	}
+	if vmState != state.Stopped {
+		return nil, errors.New("The OpenShift cluster must be stopped to manage its snapshots, run 'crc stop' first")
+	}
+	return host, nil
+}
+
+func (client *client) SaveSnapshot(ctx context.Context, name string) error {
+	if err := validateSnapshotName(name); err != nil {
+		return err
+	}
+	libMachineAPIClient, cleanup := createLibMachineClient()
+	defer cleanup()
+	host, err := client.loadStoppedHost(libMachineAPIClient)
+	if err != nil {
+		return err
+	}
+	snapshots, err := libMachineAPIClient.LoadSnapshots(client.name)
+	if err != nil {
+		return errors.Wrap(err, "Cannot load snapshots")
+	}
+	if findSnapshot(snapshots, name) >= 0 {
+		return fmt.Errorf("Snapshot '%s' already exists", name)
+	}
+
+	logging.Infof("Saving snapshot '%s'...", name)
+	if err := createDiskSnapshot(host, name); err != nil {
+		return errors.Wrap(err, "Cannot create disk snapshot")
+	}
+	snapshots = append(snapshots, persist.Snapshot{
+		Name:      name,
+		CreatedAt: time.Now(),
+		RawDriver: host.RawDriver,
+	})
+	return libMachineAPIClient.SaveSnapshots(client.name, snapshots)
+}
+
+// RestoreSnapshot reverts the disk to the given snapshot and restores the
+// driver configuration recorded with it, so that the bundle the VM was
+// created from is the one used for the next start
+func (client *client) RestoreSnapshot(ctx context.Context, name string) error {
+	libMachineAPIClient, cleanup := createLibMachineClient()
+	defer cleanup()
+	host, err := client.loadStoppedHost(libMachineAPIClient)
+	if err != nil {
+		return err
+	}
+	snapshots, err := libMachineAPIClient.LoadSnapshots(client.name)
+	if err != nil {
+		return errors.Wrap(err, "Cannot load snapshots")
+	}
+	i := findSnapshot(snapshots, name)
+	if i < 0 {
+		return fmt.Errorf("Snapshot '%s' does not exist", name)
+	}
+
+	logging.Infof("Restoring snapshot '%s'...", name)
+	if err := revertDiskSnapshot(host, name); err != nil {
+		return errors.Wrap(err, "Cannot revert disk to snapshot")
+	}
+	if err := host.UpdateConfig(snapshots[i].RawDriver); err != nil {
+		return errors.Wrap(err, "Cannot restore machine configuration")
+	}
+	return libMachineAPIClient.Save(host)
+}
+
+func (client *client) DeleteSnapshot(ctx context.Context, name string) error {
+	libMachineAPIClient, cleanup := createLibMachineClient()
+	defer cleanup()
+	host, err := client.loadStoppedHost(libMachineAPIClient)
+	if err != nil {
+		return err
+	}
+	snapshots, err := libMachineAPIClient.LoadSnapshots(client.name)
+	if err != nil {
+		return errors.Wrap(err, "Cannot load snapshots")
+	}
+	i := findSnapshot(snapshots, name)
+	if i < 0 {
+		return fmt.Errorf("Snapshot '%s' does not exist", name)
+	}
+
+	if err := deleteDiskSnapshot(host, name); err != nil {
+		return errors.Wrap(err, "Cannot delete disk snapshot")
+	}
+	snapshots = append(snapshots[:i], snapshots[i+1:]...)
+	return libMachineAPIClient.SaveSnapshots(client.name, snapshots)
+}
+
+func (client *client) ListSnapshots(ctx context.Context) ([]Snapshot, error) {
+	libMachineAPIClient, cleanup := createLibMachineClient()
+	defer cleanup()
+	snapshots, err := libMachineAPIClient.LoadSnapshots(client.name)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot load snapshots")
+	}
+	var ret []Snapshot
+	for _, snapshot := range snapshots {
+		var driver struct {
+			BundleName string
+		}
+		if err := json.Unmarshal(snapshot.RawDriver, &driver); err != nil {
+			logging.Debugf("Cannot read the driver configuration of snapshot %s: %v", snapshot.Name, err)
+		}
+		ret = append(ret, Snapshot{
+			Name:       snapshot.Name,
+			CreatedAt:  snapshot.CreatedAt,
+			BundleName: driver.BundleName,
+		})
+	}
+	return ret, nil
+}
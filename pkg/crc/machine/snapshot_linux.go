@@ -0,0 +1,45 @@
+package machine
+
+import (
+	"fmt"
+
+	"github.com/code-ready/crc/pkg/libmachine/host"
+	crcos "github.com/code-ready/crc/pkg/os"
+)
+
+// The libvirt driver uses a qcow2 image backed by the bundle disk image,
+// snapshots are stored inside this image with qemu-img
+func diskImagePath(host *host.Host) (string, error) {
+	driver, err := loadDriverConfig(host)
+	if err != nil {
+		return "", err
+	}
+	if driver.ImageFormat != "qcow2" {
+		return "", fmt.Errorf("Snapshots are not supported with %s disk images", driver.ImageFormat)
+	}
+	return driver.ResolveStorePath(fmt.Sprintf("%s.%s", driver.MachineName, driver.ImageFormat)), nil
+}
+
+func qemuImgSnapshot(host *host.Host, flag string, name string) error {
+	diskPath, err := diskImagePath(host)
+	if err != nil {
+		return err
+	}
+	_, stderr, err := crcos.RunWithDefaultLocale("qemu-img", "snapshot", flag, name, diskPath)
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, stderr)
+	}
+	return nil
+}
+
+func createDiskSnapshot(host *host.Host, name string) error {
+	return qemuImgSnapshot(host, "-c", name)
+}
+
+func revertDiskSnapshot(host *host.Host, name string) error {
+	return qemuImgSnapshot(host, "-a", name)
+}
+
+func deleteDiskSnapshot(host *host.Host, name string) error {
+	return qemuImgSnapshot(host, "-d", name)
+}
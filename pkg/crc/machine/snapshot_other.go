@@ -0,0 +1,24 @@
+//go:build !linux
+// +build !linux
+
+package machine
+
+import (
+	"errors"
+
+	"github.com/code-ready/crc/pkg/libmachine/host"
+)
+
+var errSnapshotsNotSupported = errors.New("Snapshots are only supported with the libvirt driver")
+
+func createDiskSnapshot(host *host.Host, name string) error {
+	return errSnapshotsNotSupported
+}
+
+func revertDiskSnapshot(host *host.Host, name string) error {
+	return errSnapshotsNotSupported
+}
+
+func deleteDiskSnapshot(host *host.Host, name string) error {
+	return errSnapshotsNotSupported
+}
@@ -0,0 +1,16 @@
+package machine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSnapshotName(t *testing.T) {
+	assert.NoError(t, validateSnapshotName("clean"))
+	assert.NoError(t, validateSnapshotName("before-operator_v1.2"))
+	assert.Error(t, validateSnapshotName(""))
+	assert.Error(t, validateSnapshotName("-c"))
+	assert.Error(t, validateSnapshotName("with space"))
+	assert.Error(t, validateSnapshotName("../clean"))
+}
@@ -0,0 +1,77 @@
+package machine
+
+import (
+	"context"
+
+	"github.com/code-ready/crc/pkg/crc/constants"
+	crcssh "github.com/code-ready/crc/pkg/crc/ssh"
+	"github.com/code-ready/crc/pkg/libmachine"
+	"github.com/code-ready/crc/pkg/libmachine/host"
+	"github.com/code-ready/machine/libmachine/state"
+	"github.com/pkg/errors"
+)
+
+// ConnectionDetails returns what is needed to connect to the running VM with SSH
+func (client *client) ConnectionDetails(ctx context.Context) (*ConnectionDetails, error) {
+	libMachineAPIClient, cleanup := createLibMachineClient()
+	defer cleanup()
+	host, err := client.loadRunningHost(libMachineAPIClient)
+	if err != nil {
+		return nil, err
+	}
+	ip, err := getIP(host, client.useVSock())
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot get IP")
+	}
+	return &ConnectionDetails{
+		IP:          ip,
+		SSHPort:     getSSHPort(client.useVSock()),
+		SSHUsername: constants.DefaultSSHUser,
+		SSHKeys:     []string{constants.GetPrivateKeyPath(client.name), constants.GetRsaPrivateKeyPath(client.name)},
+	}, nil
+}
+
+// loadRunningHost loads the machine, or returns an error when the VM is not
+// running or is paused
+func (client *client) loadRunningHost(libMachineAPIClient *libmachine.Client) (*host.Host, error) {
+	host, err := libMachineAPIClient.Load(client.name)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot load machine")
+	}
+	vmState, err := host.Driver.GetState()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot get machine state")
+	}
+	if paused, err := isPaused(host); err == nil && paused {
+		return nil, errors.New("The OpenShift cluster is paused, run 'crc resume' first")
+	}
+	if vmState != state.Running {
+		return nil, errors.New("The OpenShift cluster is not running")
+	}
+	return host, nil
+}
+
+// sshRunner returns a runner connected to the VM of host
+func (client *client) sshRunner(host *host.Host) (*crcssh.Runner, error) {
+	ip, err := getIP(host, client.useVSock())
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting the IP")
+	}
+	sshRunner, err := crcssh.CreateRunner(ip, getSSHPort(client.useVSock()), constants.GetPrivateKeyPath(client.name), constants.GetRsaPrivateKeyPath(client.name))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating the ssh client")
+	}
+	return sshRunner, nil
+}
+
+// runningVMSSHRunner returns a runner connected to the VM, or an error when
+// the cluster is not running
+func (client *client) runningVMSSHRunner() (*crcssh.Runner, error) {
+	libMachineAPIClient, cleanup := createLibMachineClient()
+	defer cleanup()
+	host, err := client.loadRunningHost(libMachineAPIClient)
+	if err != nil {
+		return nil, err
+	}
+	return client.sshRunner(host)
+}
@@ -1,12 +1,11 @@
 package machine
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"runtime"
-	"strconv"
 	"strings"
 	"time"
 
@@ -18,11 +17,9 @@ import (
 	"github.com/code-ready/crc/pkg/crc/machine/config"
 	"github.com/code-ready/crc/pkg/crc/network"
 	"github.com/code-ready/crc/pkg/crc/oc"
-	"github.com/code-ready/crc/pkg/crc/services"
-	"github.com/code-ready/crc/pkg/crc/services/dns"
+	"github.com/code-ready/crc/pkg/crc/profile"
 	"github.com/code-ready/crc/pkg/crc/ssh"
 	crcssh "github.com/code-ready/crc/pkg/crc/ssh"
-	"github.com/code-ready/crc/pkg/crc/systemd"
 	"github.com/code-ready/crc/pkg/libmachine"
 	"github.com/code-ready/crc/pkg/libmachine/host"
 	crcos "github.com/code-ready/crc/pkg/os"
@@ -87,304 +84,58 @@ func (client *client) updateVMConfig(startConfig StartConfig, api libmachine.API
 	return nil
 }
 
-func (client *client) Start(startConfig StartConfig) (*StartResult, error) {
-	if err := client.validateStartConfig(startConfig); err != nil {
-		return nil, err
-	}
-
-	var crcBundleMetadata *bundle.CrcBundleInfo
-
-	libMachineAPIClient, cleanup := createLibMachineClient()
-	defer cleanup()
-
-	// Pre-VM start
-	var host *host.Host
-	exists, err := client.Exists()
-	if err != nil {
-		return nil, errors.Wrap(err, "Cannot determine if VM exists")
-	}
-	if !exists {
-		// Ask early for pull secret if it hasn't been requested yet
-		_, err = startConfig.PullSecret.Value()
-		if err != nil {
-			return nil, errors.Wrap(err, "Failed to ask for pull secret")
-		}
-
-		machineConfig := config.MachineConfig{
-			Name:        client.name,
-			BundleName:  filepath.Base(startConfig.BundlePath),
-			CPUs:        startConfig.CPUs,
-			Memory:      startConfig.Memory,
-			DiskSize:    startConfig.DiskSize,
-			NetworkMode: client.networkMode(),
-		}
-
-		crcBundleMetadata, err = getCrcBundleInfo(startConfig.BundlePath)
-		if err != nil {
-			return nil, errors.Wrap(err, "Error getting bundle metadata")
-		}
-
-		logging.Infof("Creating CodeReady Containers VM for OpenShift %s...", crcBundleMetadata.GetOpenshiftVersion())
-
-		// Retrieve metadata info
-		machineConfig.ImageSourcePath = crcBundleMetadata.GetDiskImagePath()
-		machineConfig.ImageFormat = crcBundleMetadata.Storage.DiskImages[0].Format
-		machineConfig.SSHKeyPath = crcBundleMetadata.GetSSHKeyPath()
-		machineConfig.KernelCmdLine = crcBundleMetadata.Nodes[0].KernelCmdLine
-		machineConfig.Initramfs = crcBundleMetadata.GetInitramfsPath()
-		machineConfig.Kernel = crcBundleMetadata.GetKernelPath()
-
-		host, err = createHost(libMachineAPIClient, machineConfig)
-		if err != nil {
-			return nil, errors.Wrap(err, "Error creating machine")
-		}
-	} else { // exists
-		host, err = libMachineAPIClient.Load(client.name)
-		if err != nil {
-			return nil, errors.Wrap(err, "Error loading machine")
-		}
-
-		var bundleName string
-		bundleName, crcBundleMetadata, err = getBundleMetadataFromDriver(host.Driver)
-		if err != nil {
-			return nil, errors.Wrap(err, "Error loading bundle metadata")
-		}
-		if bundleName != filepath.Base(startConfig.BundlePath) {
-			logging.Debugf("Bundle '%s' was requested, but the existing VM is using '%s'",
-				filepath.Base(startConfig.BundlePath), bundleName)
-			return nil, fmt.Errorf("Bundle '%s' was requested, but the existing VM is using '%s'",
-				filepath.Base(startConfig.BundlePath),
-				bundleName)
-		}
-		vmState, err := host.Driver.GetState()
-		if err != nil {
-			return nil, errors.Wrap(err, "Error getting the machine state")
-		}
-		if vmState == state.Running {
-			logging.Infof("A CodeReady Containers VM for OpenShift %s is already running", crcBundleMetadata.GetOpenshiftVersion())
-			clusterConfig, err := getClusterConfig(crcBundleMetadata)
-			if err != nil {
-				return nil, errors.Wrap(err, "Cannot create cluster configuration")
-			}
-			return &StartResult{
-				Status:         vmState,
-				ClusterConfig:  *clusterConfig,
-				KubeletStarted: true,
-			}, nil
-		}
-
-		logging.Infof("Starting CodeReady Containers VM for OpenShift %s...", crcBundleMetadata.GetOpenshiftVersion())
-
-		if err := client.updateVMConfig(startConfig, libMachineAPIClient, host); err != nil {
-			return nil, errors.Wrap(err, "Could not update CRC VM configuration")
-		}
-
-		if err := host.Driver.Start(); err != nil {
-			return nil, errors.Wrap(err, "Error starting stopped VM")
-		}
-	}
-
-	if runtime.GOOS == "darwin" && client.useVSock() {
-		if err := makeDaemonVisibleToHyperkit(client.name); err != nil {
-			return nil, err
-		}
-	}
-
-	clusterConfig, err := getClusterConfig(crcBundleMetadata)
-	if err != nil {
-		return nil, errors.Wrap(err, "Cannot create cluster configuration")
-	}
-
-	// Post-VM start
-	vmState, err := host.Driver.GetState()
-	if err != nil {
-		return nil, errors.Wrap(err, "Error getting the state")
-	}
-	if vmState != state.Running {
-		return nil, errors.Wrap(err, "CodeReady Containers VM is not running")
-	}
-
-	instanceIP, err := getIP(host, client.useVSock())
-	if err != nil {
-		return nil, errors.Wrap(err, "Error getting the IP")
-	}
-	sshRunner, err := crcssh.CreateRunner(instanceIP, getSSHPort(client.useVSock()), crcBundleMetadata.GetSSHKeyPath(), constants.GetPrivateKeyPath(), constants.GetRsaPrivateKeyPath())
-	if err != nil {
-		return nil, errors.Wrap(err, "Error creating the ssh client")
-	}
-	defer sshRunner.Close()
-
-	logging.Debug("Waiting until ssh is available")
-	if err := cluster.WaitForSSH(sshRunner); err != nil {
-		return nil, errors.Wrap(err, "Failed to connect to the CRC VM with SSH -- host might be unreachable")
-	}
-	logging.Info("CodeReady Containers VM is running")
-
-	// Post VM start immediately update SSH key and copy kubeconfig to instance
-	// dir and VM
-	if err := updateSSHKeyAndCopyKubeconfig(sshRunner, client.name, crcBundleMetadata); err != nil {
-		return nil, errors.Wrap(err, "Error updating public key")
-	}
-
-	// Trigger disk resize, this will be a no-op if no disk size change is needed
-	if _, _, err = sshRunner.Run("sudo xfs_growfs / >/dev/null"); err != nil {
-		return nil, errors.Wrap(err, "Error updating filesystem size")
-	}
-
-	// Start network time synchronization if `CRC_DEBUG_ENABLE_STOP_NTP` is not set
-	if stopNtp, _ := strconv.ParseBool(os.Getenv("CRC_DEBUG_ENABLE_STOP_NTP")); !stopNtp {
-		logging.Info("Starting network time synchronization in CodeReady Containers VM")
-		if _, _, err := sshRunner.Run("sudo timedatectl set-ntp on"); err != nil {
-			return nil, errors.Wrap(err, "Failed to start network time synchronization")
-		}
-	}
-
-	// Add nameserver to VM if provided by User
-	if startConfig.NameServer != "" {
-		if err = addNameServerToInstance(sshRunner, startConfig.NameServer); err != nil {
-			return nil, errors.Wrap(err, "Failed to add nameserver to the VM")
-		}
-	}
-
-	proxyConfig, err := getProxyConfig(crcBundleMetadata.ClusterInfo.BaseDomain)
-	if err != nil {
-		return nil, errors.Wrap(err, "Error getting proxy configuration")
-	}
-	proxyConfig.ApplyToEnvironment()
-	proxyConfig.AddNoProxy(instanceIP)
-
-	// Create servicePostStartConfig for DNS checks and DNS start.
-	servicePostStartConfig := services.ServicePostStartConfig{
-		Name: client.name,
-		// TODO: would prefer passing in a more generic type
-		SSHRunner: sshRunner,
-		IP:        instanceIP,
-		// TODO: should be more finegrained
-		BundleMetadata: *crcBundleMetadata,
-		NetworkMode:    client.networkMode(),
-	}
-
-	// Run the DNS server inside the VM
-	if err := dns.RunPostStart(servicePostStartConfig); err != nil {
-		return nil, errors.Wrap(err, "Error running post start")
-	}
-
-	// Check DNS lookup before starting the kubelet
-	if queryOutput, err := dns.CheckCRCLocalDNSReachable(servicePostStartConfig); err != nil {
-		if !client.useVSock() {
-			return nil, errors.Wrapf(err, "Failed internal DNS query: %s", queryOutput)
-		}
-		logging.Warn(fmt.Sprintf("Failed internal DNS query: %s: %v", queryOutput, err))
-	}
-	logging.Info("Check internal and public DNS query ...")
-
-	if queryOutput, err := dns.CheckCRCPublicDNSReachable(servicePostStartConfig); err != nil {
-		logging.Warnf("Failed public DNS query from the cluster: %v : %s", err, queryOutput)
-	}
-
-	// Check DNS lookup from host to VM
-	logging.Info("Check DNS query from host ...")
-	if err := network.CheckCRCLocalDNSReachableFromHost(crcBundleMetadata, instanceIP); err != nil {
-		if !client.useVSock() {
-			return nil, errors.Wrap(err, "Failed to query DNS from host")
-		}
-		logging.Warn(fmt.Sprintf("Failed to query DNS from host: %v", err))
-	}
-
-	if err := cluster.EnsurePullSecretPresentOnInstanceDisk(sshRunner, startConfig.PullSecret); err != nil {
-		return nil, errors.Wrap(err, "Failed to update VM pull secret")
-	}
-
-	if err := ensureKubeletAndCRIOAreConfiguredForProxy(sshRunner, proxyConfig, instanceIP); err != nil {
-		return nil, errors.Wrap(err, "Failed to update proxy configuration of kubelet and crio")
+// Start creates or starts the VM and then the OpenShift cluster.
+// When ctx is cancelled, the VM is stopped so that it is not left half configured.
+func (client *client) Start(ctx context.Context, startConfig StartConfig) (*StartResult, error) {
+	result, err := client.start(ctx, startConfig)
+	if err != nil && ctx.Err() != nil {
+		client.stopAfterCancel()
+		return nil, errors.Wrap(ctx.Err(), "Start was cancelled")
 	}
+	return result, err
+}
 
-	// Check the certs validity inside the vm
-	logging.Info("Verifying validity of the kubelet certificates ...")
-	certsExpired, err := cluster.CheckCertsValidity(sshRunner)
+func (client *client) stopAfterCancel() {
+	// ctx is already cancelled, a new context is needed to wait for the VM to be stopped
+	running, err := client.IsRunning(context.Background())
 	if err != nil {
-		return nil, errors.Wrap(err, "Failed to check certificate validity")
-	}
-
-	logging.Info("Starting OpenShift kubelet service")
-	sd := systemd.NewInstanceSystemdCommander(sshRunner)
-	if err := sd.Start("kubelet"); err != nil {
-		return nil, errors.Wrap(err, "Error starting kubelet")
-	}
-
-	ocConfig := oc.UseOCWithSSH(sshRunner)
-
-	if err := cluster.ApproveCSRAndWaitForCertsRenewal(sshRunner, ocConfig, certsExpired[cluster.KubeletClientCert], certsExpired[cluster.KubeletServerCert]); err != nil {
-		logBundleDate(crcBundleMetadata)
-		return nil, errors.Wrap(err, "Failed to renew TLS certificates: please check if a newer CodeReady Containers release is available")
-	}
-
-	if err := cluster.WaitForAPIServer(ocConfig); err != nil {
-		return nil, errors.Wrap(err, "Error waiting for apiserver")
+		logging.Debugf("Cannot get VM state after cancelling start: %v", err)
+		return
 	}
-
-	if err := ensureProxyIsConfiguredInOpenShift(ocConfig, sshRunner, proxyConfig, instanceIP); err != nil {
-		return nil, errors.Wrap(err, "Failed to update cluster proxy configuration")
+	if !running {
+		return
 	}
-
-	if err := cluster.EnsurePullSecretPresentInTheCluster(ocConfig, startConfig.PullSecret); err != nil {
-		return nil, errors.Wrap(err, "Failed to update cluster pull secret")
+	logging.Info("Start was cancelled, stopping the CodeReady Containers VM ...")
+	if _, err := client.Stop(context.Background(), StopConfig{}); err != nil {
+		logging.Warnf("Cannot stop the VM after cancelling start: %v", err)
 	}
+}
 
-	if err := cluster.EnsureClusterIDIsNotEmpty(ocConfig); err != nil {
-		return nil, errors.Wrap(err, "Failed to update cluster ID")
+// waitForCluster waits for the condition requested in startConfig. Only a
+// cancellation is reported as an error: a cluster which is not ready before
+// the timeout may still become ready later on.
+func (client *client) waitForCluster(ctx context.Context, ocConfig oc.Config, startConfig StartConfig) error {
+	if startConfig.Wait == constants.WaitNone {
+		logging.Info("Not waiting for the cluster operators to be ready")
+		return nil
 	}
-
-	if client.monitoringEnabled() {
-		logging.Info("Enabling cluster monitoring operator...")
-		if err := cluster.StartMonitoring(ocConfig); err != nil {
-			return nil, errors.Wrap(err, "Cannot start monitoring stack")
-		}
+	timeout := startConfig.WaitTimeout
+	if timeout == 0 {
+		timeout, _ = time.ParseDuration(constants.DefaultWaitTimeout)
 	}
-
-	// In Openshift 4.3, when cluster comes up, the following happens
-	// 1. After the openshift-apiserver pod is started, its log contains multiple occurrences of `certificate has expired or is not yet valid`
-	// 2. Initially there is no request-header's client-ca crt available to `extension-apiserver-authentication` configmap
-	// 3. In the pod logs `missing content for CA bundle "client-ca::kube-system::extension-apiserver-authentication::requestheader-client-ca-file"`
-	// 4. After ~1 min /etc/kubernetes/static-pod-resources/kube-apiserver-certs/configmaps/aggregator-client-ca/ca-bundle.crt is regenerated
-	// 5. It is now also appear to `extension-apiserver-authentication` configmap as part of request-header's client-ca content
-	// 6. Openshift-apiserver is able to load the CA which was regenerated
-	// 7. Now apiserver pod log contains multiple occurrences of `error x509: certificate signed by unknown authority`
-	// When the openshift-apiserver is in this state, the cluster is non functional.
-	// A restart of the openshift-apiserver pod is enough to clear that error and get a working cluster.
-	// This is a work-around while the root cause is being identified.
-	// More info: https://bugzilla.redhat.com/show_bug.cgi?id=1795163
-	if certsExpired[cluster.AggregatorClientCert] {
-		logging.Debug("Waiting for the renewal of the request header client ca...")
-		if err := cluster.WaitForRequestHeaderClientCaFile(sshRunner); err != nil {
-			return nil, errors.Wrap(err, "Failed to wait for aggregator client ca renewal")
+	logging.Infof("Starting OpenShift cluster ... [waiting up to %s for the cluster operators]", timeout)
+	if err := cluster.WaitForClusterOperators(ctx, ocConfig, client.monitoringEnabled(), startConfig.WaitOperators, timeout, 5*time.Second); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
 		}
-
-		if err := cluster.DeleteOpenshiftAPIServerPods(ocConfig); err != nil {
-			return nil, errors.Wrap(err, "Cannot delete OpenShift API Server pods")
-		}
-	}
-
-	logging.Info("Starting OpenShift cluster ... [waiting 3m]")
-
-	time.Sleep(time.Minute * 3)
-
-	waitForProxyPropagation(ocConfig, proxyConfig)
-
-	logging.Info("Updating kubeconfig")
-	if err := eventuallyWriteKubeconfig(ocConfig, instanceIP, clusterConfig); err != nil {
-		logging.Warnf("Cannot update kubeconfig: %v", err)
+		logging.Warnf("The cluster operators are still not ready after %s: %v", timeout, err)
+		return nil
 	}
-
-	logging.Warn("The cluster might report a degraded or error state. This is expected since several operators have been disabled to lower the resource usage. For more information, please consult the documentation")
-	return &StartResult{
-		KubeletStarted: true,
-		ClusterConfig:  *clusterConfig,
-		Status:         vmState,
-	}, nil
+	logging.Info("All the cluster operators are ready")
+	return nil
 }
 
-func (client *client) IsRunning() (bool, error) {
+func (client *client) IsRunning(ctx context.Context) (bool, error) {
 	libMachineAPIClient, cleanup := createLibMachineClient()
 	defer cleanup()
 	host, err := libMachineAPIClient.Load(client.name)
@@ -406,11 +157,21 @@ func (client *client) IsRunning() (bool, error) {
 }
 
 func (client *client) validateStartConfig(startConfig StartConfig) error {
+	if startConfig.FromStep != "" {
+		if err := validateStartStep(startConfig.FromStep); err != nil {
+			return err
+		}
+	}
 	if client.monitoringEnabled() && startConfig.Memory < minimumMemoryForMonitoring {
 		return fmt.Errorf("Too little memory (%s) allocated to the virtual machine to start the monitoring stack, %s is the minimum",
 			units.BytesSize(float64(startConfig.Memory)*1024*1024),
 			units.BytesSize(minimumMemoryForMonitoring*1024*1024))
 	}
+	if client.useVSock() {
+		if err := client.virtualNetwork().Validate(); err != nil {
+			return errors.Wrap(err, "Invalid virtual network configuration")
+		}
+	}
 	return nil
 }
 
@@ -424,19 +185,19 @@ func makeDaemonVisibleToHyperkit(name string) error {
 		if !os.IsNotExist(err) {
 			return errors.Wrap(err, "VSock listener error")
 		}
-		if err := os.Symlink(constants.NetworkSocketPath, dst); err != nil {
+		if err := os.Symlink(profile.NetworkSocketPath(name), dst); err != nil {
 			return errors.Wrap(err, "VSock listener error")
 		}
 	}
 	return nil
 }
 
-func createHost(api libmachine.API, machineConfig config.MachineConfig) (*host.Host, error) {
+func createHost(ctx context.Context, api libmachine.API, machineConfig config.MachineConfig) (*host.Host, error) {
 	vm, err := newHost(api, machineConfig)
 	if err != nil {
 		return nil, fmt.Errorf("Error creating new host: %s", err)
 	}
-	if err := api.Create(vm); err != nil {
+	if err := api.Create(ctx, vm); err != nil {
 		return nil, fmt.Errorf("Error creating the VM: %s", err)
 	}
 	return vm, nil
@@ -456,21 +217,21 @@ func addNameServerToInstance(sshRunner *crcssh.Runner, ns string) error {
 	return nil
 }
 
-func updateSSHKeyPair(sshRunner *crcssh.Runner) error {
-	if _, err := os.Stat(constants.GetPrivateKeyPath()); err != nil {
+func updateSSHKeyPair(sshRunner *crcssh.Runner, name string) error {
+	if _, err := os.Stat(constants.GetPrivateKeyPath(name)); err != nil {
 		if !os.IsNotExist(err) {
 			return err
 		}
 
 		// Generate ssh key pair
 		logging.Info("Generating new SSH Key pair ...")
-		if err := ssh.GenerateSSHKey(constants.GetPrivateKeyPath()); err != nil {
+		if err := ssh.GenerateSSHKey(constants.GetPrivateKeyPath(name)); err != nil {
 			return fmt.Errorf("Error generating ssh key pair: %v", err)
 		}
 	}
 
 	// Read generated public key
-	publicKey, err := ioutil.ReadFile(constants.GetPublicKeyPath())
+	publicKey, err := ioutil.ReadFile(constants.GetPublicKeyPath(name))
 	if err != nil {
 		return err
 	}
@@ -490,7 +251,7 @@ func updateSSHKeyPair(sshRunner *crcssh.Runner) error {
 }
 
 func updateSSHKeyAndCopyKubeconfig(sshRunner *crcssh.Runner, name string, crcBundleMetadata *bundle.CrcBundleInfo) error {
-	if err := updateSSHKeyPair(sshRunner); err != nil {
+	if err := updateSSHKeyPair(sshRunner, name); err != nil {
 		return fmt.Errorf("Error updating SSH Keys: %v", err)
 	}
 
@@ -519,15 +280,22 @@ func ensureKubeletAndCRIOAreConfiguredForProxy(sshRunner *crcssh.Runner, proxy *
 	return cluster.AddProxyToKubeletAndCriO(sshRunner, proxy)
 }
 
-func ensureProxyIsConfiguredInOpenShift(ocConfig oc.Config, sshRunner *crcssh.Runner, proxy *network.ProxyConfig, instanceIP string) (err error) {
+func ensureProxyIsConfiguredInOpenShift(ctx context.Context, ocConfig oc.Config, proxy *network.ProxyConfig, instanceIP string) (err error) {
 	if !proxy.IsEnabled() {
 		return nil
 	}
 	logging.Info("Adding proxy configuration to the cluster ...")
-	return cluster.AddProxyConfigToCluster(sshRunner, ocConfig, proxy)
+	return cluster.AddProxyConfigToCluster(ctx, ocConfig, proxy)
 }
 
-func waitForProxyPropagation(ocConfig oc.Config, proxyConfig *network.ProxyConfig) {
+func ensureRegistriesAreConfiguredInOpenShift(ctx context.Context, ocConfig oc.Config, sshRunner *crcssh.Runner, registries *cluster.RegistriesConfig) error {
+	if len(registries.Mirrors) > 0 || len(registries.Insecure) > 0 {
+		logging.Info("Adding registries configuration to the cluster ...")
+	}
+	return cluster.EnsureRegistriesInCluster(ctx, sshRunner, ocConfig, registries)
+}
+
+func waitForProxyPropagation(ctx context.Context, ocConfig oc.Config, proxyConfig *network.ProxyConfig) {
 	if !proxyConfig.IsEnabled() {
 		return
 	}
@@ -545,17 +313,7 @@ func waitForProxyPropagation(ocConfig oc.Config, proxyConfig *network.ProxyConfi
 		return nil
 	}
 
-	if err := crcerrors.RetryAfter(300*time.Second, checkProxySettingsForOperator, 2*time.Second); err != nil {
+	if err := crcerrors.RetryAfter(ctx, 300*time.Second, checkProxySettingsForOperator, 2*time.Second); err != nil {
 		logging.Debug("Failed to propagate proxy settings to cluster")
 	}
 }
-
-func logBundleDate(crcBundleMetadata *bundle.CrcBundleInfo) {
-	if buildTime, err := crcBundleMetadata.GetBundleBuildTime(); err == nil {
-		bundleAgeDays := time.Since(buildTime).Hours() / 24
-		if bundleAgeDays >= 30 {
-			/* Initial bundle certificates are only valid for 30 days */
-			logging.Debugf("Bundle has been generated %d days ago", int(bundleAgeDays))
-		}
-	}
-}
@@ -0,0 +1,697 @@
+package machine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/code-ready/crc/pkg/crc/cluster"
+	"github.com/code-ready/crc/pkg/crc/constants"
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/code-ready/crc/pkg/crc/machine/bundle"
+	"github.com/code-ready/crc/pkg/crc/machine/config"
+	"github.com/code-ready/crc/pkg/crc/network"
+	"github.com/code-ready/crc/pkg/crc/oc"
+	"github.com/code-ready/crc/pkg/crc/services"
+	"github.com/code-ready/crc/pkg/crc/services/dns"
+	crcssh "github.com/code-ready/crc/pkg/crc/ssh"
+	"github.com/code-ready/crc/pkg/crc/systemd"
+	"github.com/code-ready/crc/pkg/libmachine"
+	"github.com/code-ready/crc/pkg/libmachine/host"
+	"github.com/code-ready/machine/libmachine/state"
+	"github.com/pkg/errors"
+)
+
+// startStep is one of the steps run by Start. Steps must be idempotent:
+// a step which failed, or which is requested with StartConfig.FromStep, is
+// run again on the next start.
+type startStep struct {
+	name string
+	// always is set for the steps bringing up the VM and gathering the state
+	// needed by the next steps. They run on every start and their completion
+	// is not recorded.
+	always bool
+	run    func(client *client, ctx context.Context, state *startState) error
+}
+
+var startSteps = []startStep{
+	{name: StepLoadBundle, always: true, run: (*client).loadBundle},
+	{name: StepStartVM, always: true, run: (*client).startVM},
+	{name: StepWaitForSSH, always: true, run: (*client).waitForSSH},
+	{name: StepConfigureVM, run: (*client).configureVM},
+	{name: StepCheckDNS, run: (*client).checkDNS},
+	{name: StepMountDirectories, run: (*client).mountDirectories},
+	{name: StepCheckCerts, always: true, run: (*client).checkCerts},
+	{name: StepStartKubelet, run: (*client).startKubelet},
+	{name: StepRenewCerts, run: (*client).renewCerts},
+	{name: StepWaitForAPIServer, run: (*client).waitForAPIServer},
+	{name: StepConfigureCluster, run: (*client).configureCluster},
+	{name: StepWaitForCluster, run: (*client).waitForClusterStep},
+	{name: StepUpdateKubeconfig, run: (*client).updateKubeconfig},
+}
+
+// StartSteps returns the names of the steps run by Start, in order
+func StartSteps() []string {
+	var names []string
+	for _, step := range startSteps {
+		names = append(names, step.name)
+	}
+	return names
+}
+
+func validateStartStep(name string) error {
+	for _, step := range startSteps {
+		if step.name == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("Unknown start step '%s', valid steps are: %s", name, strings.Join(StartSteps(), ", "))
+}
+
+// startState is shared by the start steps
+type startState struct {
+	startConfig StartConfig
+	api         libmachine.API
+
+	exists     bool
+	wasRunning bool
+
+	host              *host.Host
+	crcBundleMetadata *bundle.CrcBundleInfo
+	clusterConfig     *ClusterConfig
+	vmState           state.State
+	instanceIP        string
+	sshRunner         *crcssh.Runner
+	ocConfig          oc.Config
+	proxyConfig       *network.ProxyConfig
+	certsExpired      map[string]bool
+}
+
+func (client *client) start(ctx context.Context, startConfig StartConfig) (*StartResult, error) {
+	if err := client.validateStartConfig(startConfig); err != nil {
+		return nil, err
+	}
+
+	libMachineAPIClient, cleanup := createLibMachineClient()
+	defer cleanup()
+
+	s := &startState{
+		startConfig: startConfig,
+		api:         libMachineAPIClient,
+	}
+	defer func() {
+		if s.sshRunner != nil {
+			s.sshRunner.Close()
+		}
+	}()
+
+	exists, err := client.Exists(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot determine if VM exists")
+	}
+	s.exists = exists
+	if exists {
+		// fail before starting the VM when the recorded mounts cannot be set up
+		mounts, err := libMachineAPIClient.LoadMounts(client.name)
+		if err != nil {
+			return nil, errors.Wrap(err, "Cannot load mounts")
+		}
+		if len(mounts) > 0 {
+			if err := sharedDirectoriesSupported(); err != nil {
+				return nil, errors.Wrap(err, "Cannot share the directories added with 'crc mount add'")
+			}
+		}
+	}
+	if !exists {
+		// Ask early for pull secret if it hasn't been requested yet, outside
+		// of the steps so that the time spent by the user is not measured
+		if _, err := startConfig.PullSecret.Value(); err != nil {
+			return nil, errors.Wrap(err, "Failed to ask for pull secret")
+		}
+	}
+
+	var checkpoint *startCheckpoint
+	var timings []StepTiming
+	// Until the first step which runs, the completed steps are skipped
+	resuming := true
+	fromStepReached := false
+	for index, step := range startSteps {
+		if step.name == startConfig.FromStep {
+			fromStepReached = true
+		}
+		if !step.always {
+			if checkpoint == nil {
+				checkpoint, err = client.loadStartCheckpoint(s.sshRunner, s.wasRunning)
+				if err != nil {
+					return nil, err
+				}
+				if s.wasRunning && startConfig.FromStep == "" && checkpoint.isComplete() {
+					logging.Infof("A CodeReady Containers VM for OpenShift %s is already running", s.crcBundleMetadata.GetOpenshiftVersion())
+					startConfig.reportProgress(StepDone, 100, "VM already running")
+					return &StartResult{
+						Status:         s.vmState,
+						ClusterConfig:  *s.clusterConfig,
+						KubeletStarted: true,
+						Timings:        timings,
+					}, nil
+				}
+			}
+			skip := false
+			if startConfig.FromStep != "" {
+				skip = !fromStepReached
+			} else if resuming {
+				skip = checkpoint.isCompleted(step.name)
+			}
+			if skip {
+				logging.Debugf("Skipping start step %s, it is already completed", step.name)
+				continue
+			}
+			resuming = false
+			// This step and the next ones must be run again
+			checkpoint.truncate(index)
+			if err := client.saveStartCheckpoint(checkpoint); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		startTime := time.Now()
+		if err := step.run(client, ctx, s); err != nil {
+			logging.Debugf("Start step %s failed after %s", step.name, time.Since(startTime))
+			return nil, err
+		}
+		duration := time.Since(startTime)
+		logging.Debugf("Start step %s took %s", step.name, duration)
+		timings = append(timings, StepTiming{Step: step.name, Duration: duration})
+
+		if !step.always {
+			checkpoint.complete(step.name, duration)
+			if err := client.saveStartCheckpoint(checkpoint); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	logging.Warn("The cluster might report a degraded or error state. This is expected since several operators have been disabled to lower the resource usage. For more information, please consult the documentation")
+	startConfig.reportProgress(StepDone, 100, "Started the OpenShift cluster")
+	return &StartResult{
+		KubeletStarted: true,
+		ClusterConfig:  *s.clusterConfig,
+		Status:         s.vmState,
+		Timings:        timings,
+	}, nil
+}
+
+func (client *client) loadBundle(ctx context.Context, s *startState) error {
+	var err error
+	if s.exists {
+		s.host, err = s.api.Load(client.name)
+		if err != nil {
+			return errors.Wrap(err, "Error loading machine")
+		}
+
+		var bundleName string
+		bundleName, s.crcBundleMetadata, err = getBundleMetadataFromDriver(s.host.Driver)
+		if err != nil {
+			return errors.Wrap(err, "Error loading bundle metadata")
+		}
+		if bundleName != filepath.Base(s.startConfig.BundlePath) {
+			logging.Debugf("Bundle '%s' was requested, but the existing VM is using '%s'",
+				filepath.Base(s.startConfig.BundlePath), bundleName)
+			return fmt.Errorf("Bundle '%s' was requested, but the existing VM is using '%s'",
+				filepath.Base(s.startConfig.BundlePath),
+				bundleName)
+		}
+	} else {
+		s.startConfig.reportProgress(StepLoadBundle, 0, "Loading bundle")
+		s.crcBundleMetadata, err = getCrcBundleInfo(s.startConfig.BundlePath)
+		if err != nil {
+			return errors.Wrap(err, "Error getting bundle metadata")
+		}
+	}
+
+	if expiry := getBundleExpiry(s.crcBundleMetadata, time.Now()); expiry != nil && expiry.Warning != "" {
+		logging.Warn(expiry.Warning)
+	}
+	return nil
+}
+
+func (client *client) startVM(ctx context.Context, s *startState) error {
+	if !s.exists {
+		logging.Infof("Creating CodeReady Containers VM for OpenShift %s...", s.crcBundleMetadata.GetOpenshiftVersion())
+		s.startConfig.reportProgress(StepCreateVM, 10, fmt.Sprintf("Creating VM for OpenShift %s", s.crcBundleMetadata.GetOpenshiftVersion()))
+
+		machineConfig := config.MachineConfig{
+			Name:        client.name,
+			BundleName:  filepath.Base(s.startConfig.BundlePath),
+			CPUs:        s.startConfig.CPUs,
+			Memory:      s.startConfig.Memory,
+			DiskSize:    s.startConfig.DiskSize,
+			NetworkMode: client.networkMode(),
+
+			// Retrieve metadata info
+			ImageSourcePath: s.crcBundleMetadata.GetDiskImagePath(),
+			ImageFormat:     s.crcBundleMetadata.Storage.DiskImages[0].Format,
+			SSHKeyPath:      s.crcBundleMetadata.GetSSHKeyPath(),
+			KernelCmdLine:   s.crcBundleMetadata.Nodes[0].KernelCmdLine,
+			Initramfs:       s.crcBundleMetadata.GetInitramfsPath(),
+			Kernel:          s.crcBundleMetadata.GetKernelPath(),
+		}
+
+		var err error
+		s.host, err = createHost(ctx, s.api, machineConfig)
+		if err != nil {
+			return errors.Wrap(err, "Error creating machine")
+		}
+		return nil
+	}
+
+	vmState, err := s.host.Driver.GetState()
+	if err != nil {
+		return errors.Wrap(err, "Error getting the machine state")
+	}
+	if vmState == state.Running {
+		s.wasRunning = true
+		return nil
+	}
+
+	logging.Infof("Starting CodeReady Containers VM for OpenShift %s...", s.crcBundleMetadata.GetOpenshiftVersion())
+	s.startConfig.reportProgress(StepStartVM, 10, fmt.Sprintf("Starting VM for OpenShift %s", s.crcBundleMetadata.GetOpenshiftVersion()))
+
+	// The memory of a paused VM was saved with its configuration, changing it would prevent restoring it
+	if paused, err := isPaused(s.host); err == nil && paused {
+		logging.Warn("The OpenShift cluster was paused, memory, CPU and disk size changes will only be applied after 'crc stop'")
+	} else {
+		if err := client.updateVMConfig(s.startConfig, s.api, s.host); err != nil {
+			return errors.Wrap(err, "Could not update CRC VM configuration")
+		}
+		if err := configureSharedDirectories(s.api, s.host); err != nil {
+			return errors.Wrap(err, "Could not share directories with the CRC VM")
+		}
+	}
+
+	if err := s.host.Driver.Start(); err != nil {
+		return errors.Wrap(err, "Error starting stopped VM")
+	}
+	return nil
+}
+
+func (client *client) waitForSSH(ctx context.Context, s *startState) error {
+	if runtime.GOOS == "darwin" && client.useVSock() {
+		if err := makeDaemonVisibleToHyperkit(client.name); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	s.clusterConfig, err = getClusterConfig(s.crcBundleMetadata)
+	if err != nil {
+		return errors.Wrap(err, "Cannot create cluster configuration")
+	}
+
+	s.vmState, err = s.host.Driver.GetState()
+	if err != nil {
+		return errors.Wrap(err, "Error getting the state")
+	}
+	if s.vmState != state.Running {
+		return errors.New("CodeReady Containers VM is not running")
+	}
+
+	s.instanceIP, err = getIP(s.host, client.useVSock())
+	if err != nil {
+		return errors.Wrap(err, "Error getting the IP")
+	}
+	s.sshRunner, err = crcssh.CreateRunner(s.instanceIP, getSSHPort(client.useVSock()), s.crcBundleMetadata.GetSSHKeyPath(), constants.GetPrivateKeyPath(client.name), constants.GetRsaPrivateKeyPath(client.name))
+	if err != nil {
+		return errors.Wrap(err, "Error creating the ssh client")
+	}
+	s.ocConfig = oc.UseOCWithSSH(s.sshRunner)
+
+	logging.Debug("Waiting until ssh is available")
+	s.startConfig.reportProgress(StepWaitForSSH, 20, "Waiting for SSH")
+	if err := cluster.WaitForSSH(ctx, s.sshRunner); err != nil {
+		return errors.Wrap(err, "Failed to connect to the CRC VM with SSH -- host might be unreachable")
+	}
+	logging.Info("CodeReady Containers VM is running")
+
+	s.proxyConfig, err = getProxyConfig(s.crcBundleMetadata.ClusterInfo.BaseDomain)
+	if err != nil {
+		return errors.Wrap(err, "Error getting proxy configuration")
+	}
+	s.proxyConfig.ApplyToEnvironment()
+	s.proxyConfig.AddNoProxy(s.instanceIP)
+	return nil
+}
+
+func (client *client) configureVM(ctx context.Context, s *startState) error {
+	s.startConfig.reportProgress(StepConfigureVM, 30, "Configuring VM")
+
+	// Post VM start immediately update SSH key and copy kubeconfig to instance
+	// dir and VM
+	if err := updateSSHKeyAndCopyKubeconfig(s.sshRunner, client.name, s.crcBundleMetadata); err != nil {
+		return errors.Wrap(err, "Error updating public key")
+	}
+
+	// Trigger disk resize, this will be a no-op if no disk size change is needed
+	if _, _, err := s.sshRunner.Run("sudo xfs_growfs / >/dev/null"); err != nil {
+		return errors.Wrap(err, "Error updating filesystem size")
+	}
+
+	// Start network time synchronization if `CRC_DEBUG_ENABLE_STOP_NTP` is not set
+	if stopNtp, _ := strconv.ParseBool(os.Getenv("CRC_DEBUG_ENABLE_STOP_NTP")); !stopNtp {
+		logging.Info("Starting network time synchronization in CodeReady Containers VM")
+		if _, _, err := s.sshRunner.Run("sudo timedatectl set-ntp on"); err != nil {
+			return errors.Wrap(err, "Failed to start network time synchronization")
+		}
+	}
+
+	// Add nameserver to VM if provided by User
+	if s.startConfig.NameServer != "" {
+		if err := addNameServerToInstance(s.sshRunner, s.startConfig.NameServer); err != nil {
+			return errors.Wrap(err, "Failed to add nameserver to the VM")
+		}
+	}
+	return nil
+}
+
+func (client *client) checkDNS(ctx context.Context, s *startState) error {
+	// Create servicePostStartConfig for DNS checks and DNS start.
+	servicePostStartConfig := services.ServicePostStartConfig{
+		Name: client.name,
+		// TODO: would prefer passing in a more generic type
+		SSHRunner: s.sshRunner,
+		IP:        s.instanceIP,
+		// TODO: should be more finegrained
+		BundleMetadata: *s.crcBundleMetadata,
+		NetworkMode:    client.networkMode(),
+		GatewayIP:      client.virtualNetwork().GatewayIP,
+	}
+
+	s.startConfig.reportProgress(StepCheckDNS, 35, "Checking DNS")
+	// Run the DNS server inside the VM
+	if err := dns.RunPostStart(ctx, servicePostStartConfig); err != nil {
+		return errors.Wrap(err, "Error running post start")
+	}
+
+	// Check DNS lookup before starting the kubelet
+	if queryOutput, err := dns.CheckCRCLocalDNSReachable(ctx, servicePostStartConfig); err != nil {
+		if !client.useVSock() {
+			return errors.Wrapf(err, "Failed internal DNS query: %s", queryOutput)
+		}
+		logging.Warn(fmt.Sprintf("Failed internal DNS query: %s: %v", queryOutput, err))
+	}
+	logging.Info("Check internal and public DNS query ...")
+
+	if queryOutput, err := dns.CheckCRCPublicDNSReachable(servicePostStartConfig); err != nil {
+		logging.Warnf("Failed public DNS query from the cluster: %v : %s", err, queryOutput)
+	}
+
+	// Check DNS lookup from host to VM
+	logging.Info("Check DNS query from host ...")
+	if err := network.CheckCRCLocalDNSReachableFromHost(s.crcBundleMetadata, s.instanceIP); err != nil {
+		if !client.useVSock() {
+			return errors.Wrap(err, "Failed to query DNS from host")
+		}
+		logging.Warn(fmt.Sprintf("Failed to query DNS from host: %v", err))
+	}
+	return nil
+}
+
+func (client *client) mountDirectories(ctx context.Context, s *startState) error {
+	s.startConfig.reportProgress(StepMountDirectories, 40, "Mounting shared directories")
+	return mountSharedDirectories(s.api, client.name, s.sshRunner)
+}
+
+func (client *client) checkCerts(ctx context.Context, s *startState) error {
+	// Check the certs validity inside the vm
+	logging.Info("Verifying validity of the kubelet certificates ...")
+	s.startConfig.reportProgress(StepCheckCerts, 45, "Verifying validity of the kubelet certificates")
+	var err error
+	s.certsExpired, err = cluster.CheckCertsValidity(s.sshRunner)
+	if err != nil {
+		return errors.Wrap(err, "Failed to check certificate validity")
+	}
+	return nil
+}
+
+func (client *client) startKubelet(ctx context.Context, s *startState) error {
+	if err := cluster.EnsurePullSecretPresentOnInstanceDisk(s.sshRunner, s.startConfig.PullSecret); err != nil {
+		return errors.Wrap(err, "Failed to update VM pull secret")
+	}
+
+	if err := ensureKubeletAndCRIOAreConfiguredForProxy(s.sshRunner, s.proxyConfig, s.instanceIP); err != nil {
+		return errors.Wrap(err, "Failed to update proxy configuration of kubelet and crio")
+	}
+
+	sd := systemd.NewInstanceSystemdCommander(s.sshRunner)
+	trustedCABundle, err := client.trustedCABundle(s.proxyConfig)
+	if err != nil {
+		return err
+	}
+	trustedCAsChanged, err := cluster.EnsureTrustedCABundleOnInstance(s.sshRunner, trustedCABundle)
+	if err != nil {
+		return errors.Wrap(err, "Failed to update the trusted CAs of the VM")
+	}
+	registries, err := client.registriesConfig()
+	if err != nil {
+		return err
+	}
+	registriesChanged, err := cluster.EnsureRegistriesOnInstance(s.sshRunner, registries)
+	if err != nil {
+		return errors.Wrap(err, "Failed to update the registries configuration of crio")
+	}
+	// crio only reads the trust store and the registries configuration when it starts
+	if trustedCAsChanged || registriesChanged {
+		if err := sd.Restart("crio"); err != nil {
+			return errors.Wrap(err, "Error restarting crio")
+		}
+	}
+
+	logging.Info("Starting OpenShift kubelet service")
+	s.startConfig.reportProgress(StepStartKubelet, 50, "Starting kubelet")
+	if err := sd.Start("kubelet"); err != nil {
+		return errors.Wrap(err, "Error starting kubelet")
+	}
+	return nil
+}
+
+func (client *client) renewCerts(ctx context.Context, s *startState) error {
+	s.startConfig.reportProgress(StepRenewCerts, 55, "Renewing expired certificates")
+	if err := cluster.ApproveCSRAndWaitForCertsRenewal(ctx, s.sshRunner, s.ocConfig, s.certsExpired[cluster.KubeletClientCert], s.certsExpired[cluster.KubeletServerCert]); err != nil {
+		return errors.Wrap(err, "Failed to renew TLS certificates: please check if a newer CodeReady Containers release is available")
+	}
+	return nil
+}
+
+func (client *client) waitForAPIServer(ctx context.Context, s *startState) error {
+	s.startConfig.reportProgress(StepWaitForAPIServer, 60, "Waiting for the API server")
+	if err := cluster.WaitForAPIServer(ctx, s.ocConfig); err != nil {
+		return errors.Wrap(err, "Error waiting for apiserver")
+	}
+	return nil
+}
+
+func (client *client) configureCluster(ctx context.Context, s *startState) error {
+	s.startConfig.reportProgress(StepConfigureCluster, 65, "Configuring the cluster")
+	if err := ensureProxyIsConfiguredInOpenShift(ctx, s.ocConfig, s.proxyConfig, s.instanceIP); err != nil {
+		return errors.Wrap(err, "Failed to update cluster proxy configuration")
+	}
+
+	trustedCABundle, err := client.trustedCABundle(s.proxyConfig)
+	if err != nil {
+		return err
+	}
+	trustedCARegistries, err := client.trustedCARegistries()
+	if err != nil {
+		return err
+	}
+	if err := cluster.EnsureTrustedCABundleInCluster(ctx, s.sshRunner, s.ocConfig, trustedCABundle, trustedCARegistries); err != nil {
+		return errors.Wrap(err, "Failed to update the trusted CAs of the cluster")
+	}
+
+	registries, err := client.registriesConfig()
+	if err != nil {
+		return err
+	}
+	if err := ensureRegistriesAreConfiguredInOpenShift(ctx, s.ocConfig, s.sshRunner, registries); err != nil {
+		return errors.Wrap(err, "Failed to update the registries configuration of the cluster")
+	}
+
+	if err := cluster.EnsurePullSecretPresentInTheCluster(ctx, s.ocConfig, s.startConfig.PullSecret); err != nil {
+		return errors.Wrap(err, "Failed to update cluster pull secret")
+	}
+
+	if err := cluster.EnsureClusterIDIsNotEmpty(ctx, s.ocConfig); err != nil {
+		return errors.Wrap(err, "Failed to update cluster ID")
+	}
+
+	if client.monitoringEnabled() {
+		logging.Info("Enabling cluster monitoring operator...")
+		if err := cluster.StartMonitoring(s.ocConfig); err != nil {
+			return errors.Wrap(err, "Cannot start monitoring stack")
+		}
+	}
+
+	// In Openshift 4.3, when cluster comes up, the following happens
+	// 1. After the openshift-apiserver pod is started, its log contains multiple occurrences of `certificate has expired or is not yet valid`
+	// 2. Initially there is no request-header's client-ca crt available to `extension-apiserver-authentication` configmap
+	// 3. In the pod logs `missing content for CA bundle "client-ca::kube-system::extension-apiserver-authentication::requestheader-client-ca-file"`
+	// 4. After ~1 min /etc/kubernetes/static-pod-resources/kube-apiserver-certs/configmaps/aggregator-client-ca/ca-bundle.crt is regenerated
+	// 5. It is now also appear to `extension-apiserver-authentication` configmap as part of request-header's client-ca content
+	// 6. Openshift-apiserver is able to load the CA which was regenerated
+	// 7. Now apiserver pod log contains multiple occurrences of `error x509: certificate signed by unknown authority`
+	// When the openshift-apiserver is in this state, the cluster is non functional.
+	// A restart of the openshift-apiserver pod is enough to clear that error and get a working cluster.
+	// This is a work-around while the root cause is being identified.
+	// More info: https://bugzilla.redhat.com/show_bug.cgi?id=1795163
+	if s.certsExpired[cluster.AggregatorClientCert] {
+		logging.Debug("Waiting for the renewal of the request header client ca...")
+		if err := cluster.WaitForRequestHeaderClientCaFile(ctx, s.sshRunner); err != nil {
+			return errors.Wrap(err, "Failed to wait for aggregator client ca renewal")
+		}
+
+		if err := cluster.DeleteOpenshiftAPIServerPods(ctx, s.ocConfig); err != nil {
+			return errors.Wrap(err, "Cannot delete OpenShift API Server pods")
+		}
+	}
+	return nil
+}
+
+func (client *client) waitForClusterStep(ctx context.Context, s *startState) error {
+	s.startConfig.reportProgress(StepWaitForCluster, 70, "Waiting for the cluster to start")
+	if err := client.waitForCluster(ctx, s.ocConfig, s.startConfig); err != nil {
+		return err
+	}
+
+	waitForProxyPropagation(ctx, s.ocConfig, s.proxyConfig)
+	return nil
+}
+
+func (client *client) updateKubeconfig(ctx context.Context, s *startState) error {
+	logging.Info("Updating kubeconfig")
+	s.startConfig.reportProgress(StepUpdateKubeconfig, 95, "Updating kubeconfig")
+	if err := eventuallyWriteKubeconfig(ctx, client.name, s.ocConfig, s.instanceIP, s.clusterConfig); err != nil {
+		logging.Warnf("Cannot update kubeconfig: %v", err)
+	}
+	return nil
+}
+
+// startCheckpoint records the start steps completed since the VM booted
+type startCheckpoint struct {
+	BootID    string          `json:"bootID"`
+	Completed []completedStep `json:"completed"`
+}
+
+type completedStep struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+}
+
+func startCheckpointPath(name string) string {
+	return filepath.Join(constants.MachineInstanceDir, name, "start-steps.json")
+}
+
+// loadStartCheckpoint returns the steps completed during the current boot of
+// the VM. When the VM was started by a version of crc which did not record
+// them, all the steps are considered completed.
+func (client *client) loadStartCheckpoint(sshRunner *crcssh.Runner, wasRunning bool) (*startCheckpoint, error) {
+	bootID, _, err := sshRunner.Run("cat /proc/sys/kernel/random/boot_id")
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot get the boot ID of the VM")
+	}
+	checkpoint, err := readStartCheckpoint(startCheckpointPath(client.name), strings.TrimSpace(bootID), wasRunning)
+	if err != nil {
+		return nil, err
+	}
+	return checkpoint, client.saveStartCheckpoint(checkpoint)
+}
+
+func readStartCheckpoint(path, bootID string, wasRunning bool) (*startCheckpoint, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		checkpoint := &startCheckpoint{BootID: bootID}
+		if wasRunning {
+			for _, step := range startSteps {
+				if !step.always {
+					checkpoint.complete(step.name, 0)
+				}
+			}
+		}
+		return checkpoint, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot read the completed start steps")
+	}
+	var checkpoint startCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		logging.Debugf("Ignoring invalid %s: %v", path, err)
+		return &startCheckpoint{BootID: bootID}, nil
+	}
+	if checkpoint.BootID != bootID {
+		logging.Debugf("The VM was restarted since the last start, running all the start steps")
+		return &startCheckpoint{BootID: bootID}, nil
+	}
+	return &checkpoint, nil
+}
+
+func (client *client) saveStartCheckpoint(checkpoint *startCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(startCheckpointPath(client.name), data, 0600); err != nil {
+		return errors.Wrap(err, "Cannot record the completed start steps")
+	}
+	return nil
+}
+
+func (checkpoint *startCheckpoint) isCompleted(name string) bool {
+	for _, step := range checkpoint.Completed {
+		if step.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (checkpoint *startCheckpoint) isComplete() bool {
+	for _, step := range startSteps {
+		if !step.always && !checkpoint.isCompleted(step.name) {
+			return false
+		}
+	}
+	return true
+}
+
+func (checkpoint *startCheckpoint) complete(name string, duration time.Duration) {
+	checkpoint.Completed = append(checkpoint.Completed, completedStep{Name: name, Duration: duration})
+}
+
+// truncate forgets about the completion of startSteps[index] and of the steps after it
+func (checkpoint *startCheckpoint) truncate(index int) {
+	var completed []completedStep
+	for _, step := range checkpoint.Completed {
+		if stepIndex(step.Name) < index {
+			completed = append(completed, step)
+		}
+	}
+	checkpoint.Completed = completed
+}
+
+func stepIndex(name string) int {
+	for index, step := range startSteps {
+		if step.name == name {
+			return index
+		}
+	}
+	return len(startSteps)
+}
@@ -0,0 +1,59 @@
+package machine
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateStartStep(t *testing.T) {
+	assert.NoError(t, validateStartStep(StepConfigureCluster))
+	assert.NoError(t, validateStartStep(StepLoadBundle))
+	assert.Error(t, validateStartStep(StepDone))
+	assert.Error(t, validateStartStep("unknown"))
+}
+
+func TestReadStartCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "start-steps.json")
+
+	checkpoint, err := readStartCheckpoint(path, "boot1", false)
+	assert.NoError(t, err)
+	assert.Equal(t, &startCheckpoint{BootID: "boot1"}, checkpoint)
+
+	// A running VM without recorded steps was started by a previous version
+	checkpoint, err = readStartCheckpoint(path, "boot1", true)
+	assert.NoError(t, err)
+	assert.True(t, checkpoint.isComplete())
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"bootID":"boot1","completed":[{"name":"configure-vm","duration":1000}]}`), 0600))
+	checkpoint, err = readStartCheckpoint(path, "boot1", true)
+	assert.NoError(t, err)
+	assert.True(t, checkpoint.isCompleted(StepConfigureVM))
+	assert.False(t, checkpoint.isCompleted(StepCheckDNS))
+	assert.False(t, checkpoint.isComplete())
+
+	// The completed steps are forgotten when the VM reboots
+	checkpoint, err = readStartCheckpoint(path, "boot2", true)
+	assert.NoError(t, err)
+	assert.Equal(t, &startCheckpoint{BootID: "boot2"}, checkpoint)
+}
+
+func TestTruncateStartCheckpoint(t *testing.T) {
+	checkpoint := &startCheckpoint{BootID: "boot1"}
+	checkpoint.complete(StepConfigureVM, time.Second)
+	checkpoint.complete(StepCheckDNS, time.Second)
+	checkpoint.complete(StepStartKubelet, time.Second)
+
+	checkpoint.truncate(stepIndex(StepCheckDNS))
+	assert.True(t, checkpoint.isCompleted(StepConfigureVM))
+	assert.False(t, checkpoint.isCompleted(StepCheckDNS))
+	assert.False(t, checkpoint.isCompleted(StepStartKubelet))
+}
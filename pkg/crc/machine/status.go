@@ -1,8 +1,10 @@
 package machine
 
 import (
+	"context"
+	"time"
+
 	"github.com/code-ready/crc/pkg/crc/cluster"
-	"github.com/code-ready/crc/pkg/crc/constants"
 	"github.com/code-ready/crc/pkg/crc/logging"
 	"github.com/code-ready/crc/pkg/crc/oc"
 	crcssh "github.com/code-ready/crc/pkg/crc/ssh"
@@ -10,7 +12,7 @@ import (
 	"github.com/pkg/errors"
 )
 
-func (client *client) Status() (*ClusterStatusResult, error) {
+func (client *client) Status(ctx context.Context) (*ClusterStatusResult, error) {
 	libMachineAPIClient, cleanup := createLibMachineClient()
 	defer cleanup()
 
@@ -28,16 +30,30 @@ func (client *client) Status() (*ClusterStatusResult, error) {
 		return nil, errors.Wrap(err, "Cannot get machine state")
 	}
 
+	_, crcBundleMetadata, bundleErr := getBundleMetadataFromDriver(host.Driver)
+	var bundleExpiry *BundleExpiry
+	if bundleErr == nil {
+		bundleExpiry = getBundleExpiry(crcBundleMetadata, time.Now())
+	}
+
+	if paused, err := isPaused(host); err == nil && paused {
+		return &ClusterStatusResult{
+			CrcStatus:       state.Paused,
+			OpenshiftStatus: "Paused",
+			BundleExpiry:    bundleExpiry,
+		}, nil
+	}
+
 	if vmStatus != state.Running {
 		return &ClusterStatusResult{
 			CrcStatus:       vmStatus,
 			OpenshiftStatus: "Stopped",
+			BundleExpiry:    bundleExpiry,
 		}, nil
 	}
 
-	_, crcBundleMetadata, err := getBundleMetadataFromDriver(host.Driver)
-	if err != nil {
-		return nil, errors.Wrap(err, "Error loading bundle metadata")
+	if bundleErr != nil {
+		return nil, errors.Wrap(bundleErr, "Error loading bundle metadata")
 	}
 	proxyConfig, err := getProxyConfig(crcBundleMetadata.ClusterInfo.BaseDomain)
 	if err != nil {
@@ -45,35 +61,54 @@ func (client *client) Status() (*ClusterStatusResult, error) {
 	}
 	proxyConfig.ApplyToEnvironment()
 
-	ip, err := getIP(host, client.useVSock())
+	sshRunner, err := client.sshRunner(host)
 	if err != nil {
-		return nil, errors.Wrap(err, "Error getting ip")
-	}
-	sshRunner, err := crcssh.CreateRunner(ip, getSSHPort(client.useVSock()), constants.GetPrivateKeyPath(), constants.GetRsaPrivateKeyPath())
-	if err != nil {
-		return nil, errors.Wrap(err, "Error creating the ssh client")
+		return nil, err
 	}
 	defer sshRunner.Close()
-	// check if all the clusteroperators are running
 	diskSize, diskUse, err := cluster.GetRootPartitionUsage(sshRunner)
 	if err != nil {
 		return nil, errors.Wrap(err, "Cannot get root partition usage")
 	}
+	// check if all the clusteroperators are running
+	operators, err := cluster.GetClusterOperatorsStatuses(oc.UseOCWithSSH(sshRunner), client.monitoringEnabled())
+	if err != nil {
+		logging.Debugf("cannot get OpenShift status: %v", err)
+	}
+	vmStats, err := cluster.GetVMStats(sshRunner)
+	if err != nil {
+		logging.Debugf("cannot get VM resource usage: %v", err)
+	}
 	return &ClusterStatusResult{
 		CrcStatus:        state.Running,
-		OpenshiftStatus:  getOpenShiftStatus(sshRunner, client.monitoringEnabled()),
+		OpenshiftStatus:  getOpenShiftStatus(operators),
 		OpenshiftVersion: crcBundleMetadata.GetOpenshiftVersion(),
 		DiskUse:          diskUse,
 		DiskSize:         diskSize,
+		BundleExpiry:     bundleExpiry,
+		Operators:        operators,
+		VMStats:          vmStats,
+		CertsExpiry:      getCertsExpiry(sshRunner),
 	}, nil
 }
 
-func getOpenShiftStatus(sshRunner *crcssh.Runner, monitoringEnabled bool) string {
-	status, err := cluster.GetClusterOperatorsStatus(oc.UseOCWithSSH(sshRunner), monitoringEnabled)
+func getCertsExpiry(sshRunner *crcssh.Runner) *CertsExpiry {
+	clientExpiry, serverExpiry, err := cluster.GetKubeletCertsExpiryDates(sshRunner)
 	if err != nil {
-		logging.Debugf("cannot get OpenShift status: %v", err)
+		logging.Debugf("cannot get kubelet certificates expiry dates: %v", err)
+		return nil
+	}
+	return &CertsExpiry{
+		KubeletClient: clientExpiry,
+		KubeletServer: serverExpiry,
+	}
+}
+
+func getOpenShiftStatus(operators []cluster.OperatorStatus) string {
+	if len(operators) == 0 {
 		return "Unreachable"
 	}
+	status := cluster.AggregateStatus(operators)
 	switch {
 	case status.Progressing:
 		return "Starting"
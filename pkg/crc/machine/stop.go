@@ -1,27 +1,81 @@
 package machine
 
 import (
+	"context"
+	"time"
+
+	"github.com/code-ready/crc/pkg/crc/constants"
 	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/code-ready/crc/pkg/crc/systemd"
+	"github.com/code-ready/crc/pkg/libmachine/host"
 	"github.com/code-ready/machine/libmachine/state"
 	"github.com/pkg/errors"
 )
 
-func (client *client) Stop() (state.State, error) {
+// Stop shuts the VM down gracefully: the kubelet and crio are stopped and
+// the disks synced before the guest is asked to shut down. If it is still
+// running once stopConfig.Timeout is elapsed, the VM is powered off.
+func (client *client) Stop(ctx context.Context, stopConfig StopConfig) (*StopResult, error) {
 	libMachineAPIClient, cleanup := createLibMachineClient()
 	defer cleanup()
 	host, err := libMachineAPIClient.Load(client.name)
 
 	if err != nil {
-		return state.None, errors.Wrap(err, "Cannot load machine")
+		return nil, errors.Wrap(err, "Cannot load machine")
+	}
+
+	timeout := stopConfig.Timeout
+	if timeout == 0 {
+		timeout, _ = time.ParseDuration(constants.DefaultStopTimeout)
 	}
 
 	logging.Info("Stopping the OpenShift cluster, this may take a few minutes...")
-	if err := host.Stop(); err != nil {
-		status, err := host.Driver.GetState()
-		if err != nil {
-			logging.Debugf("Cannot get VM status after stopping it: %v", err)
+	if vmState, err := host.Driver.GetState(); err == nil && vmState == state.Running {
+		client.stopClusterServices(host)
+	}
+
+	stopCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	stopErr := host.Stop(stopCtx)
+	if stopErr == nil {
+		return &StopResult{State: state.Stopped}, nil
+	}
+	status, err := host.Driver.GetState()
+	if err != nil {
+		logging.Debugf("Cannot get VM status after stopping it: %v", err)
+		return &StopResult{State: status}, errors.Wrap(stopErr, "Cannot stop machine")
+	}
+	if status != state.Running || ctx.Err() != nil {
+		return &StopResult{State: status}, errors.Wrap(stopErr, "Cannot stop machine")
+	}
+
+	logging.Warnf("The VM did not shut down within %s, powering it off", timeout)
+	if err := host.Kill(ctx); err != nil {
+		return &StopResult{State: status}, errors.Wrap(err, "Cannot power off machine")
+	}
+	status, err = host.Driver.GetState()
+	return &StopResult{State: status, Forced: true}, err
+}
+
+// stopClusterServices stops the kubelet and crio so that the containers are
+// terminated cleanly, and flushes the disks before the guest shutdown. This
+// is best effort, errors are only logged as the shutdown is attempted anyway.
+func (client *client) stopClusterServices(host *host.Host) {
+	sshRunner, err := client.sshRunner(host)
+	if err != nil {
+		logging.Debugf("Cannot connect to the VM: %v", err)
+		return
+	}
+	defer sshRunner.Close()
+
+	sd := systemd.NewInstanceSystemdCommander(sshRunner)
+	for _, service := range []string{"kubelet", "crio"} {
+		logging.Debugf("Stopping %s", service)
+		if err := sd.Stop(service); err != nil {
+			logging.Debugf("Cannot stop %s: %v", service, err)
 		}
-		return status, errors.Wrap(err, "Cannot stop machine")
 	}
-	return host.Driver.GetState()
+	if _, _, err := sshRunner.Run("sync"); err != nil {
+		logging.Debugf("Cannot sync the VM disks: %v", err)
+	}
 }
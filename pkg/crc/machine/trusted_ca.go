@@ -0,0 +1,46 @@
+package machine
+
+import (
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+
+	cmdConfig "github.com/code-ready/crc/cmd/crc/cmd/config"
+	"github.com/code-ready/crc/pkg/crc/cluster"
+	"github.com/code-ready/crc/pkg/crc/network"
+)
+
+// trustedCABundle returns the CAs trusted by the node and the cluster in
+// addition to the system ones: the proxy CA and the files of the
+// additional-trusted-ca-files setting
+func (client *client) trustedCABundle(proxy *network.ProxyConfig) (string, error) {
+	var certs []string
+	if proxy.IsEnabled() {
+		certs = append(certs, proxy.ProxyCACert)
+	}
+	for _, file := range cmdConfig.TrustedCAFiles(client.config) {
+		cert, err := readCAFile(file)
+		if err != nil {
+			return "", err
+		}
+		certs = append(certs, cert)
+	}
+	return cluster.TrustedCABundle(certs...), nil
+}
+
+// trustedCARegistries returns the registries of the additional-trusted-ca-registries
+// setting, the cluster uses the additional trusted CAs to import their images
+func (client *client) trustedCARegistries() ([]string, error) {
+	return network.ParseRegistryHosts(client.config.Get(cmdConfig.TrustedCARegistries).AsString())
+}
+
+func readCAFile(file string) (string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("Cannot read trusted CA file: %v", err)
+	}
+	if block, _ := pem.Decode(data); block == nil || block.Type != "CERTIFICATE" {
+		return "", fmt.Errorf("Trusted CA file %s does not contain a PEM certificate", file)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,55 @@
+package machine
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cmdConfig "github.com/code-ready/crc/cmd/crc/cmd/config"
+	crcConfig "github.com/code-ready/crc/pkg/crc/config"
+	"github.com/code-ready/crc/pkg/crc/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dummyCA = "-----BEGIN CERTIFICATE-----\nMIIDODCCAiCgAwIBAgIIRVfCKNUa1wIw\n-----END CERTIFICATE-----\n"
+
+func TestTrustedCABundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ca")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	caFile := filepath.Join(dir, "corporate-ca.crt")
+	require.NoError(t, ioutil.WriteFile(caFile, []byte(dummyCA), 0600))
+	invalidFile := filepath.Join(dir, "invalid.crt")
+	require.NoError(t, ioutil.WriteFile(invalidFile, []byte("not a certificate"), 0600))
+
+	cfg := crcConfig.New(crcConfig.NewEmptyInMemoryStorage())
+	cmdConfig.RegisterSettings(cfg)
+	client := NewClient("crc", false, cfg).(*client)
+	proxy := &network.ProxyConfig{
+		HTTPProxy:   "http://proxy.example.com:3128",
+		ProxyCACert: "proxy-ca",
+	}
+
+	bundle, err := client.trustedCABundle(proxy)
+	assert.NoError(t, err)
+	assert.Equal(t, "proxy-ca\n", bundle)
+
+	_, err = cfg.Set(cmdConfig.AdditionalTrustedCAFiles, caFile)
+	require.NoError(t, err)
+	bundle, err = client.trustedCABundle(&network.ProxyConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, dummyCA, bundle)
+	bundle, err = client.trustedCABundle(proxy)
+	assert.NoError(t, err)
+	assert.Equal(t, "proxy-ca\n"+dummyCA, bundle)
+
+	_, err = cfg.Set(cmdConfig.AdditionalTrustedCAFiles, caFile+","+invalidFile)
+	require.NoError(t, err)
+	_, err = client.trustedCABundle(proxy)
+	assert.EqualError(t, err, "Trusted CA file "+invalidFile+" does not contain a PEM certificate")
+
+	_, err = cfg.Set(cmdConfig.AdditionalTrustedCAFiles, filepath.Join(dir, "missing.crt"))
+	assert.Error(t, err)
+}
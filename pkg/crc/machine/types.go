@@ -1,6 +1,8 @@
 package machine
 
 import (
+	"time"
+
 	"github.com/code-ready/crc/pkg/crc/cluster"
 	"github.com/code-ready/crc/pkg/crc/network"
 	"github.com/code-ready/machine/libmachine/state"
@@ -20,8 +22,36 @@ type StartConfig struct {
 
 	// User Pull secret
 	PullSecret cluster.PullSecretLoader
+
+	// Condition to wait for once the kubelet is started, constants.WaitOperators when empty
+	Wait string
+	// Maximum time spent waiting for the cluster, constants.DefaultWaitTimeout when zero
+	WaitTimeout time.Duration
+	// Cluster operators which must be ready, all of them when empty
+	WaitOperators []string
+
+	// Optional callback notified of the progress of the start
+	Progress ProgressReporter
+
+	// Step to resume from, even if it and the next ones were already completed.
+	// When empty, the start resumes from the first step which did not complete.
+	FromStep string
+}
+
+type StopConfig struct {
+	// Time given to the VM to shut down before it is powered off, constants.DefaultStopTimeout when zero
+	Timeout time.Duration
 }
 
+// StartProgress describes the step Start is currently running
+type StartProgress struct {
+	Step       string
+	Percentage int
+	Message    string
+}
+
+type ProgressReporter func(StartProgress)
+
 type ClusterConfig struct {
 	ClusterCACert string
 	KubeConfig    string
@@ -35,6 +65,14 @@ type StartResult struct {
 	Status         state.State
 	ClusterConfig  ClusterConfig
 	KubeletStarted bool
+	// Time spent in the steps which were run, skipped steps are not listed
+	Timings []StepTiming
+}
+
+// StepTiming is the time spent in one of the steps returned by StartSteps
+type StepTiming struct {
+	Step     string
+	Duration time.Duration
 }
 
 type StopResult struct {
@@ -42,6 +80,8 @@ type StopResult struct {
 	Success bool
 	State   state.State
 	Error   string
+	// Forced is true when the VM did not shut down in time and was powered off
+	Forced bool
 }
 
 type ClusterStatusResult struct {
@@ -50,9 +90,50 @@ type ClusterStatusResult struct {
 	OpenshiftVersion string
 	DiskUse          int64
 	DiskSize         int64
+	// Nil when the bundle used by the VM has no build time
+	BundleExpiry *BundleExpiry
+
+	// The following fields are only set when the VM is running, and left
+	// empty when they cannot be retrieved
+	Operators   []cluster.OperatorStatus
+	VMStats     *cluster.VMStats
+	CertsExpiry *CertsExpiry
+}
+
+// CertsExpiry gives the expiry dates of the kubelet certificates
+type CertsExpiry struct {
+	KubeletClient time.Time
+	KubeletServer time.Time
+}
+
+// BundleExpiry tells when the certificates of the cluster created from the
+// bundle can no longer be renewed. Warning is only set when this date is close.
+type BundleExpiry struct {
+	ExpiresAt time.Time
+	Warning   string
 }
 
 type ConsoleResult struct {
 	ClusterConfig ClusterConfig
 	State         state.State
 }
+
+type ConnectionDetails struct {
+	IP          string
+	SSHPort     int
+	SSHUsername string
+	SSHKeys     []string
+}
+
+// Mount describes a directory of the host shared with the VM
+type Mount struct {
+	HostDir string
+	VMDir   string
+}
+
+// Snapshot describes a saved state of the disk of the stopped VM
+type Snapshot struct {
+	Name       string
+	CreatedAt  time.Time
+	BundleName string
+}
@@ -0,0 +1,96 @@
+package network
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+// registries and repositories, with an optional port and path
+var validRegistry = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*(:[0-9]+)?(/[a-zA-Z0-9._-]+)*$`)
+
+// registry hosts, with an optional port
+var validRegistryHost = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*(:[0-9]+)?$`)
+
+// RegistryMirror redirects the pulls of the images of Source to Mirror
+type RegistryMirror struct {
+	Source string
+	Mirror string
+}
+
+// ParseRegistryMirrors parses a comma separated list of source=mirror entries
+func ParseRegistryMirrors(value string) ([]RegistryMirror, error) {
+	var mirrors []RegistryMirror
+	for _, entry := range strings.Split(value, ",") {
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, "=")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("registry mirror '%s' must be in the source=mirror format", entry)
+		}
+		for _, registry := range parts {
+			if !validRegistry.MatchString(registry) {
+				return nil, fmt.Errorf("'%s' is not a valid registry", registry)
+			}
+		}
+		mirrors = append(mirrors, RegistryMirror{
+			Source: parts[0],
+			Mirror: parts[1],
+		})
+	}
+	return mirrors, nil
+}
+
+// ParseInsecureRegistries parses a comma separated list of registries
+func ParseInsecureRegistries(value string) ([]string, error) {
+	var registries []string
+	for _, registry := range strings.Split(value, ",") {
+		if registry == "" {
+			continue
+		}
+		if !validRegistry.MatchString(registry) {
+			return nil, fmt.Errorf("'%s' is not a valid registry", registry)
+		}
+		registries = append(registries, registry)
+	}
+	return registries, nil
+}
+
+// ParseRegistryHosts parses a comma separated list of registry hosts
+func ParseRegistryHosts(value string) ([]string, error) {
+	var hosts []string
+	for _, host := range strings.Split(value, ",") {
+		if host == "" {
+			continue
+		}
+		if !validRegistryHost.MatchString(host) {
+			return nil, fmt.Errorf("'%s' is not a valid registry host", host)
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
+
+func ValidateRegistryMirrors(val interface{}) (bool, string) {
+	if _, err := ParseRegistryMirrors(cast.ToString(val)); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+func ValidateInsecureRegistries(val interface{}) (bool, string) {
+	if _, err := ParseInsecureRegistries(cast.ToString(val)); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+func ValidateRegistryHosts(val interface{}) (bool, string) {
+	if _, err := ParseRegistryHosts(cast.ToString(val)); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
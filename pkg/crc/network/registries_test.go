@@ -0,0 +1,43 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRegistryMirrors(t *testing.T) {
+	mirrors, err := ParseRegistryMirrors("")
+	assert.NoError(t, err)
+	assert.Empty(t, mirrors)
+
+	mirrors, err = ParseRegistryMirrors("registry.redhat.io=mirror.example.com:5000/redhat,quay.io/openshift=mirror.example.com:5000/quay")
+	assert.NoError(t, err)
+	assert.Equal(t, []RegistryMirror{
+		{Source: "registry.redhat.io", Mirror: "mirror.example.com:5000/redhat"},
+		{Source: "quay.io/openshift", Mirror: "mirror.example.com:5000/quay"},
+	}, mirrors)
+
+	_, err = ParseRegistryMirrors("registry.redhat.io")
+	assert.EqualError(t, err, "registry mirror 'registry.redhat.io' must be in the source=mirror format")
+	_, err = ParseRegistryMirrors("registry.redhat.io=http://mirror.example.com")
+	assert.EqualError(t, err, "'http://mirror.example.com' is not a valid registry")
+}
+
+func TestParseInsecureRegistries(t *testing.T) {
+	registries, err := ParseInsecureRegistries("192.168.1.10:5000,registry.lan")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.10:5000", "registry.lan"}, registries)
+
+	_, err = ParseInsecureRegistries("registry.lan registry2.lan")
+	assert.EqualError(t, err, "'registry.lan registry2.lan' is not a valid registry")
+}
+
+func TestParseRegistryHosts(t *testing.T) {
+	hosts, err := ParseRegistryHosts("registry.example.com,192.168.1.10:5000")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"registry.example.com", "192.168.1.10:5000"}, hosts)
+
+	_, err = ParseRegistryHosts("registry.example.com/team")
+	assert.EqualError(t, err, "'registry.example.com/team' is not a valid registry host")
+}
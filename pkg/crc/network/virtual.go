@@ -0,0 +1,303 @@
+package network
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/code-ready/crc/pkg/crc/constants"
+	"github.com/spf13/cast"
+)
+
+const (
+	DefaultVirtualSubnet    = "192.168.127.0/24"
+	DefaultVirtualGatewayIP = "192.168.127.1"
+	DefaultVirtualHostIP    = "192.168.127.254"
+	// Large packets slightly improve the performance. Less small packets.
+	DefaultVirtualMTU = 4000
+
+	// DefaultVirtualGatewayMAC is a locally administered address, it cannot collide with a real device
+	DefaultVirtualGatewayMAC = "5a:94:ef:e4:0c:dd"
+	// DefaultVirtualForwardedPorts are the ports of the API server and of the router
+	DefaultVirtualForwardedPorts = "6443,443"
+
+	// 192.168.130.0/24 is the subnet of the libvirt network
+	libvirtSubnetOctet = 130
+
+	minVirtualMTU = 1500
+	maxVirtualMTU = 65535
+)
+
+// VirtualNetwork describes the user mode network provided by the daemon to
+// the VM when the network mode is vsock
+type VirtualNetwork struct {
+	Subnet string
+	// Address of the gateway, it is also the DNS server of the VM
+	GatewayIP string
+	// Address translated to 127.0.0.1 on the host
+	HostIP     string
+	MTU        int
+	GatewayMAC string
+	// Comma separated list of ports forwarded from the host to the VM,
+	// port or hostPort:vmPort entries
+	ForwardedPorts string
+}
+
+// PortForward forwards HostPort on the host to VMPort in the VM
+type PortForward struct {
+	HostPort int
+	VMPort   int
+}
+
+// VMIP returns the address of the VM. The daemon leases the first free
+// address of the subnet to the VM, the gateway address being reserved.
+func (vn VirtualNetwork) VMIP() (net.IP, error) {
+	subnet, err := parseVirtualSubnet(vn.Subnet)
+	if err != nil {
+		return nil, err
+	}
+	gateway := net.ParseIP(vn.GatewayIP)
+	for i := 1; i < 3; i++ {
+		ip := hostAddress(subnet, i)
+		if !ip.Equal(gateway) {
+			return ip, nil
+		}
+	}
+	return nil, fmt.Errorf("no address left for the VM in subnet %s", vn.Subnet)
+}
+
+// Validate checks the settings of the virtual network against each other
+func (vn VirtualNetwork) Validate() error {
+	subnet, err := parseVirtualSubnet(vn.Subnet)
+	if err != nil {
+		return err
+	}
+	gateway, err := parseSubnetAddress(subnet, "gateway", vn.GatewayIP)
+	if err != nil {
+		return err
+	}
+	host, err := parseSubnetAddress(subnet, "host", vn.HostIP)
+	if err != nil {
+		return err
+	}
+	if gateway.Equal(host) {
+		return fmt.Errorf("the gateway and the host virtual addresses must be different, both are %s", gateway)
+	}
+	vm, err := vn.VMIP()
+	if err != nil {
+		return err
+	}
+	if host.Equal(vm) {
+		return fmt.Errorf("the host virtual address %s is leased to the VM, use another address", host)
+	}
+	if _, err := vn.GatewayMACAddress(); err != nil {
+		return err
+	}
+	if _, err := vn.PortForwards(); err != nil {
+		return err
+	}
+	return validateVirtualMTU(vn.MTU)
+}
+
+// GatewayMACAddress returns the MAC address of the gateway in binary form
+func (vn VirtualNetwork) GatewayMACAddress() (string, error) {
+	mac, err := parseGatewayMAC(vn.GatewayMAC)
+	if err != nil {
+		return "", err
+	}
+	return string(mac), nil
+}
+
+// PortForwards returns the ports forwarded from the host to the VM, the SSH
+// port used by crc is always forwarded
+func (vn VirtualNetwork) PortForwards() ([]PortForward, error) {
+	forwards, err := parseForwardedPorts(vn.ForwardedPorts)
+	if err != nil {
+		return nil, err
+	}
+	return append([]PortForward{{HostPort: constants.VsockSSHPort, VMPort: 22}}, forwards...), nil
+}
+
+func parseVirtualSubnet(value string) (*net.IPNet, error) {
+	ip, subnet, err := net.ParseCIDR(value)
+	if err != nil || ip.To4() == nil {
+		return nil, fmt.Errorf("'%s' is not a valid IPv4 subnet, for instance %s", value, DefaultVirtualSubnet)
+	}
+	if !ip.Equal(subnet.IP) {
+		return nil, fmt.Errorf("'%s' is not a subnet address, did you mean %s?", value, subnet)
+	}
+	// the gateway, the VM and the host need an address
+	if ones, _ := subnet.Mask.Size(); ones > 29 {
+		return nil, fmt.Errorf("subnet %s is too small, its prefix length must be at most 29", value)
+	}
+	return subnet, nil
+}
+
+func parseSubnetAddress(subnet *net.IPNet, name, value string) (net.IP, error) {
+	ip := net.ParseIP(value)
+	if ip == nil || ip.To4() == nil {
+		return nil, fmt.Errorf("the %s virtual address '%s' is not a valid IPv4 address", name, value)
+	}
+	if !subnet.Contains(ip) {
+		return nil, fmt.Errorf("the %s virtual address %s is not in subnet %s", name, ip, subnet)
+	}
+	if ip.Equal(subnet.IP) || ip.Equal(broadcastAddress(subnet)) {
+		return nil, fmt.Errorf("the %s virtual address %s cannot be the network or the broadcast address of subnet %s", name, ip, subnet)
+	}
+	return ip, nil
+}
+
+func parseGatewayMAC(value string) (net.HardwareAddr, error) {
+	mac, err := net.ParseMAC(value)
+	if err != nil || len(mac) != 6 {
+		return nil, fmt.Errorf("'%s' is not a valid MAC address, for instance %s", value, DefaultVirtualGatewayMAC)
+	}
+	if mac[0]&1 == 1 {
+		return nil, fmt.Errorf("'%s' is a multicast MAC address, the gateway needs a unicast address", value)
+	}
+	return mac, nil
+}
+
+func parseForwardedPorts(value string) ([]PortForward, error) {
+	var forwards []PortForward
+	hostPorts := map[int]bool{constants.VsockSSHPort: true}
+	for _, entry := range strings.Split(value, ",") {
+		if entry == "" {
+			continue
+		}
+		ports := strings.Split(entry, ":")
+		if len(ports) > 2 {
+			return nil, fmt.Errorf("forwarded port '%s' must be in the port or hostPort:vmPort format", entry)
+		}
+		hostPort, err := parsePort(ports[0])
+		if err != nil {
+			return nil, err
+		}
+		vmPort := hostPort
+		if len(ports) == 2 {
+			if vmPort, err = parsePort(ports[1]); err != nil {
+				return nil, err
+			}
+		}
+		if hostPorts[hostPort] {
+			return nil, fmt.Errorf("host port %d is already forwarded to the VM", hostPort)
+		}
+		hostPorts[hostPort] = true
+		forwards = append(forwards, PortForward{
+			HostPort: hostPort,
+			VMPort:   vmPort,
+		})
+	}
+	return forwards, nil
+}
+
+func parsePort(value string) (int, error) {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("'%s' is not a valid port", value)
+	}
+	return port, nil
+}
+
+func validateVirtualMTU(mtu int) error {
+	if mtu < minVirtualMTU || mtu > maxVirtualMTU {
+		return fmt.Errorf("MTU %d is not between %d and %d", mtu, minVirtualMTU, maxVirtualMTU)
+	}
+	return nil
+}
+
+func hostAddress(subnet *net.IPNet, index int) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, binary.BigEndian.Uint32(subnet.IP.To4())+uint32(index))
+	return ip
+}
+
+func broadcastAddress(subnet *net.IPNet) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, binary.BigEndian.Uint32(subnet.IP.To4())|^binary.BigEndian.Uint32(net.IP(subnet.Mask).To4()))
+	return ip
+}
+
+// ValidateVirtualSubnet checks if the value is an IPv4 subnet large enough for the virtual network
+func ValidateVirtualSubnet(val interface{}) (bool, string) {
+	if _, err := parseVirtualSubnet(cast.ToString(val)); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+// ValidateVirtualMTU checks if the value is a valid MTU for the virtual network
+func ValidateVirtualMTU(val interface{}) (bool, string) {
+	mtu, err := cast.ToIntE(val)
+	if err != nil {
+		return false, fmt.Sprintf("could not convert '%s' to integer", val)
+	}
+	if err := validateVirtualMTU(mtu); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+// ValidateVirtualGatewayMAC checks if the value is a unicast MAC address
+func ValidateVirtualGatewayMAC(val interface{}) (bool, string) {
+	if _, err := parseGatewayMAC(cast.ToString(val)); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+// ValidateVirtualForwardedPorts checks if the value is a comma separated list
+// of port or hostPort:vmPort entries
+func ValidateVirtualForwardedPorts(val interface{}) (bool, string) {
+	if _, err := parseForwardedPorts(cast.ToString(val)); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+func SuccessfullyAppliedVirtualNetwork(key string, _ interface{}) string {
+	return fmt.Sprintf("Changes to configuration property '%s' are only applied when the crc daemon starts.\n"+
+		"Stop the CRC instance with 'crc stop', then restart the daemon, for instance with 'crc cleanup' and 'crc setup'.", key)
+}
+
+// AllocateVirtualNetwork returns a virtual network whose subnet does not
+// overlap with the subnets in use, so that each profile gets its own
+// addresses. The subnets are taken from 192.168.128.0/24 to 192.168.254.0/24,
+// except the one of the libvirt network.
+func AllocateVirtualNetwork(used []string) (VirtualNetwork, error) {
+	var usedSubnets []*net.IPNet
+	for _, subnet := range used {
+		if _, ipNet, err := net.ParseCIDR(subnet); err == nil {
+			usedSubnets = append(usedSubnets, ipNet)
+		}
+	}
+	for i := 128; i < 255; i++ {
+		if i == libvirtSubnetOctet {
+			continue
+		}
+		candidate := &net.IPNet{IP: net.IPv4(192, 168, byte(i), 0).To4(), Mask: net.CIDRMask(24, 32)}
+		if overlapsAny(candidate, usedSubnets) {
+			continue
+		}
+		return VirtualNetwork{
+			Subnet:         candidate.String(),
+			GatewayIP:      hostAddress(candidate, 1).String(),
+			HostIP:         hostAddress(candidate, 254).String(),
+			MTU:            DefaultVirtualMTU,
+			GatewayMAC:     DefaultVirtualGatewayMAC,
+			ForwardedPorts: DefaultVirtualForwardedPorts,
+		}, nil
+	}
+	return VirtualNetwork{}, fmt.Errorf("no free subnet left for the virtual network")
+}
+
+func overlapsAny(subnet *net.IPNet, subnets []*net.IPNet) bool {
+	for _, other := range subnets {
+		if other.Contains(subnet.IP) || subnet.Contains(other.IP) {
+			return true
+		}
+	}
+	return false
+}
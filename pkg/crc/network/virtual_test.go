@@ -0,0 +1,136 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func defaultVirtualNetwork() VirtualNetwork {
+	return VirtualNetwork{
+		Subnet:         DefaultVirtualSubnet,
+		GatewayIP:      DefaultVirtualGatewayIP,
+		HostIP:         DefaultVirtualHostIP,
+		MTU:            DefaultVirtualMTU,
+		GatewayMAC:     DefaultVirtualGatewayMAC,
+		ForwardedPorts: DefaultVirtualForwardedPorts,
+	}
+}
+
+func TestVirtualNetworkDefaults(t *testing.T) {
+	vn := defaultVirtualNetwork()
+	assert.NoError(t, vn.Validate())
+	vmIP, err := vn.VMIP()
+	assert.NoError(t, err)
+	assert.Equal(t, net.ParseIP("192.168.127.2").To4(), vmIP)
+}
+
+func TestVirtualNetworkVMIP(t *testing.T) {
+	vn := VirtualNetwork{
+		Subnet:     "10.88.0.0/16",
+		GatewayIP:  "10.88.0.2",
+		HostIP:     "10.88.255.254",
+		MTU:        1500,
+		GatewayMAC: DefaultVirtualGatewayMAC,
+	}
+	assert.NoError(t, vn.Validate())
+	vmIP, err := vn.VMIP()
+	assert.NoError(t, err)
+	assert.Equal(t, net.ParseIP("10.88.0.1").To4(), vmIP)
+}
+
+func TestVirtualNetworkValidate(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		update func(vn *VirtualNetwork)
+		err    string
+	}{
+		{"invalid subnet", func(vn *VirtualNetwork) { vn.Subnet = "192.168.127.0" }, "'192.168.127.0' is not a valid IPv4 subnet, for instance 192.168.127.0/24"},
+		{"ipv6 subnet", func(vn *VirtualNetwork) { vn.Subnet = "fd00::/64" }, "'fd00::/64' is not a valid IPv4 subnet, for instance 192.168.127.0/24"},
+		{"not a subnet address", func(vn *VirtualNetwork) { vn.Subnet = "192.168.127.1/24" }, "'192.168.127.1/24' is not a subnet address, did you mean 192.168.127.0/24?"},
+		{"small subnet", func(vn *VirtualNetwork) { vn.Subnet = "192.168.127.0/30" }, "subnet 192.168.127.0/30 is too small, its prefix length must be at most 29"},
+		{"gateway outside subnet", func(vn *VirtualNetwork) { vn.GatewayIP = "192.168.128.1" }, "the gateway virtual address 192.168.128.1 is not in subnet 192.168.127.0/24"},
+		{"broadcast host", func(vn *VirtualNetwork) { vn.HostIP = "192.168.127.255" }, "the host virtual address 192.168.127.255 cannot be the network or the broadcast address of subnet 192.168.127.0/24"},
+		{"same addresses", func(vn *VirtualNetwork) { vn.HostIP = vn.GatewayIP }, "the gateway and the host virtual addresses must be different, both are 192.168.127.1"},
+		{"host leased to the VM", func(vn *VirtualNetwork) { vn.HostIP = "192.168.127.2" }, "the host virtual address 192.168.127.2 is leased to the VM, use another address"},
+		{"small MTU", func(vn *VirtualNetwork) { vn.MTU = 576 }, "MTU 576 is not between 1500 and 65535"},
+		{"invalid MAC", func(vn *VirtualNetwork) { vn.GatewayMAC = "5a:94:ef" }, "'5a:94:ef' is not a valid MAC address, for instance 5a:94:ef:e4:0c:dd"},
+		{"multicast MAC", func(vn *VirtualNetwork) { vn.GatewayMAC = "01:00:5e:00:00:01" }, "'01:00:5e:00:00:01' is a multicast MAC address, the gateway needs a unicast address"},
+		{"invalid port", func(vn *VirtualNetwork) { vn.ForwardedPorts = "6443,http" }, "'http' is not a valid port"},
+		{"SSH port", func(vn *VirtualNetwork) { vn.ForwardedPorts = "2222:22" }, "host port 2222 is already forwarded to the VM"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			vn := defaultVirtualNetwork()
+			test.update(&vn)
+			assert.EqualError(t, vn.Validate(), test.err)
+		})
+	}
+}
+
+func TestValidateVirtualMTU(t *testing.T) {
+	valid, _ := ValidateVirtualMTU("9000")
+	assert.True(t, valid)
+	valid, msg := ValidateVirtualMTU("large")
+	assert.False(t, valid)
+	assert.Equal(t, "could not convert 'large' to integer", msg)
+}
+
+func TestAllocateVirtualNetwork(t *testing.T) {
+	vn, err := AllocateVirtualNetwork([]string{DefaultVirtualSubnet})
+	assert.NoError(t, err)
+	assert.Equal(t, VirtualNetwork{
+		Subnet:         "192.168.128.0/24",
+		GatewayIP:      "192.168.128.1",
+		HostIP:         "192.168.128.254",
+		MTU:            DefaultVirtualMTU,
+		GatewayMAC:     DefaultVirtualGatewayMAC,
+		ForwardedPorts: DefaultVirtualForwardedPorts,
+	}, vn)
+	assert.NoError(t, vn.Validate())
+
+	vn, err = AllocateVirtualNetwork([]string{DefaultVirtualSubnet, "192.168.128.0/23", "invalid"})
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.131.0/24", vn.Subnet)
+
+	vn, err = AllocateVirtualNetwork([]string{"192.168.128.0/17"})
+	assert.Error(t, err)
+	assert.Equal(t, VirtualNetwork{}, vn)
+}
+
+func TestVirtualNetworkGatewayMACAddress(t *testing.T) {
+	mac, err := defaultVirtualNetwork().GatewayMACAddress()
+	assert.NoError(t, err)
+	assert.Equal(t, "\x5A\x94\xEF\xE4\x0C\xDD", mac)
+}
+
+func TestVirtualNetworkPortForwards(t *testing.T) {
+	vn := defaultVirtualNetwork()
+	vn.ForwardedPorts = "6443,8443:443,"
+	forwards, err := vn.PortForwards()
+	assert.NoError(t, err)
+	assert.Equal(t, []PortForward{
+		{HostPort: 2222, VMPort: 22},
+		{HostPort: 6443, VMPort: 6443},
+		{HostPort: 8443, VMPort: 443},
+	}, forwards)
+
+	vn.ForwardedPorts = ""
+	forwards, err = vn.PortForwards()
+	assert.NoError(t, err)
+	assert.Equal(t, []PortForward{{HostPort: 2222, VMPort: 22}}, forwards)
+}
+
+func TestValidateVirtualForwardedPorts(t *testing.T) {
+	ok, _ := ValidateVirtualForwardedPorts("6443,8443:443")
+	assert.True(t, ok)
+	ok, msg := ValidateVirtualForwardedPorts("443,443")
+	assert.False(t, ok)
+	assert.Equal(t, "host port 443 is already forwarded to the VM", msg)
+	ok, msg = ValidateVirtualForwardedPorts("1:2:3")
+	assert.False(t, ok)
+	assert.Equal(t, "forwarded port '1:2:3' must be in the port or hostPort:vmPort format", msg)
+	ok, msg = ValidateVirtualForwardedPorts("70000")
+	assert.False(t, ok)
+	assert.Equal(t, "'70000' is not a valid port", msg)
+}
@@ -141,6 +141,31 @@ func doCleanUpPreflightChecks(checks []Check) error {
 	return mErr
 }
 
+// CheckResult is the outcome of a preflight check run by CheckHost
+type CheckResult struct {
+	Description string
+	Skipped     bool
+	Err         error
+}
+
+func doReportPreflightChecks(config config.Storage, checks []Check) []CheckResult {
+	var results []CheckResult
+	for _, check := range checks {
+		if check.flags&CleanUpOnly == CleanUpOnly {
+			continue
+		}
+		result := CheckResult{
+			Description: check.checkDescription,
+			Skipped:     check.shouldSkip(config),
+		}
+		if !result.Skipped {
+			result.Err = check.check()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
 func doRegisterSettings(cfg config.Schema, checks []Check) {
 	for _, check := range checks {
 		if check.configKeySuffix != "" {
@@ -166,6 +191,14 @@ func SetupHost(config config.Storage) error {
 	return doFixPreflightChecks(config, getPreflightChecks(experimentalFeatures, mode))
 }
 
+// CheckHost runs the setup and start preflight checks without fixing the
+// host and returns their results, it is used to diagnose the host
+func CheckHost(config config.Storage) []CheckResult {
+	experimentalFeatures := config.Get(cmdConfig.ExperimentalFeatures).AsBool()
+	mode := network.ParseMode(config.Get(cmdConfig.NetworkMode).AsString())
+	return doReportPreflightChecks(config, getPreflightChecks(experimentalFeatures, mode))
+}
+
 func RegisterSettings(config config.Schema) {
 	doRegisterSettings(config, getAllPreflightChecks())
 }
@@ -0,0 +1,223 @@
+package preflight
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/code-ready/crc/pkg/crc/constants"
+	crcErrors "github.com/code-ready/crc/pkg/crc/errors"
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/code-ready/crc/pkg/crc/systemd"
+	"github.com/code-ready/crc/pkg/crc/systemd/states"
+	crcos "github.com/code-ready/crc/pkg/os"
+)
+
+// The daemon of the default profile runs as a systemd user service. It is
+// started on demand when a client connects to the API or network socket.
+var daemonSystemdChecks = [...]Check{
+	{
+		configKeySuffix:    "check-daemon-systemd-unit",
+		checkDescription:   "Checking crc daemon systemd units",
+		check:              checkDaemonSystemdUnits,
+		fixDescription:     "Setting up crc daemon systemd units",
+		fix:                fixDaemonSystemdUnits,
+		cleanupDescription: "Removing crc daemon systemd units",
+		cleanup:            removeDaemonSystemdUnits,
+		flags:              SetupOnly,
+	},
+	{
+		configKeySuffix:  "check-daemon-systemd-sockets",
+		checkDescription: "Checking crc daemon systemd sockets",
+		check:            checkDaemonSystemdSockets,
+		fixDescription:   "Starting crc daemon systemd sockets",
+		fix:              fixDaemonSystemdSockets,
+		flags:            SetupOnly,
+	},
+	{
+		configKeySuffix:  "check-daemon-bundle",
+		checkDescription: "Checking if the crc daemon uses the current bundle",
+		check:            checkDaemonBundle,
+		fixDescription:   "Stopping the crc daemon, it is restarted with the current bundle",
+		fix:              stopDaemonService,
+		flags:            SetupOnly,
+	},
+}
+
+const (
+	daemonServiceUnit       = "crc-daemon.service"
+	daemonAPISocketUnit     = "crc-api.socket"
+	daemonNetworkSocketUnit = "crc-network.socket"
+
+	daemonServiceTemplate = `[Unit]
+Description=CodeReady Containers daemon
+Requires=%s %s
+
+[Service]
+ExecStart=%s daemon
+`
+	daemonSocketTemplate = `[Unit]
+Description=CodeReady Containers daemon %s socket
+
+[Socket]
+ListenStream=%s
+FileDescriptorName=%s
+Service=%s
+RemoveOnStop=yes
+
+[Install]
+WantedBy=sockets.target
+`
+)
+
+func daemonSocketUnits() []string {
+	return []string{daemonAPISocketUnit, daemonNetworkSocketUnit}
+}
+
+func systemdUserUnitsDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "systemd", "user")
+	}
+	return filepath.Join(constants.GetHomeDir(), ".config", "systemd", "user")
+}
+
+// daemonSystemdUnits returns the content of the unit files of the daemon,
+// indexed by unit name. The service runs the crc executable used for setup.
+func daemonSystemdUnits() (map[string]string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		daemonServiceUnit: fmt.Sprintf(daemonServiceTemplate, daemonAPISocketUnit, daemonNetworkSocketUnit, executable),
+		daemonAPISocketUnit: fmt.Sprintf(daemonSocketTemplate, "API", constants.DaemonSocketPath,
+			constants.DaemonAPISocketName, daemonServiceUnit),
+		daemonNetworkSocketUnit: fmt.Sprintf(daemonSocketTemplate, "network", constants.NetworkSocketPath,
+			constants.DaemonNetworkSocketName, daemonServiceUnit),
+	}, nil
+}
+
+func checkDaemonSystemdUnits() error {
+	units, err := daemonSystemdUnits()
+	if err != nil {
+		return err
+	}
+	for name, content := range units {
+		if err := crcos.FileContentMatches(filepath.Join(systemdUserUnitsDir(), name), []byte(content)); err != nil {
+			return fmt.Errorf("The %s systemd unit is missing or outdated: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func fixDaemonSystemdUnits() error {
+	units, err := daemonSystemdUnits()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(systemdUserUnitsDir(), 0750); err != nil {
+		return err
+	}
+	for name, content := range units {
+		// #nosec G306
+		if err := ioutil.WriteFile(filepath.Join(systemdUserUnitsDir(), name), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	sd := systemd.NewHostSystemdCommander().User()
+	if err := sd.DaemonReload(); err != nil {
+		return err
+	}
+	// a running daemon may be an older crc executable, the next
+	// connection to the sockets starts the new one
+	return stopDaemonService()
+}
+
+func stopDaemonService() error {
+	sd := systemd.NewHostSystemdCommander().User()
+	if state, err := sd.Status(daemonServiceUnit); err == nil && state == states.Running {
+		return sd.Stop(daemonServiceUnit)
+	}
+	return nil
+}
+
+func removeDaemonSystemdUnits() error {
+	sd := systemd.NewHostSystemdCommander().User()
+	var mErr crcErrors.MultiError
+	for _, socket := range daemonSocketUnits() {
+		if state, err := sd.Status(socket); err != nil || state == states.NotFound {
+			continue
+		}
+		if err := sd.Stop(socket); err != nil {
+			mErr.Collect(err)
+		}
+		if err := sd.Disable(socket); err != nil {
+			mErr.Collect(err)
+		}
+	}
+	if state, err := sd.Status(daemonServiceUnit); err == nil && state == states.Running {
+		if err := sd.Stop(daemonServiceUnit); err != nil {
+			mErr.Collect(err)
+		}
+	}
+	for _, name := range append(daemonSocketUnits(), daemonServiceUnit) {
+		if err := os.Remove(filepath.Join(systemdUserUnitsDir(), name)); err != nil && !os.IsNotExist(err) {
+			mErr.Collect(err)
+		}
+	}
+	if err := sd.DaemonReload(); err != nil {
+		logging.Debugf("Cannot reload the systemd user units: %v", err)
+	}
+	if len(mErr.Errors) == 0 {
+		return nil
+	}
+	return mErr
+}
+
+func checkDaemonSystemdSockets() error {
+	sd := systemd.NewHostSystemdCommander().User()
+	for _, socket := range daemonSocketUnits() {
+		state, err := sd.Status(socket)
+		if err != nil {
+			return err
+		}
+		// the socket is running once the daemon was started through it
+		if state != states.Listening && state != states.Running {
+			return fmt.Errorf("The %s systemd socket is %s, the crc daemon cannot be started", socket, state)
+		}
+	}
+	logging.Debugf("crc daemon systemd sockets are listening")
+	return nil
+}
+
+func fixDaemonSystemdSockets() error {
+	sd := systemd.NewHostSystemdCommander().User()
+	for _, socket := range daemonSocketUnits() {
+		if err := sd.Enable(socket); err != nil {
+			return err
+		}
+		if err := sd.Start(socket); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkDaemonBundle checks that a running daemon derived its DNS records from
+// the bundle of this crc version. The daemon is started on demand, it may
+// have been started before the bundle was extracted.
+func checkDaemonBundle() error {
+	sd := systemd.NewHostSystemdCommander().User()
+	if state, err := sd.Status(daemonServiceUnit); err != nil || state != states.Running {
+		return nil
+	}
+	bundleName, err := ioutil.ReadFile(constants.DaemonBundlePath)
+	if err != nil {
+		return fmt.Errorf("Cannot read the bundle used by the crc daemon: %v", err)
+	}
+	if string(bundleName) != constants.GetDefaultBundle() {
+		return fmt.Errorf("The crc daemon uses the bundle '%s' instead of '%s'", bundleName, constants.GetDefaultBundle())
+	}
+	return nil
+}
@@ -121,11 +121,11 @@ func getPreflightChecks(experimentalFeatures bool, mode network.Mode) []Check {
 	if mode == network.DefaultMode {
 		checks = append(checks, resolverPreflightChecks[:]...)
 	}
+	// the daemon derives its DNS records from the bundle, it must be extracted first
+	checks = append(checks, bundleCheck)
 	// Experimental feature
 	if experimentalFeatures {
 		checks = append(checks, traySetupChecks[:]...)
 	}
-
-	checks = append(checks, bundleCheck)
 	return checks
 }
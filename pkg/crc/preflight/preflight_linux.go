@@ -253,6 +253,7 @@ func getPreflightChecksForDistro(distro *linux.OsRelease, networkMode network.Mo
 		checks = append(checks, libvirtNetworkPreflightChecks[:]...)
 	}
 	checks = append(checks, bundleCheck)
+	checks = append(checks, daemonSystemdChecks[:]...)
 	return checks
 }
 
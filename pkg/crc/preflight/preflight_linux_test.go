@@ -86,6 +86,9 @@ var checkListForDistros = []checkListForDistro{
 			{check: checkLibvirtCrcNetworkAvailable},
 			{check: checkLibvirtCrcNetworkActive},
 			{check: checkBundleExtracted},
+			{check: checkDaemonSystemdUnits},
+			{check: checkDaemonSystemdSockets},
+			{check: checkDaemonBundle},
 		},
 	},
 	{
@@ -110,6 +113,9 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeCrcVM},
 			{check: checkVsock},
 			{check: checkBundleExtracted},
+			{check: checkDaemonSystemdUnits},
+			{check: checkDaemonSystemdSockets},
+			{check: checkDaemonBundle},
 		},
 	},
 	{
@@ -140,6 +146,9 @@ var checkListForDistros = []checkListForDistro{
 			{check: checkLibvirtCrcNetworkAvailable},
 			{check: checkLibvirtCrcNetworkActive},
 			{check: checkBundleExtracted},
+			{check: checkDaemonSystemdUnits},
+			{check: checkDaemonSystemdSockets},
+			{check: checkDaemonBundle},
 		},
 	},
 	{
@@ -164,6 +173,9 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeCrcVM},
 			{check: checkVsock},
 			{check: checkBundleExtracted},
+			{check: checkDaemonSystemdUnits},
+			{check: checkDaemonSystemdSockets},
+			{check: checkDaemonBundle},
 		},
 	},
 	{
@@ -194,6 +206,9 @@ var checkListForDistros = []checkListForDistro{
 			{check: checkLibvirtCrcNetworkAvailable},
 			{check: checkLibvirtCrcNetworkActive},
 			{check: checkBundleExtracted},
+			{check: checkDaemonSystemdUnits},
+			{check: checkDaemonSystemdSockets},
+			{check: checkDaemonBundle},
 		},
 	},
 	{
@@ -218,6 +233,9 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeCrcVM},
 			{check: checkVsock},
 			{check: checkBundleExtracted},
+			{check: checkDaemonSystemdUnits},
+			{check: checkDaemonSystemdSockets},
+			{check: checkDaemonBundle},
 		},
 	},
 	{
@@ -250,6 +268,9 @@ var checkListForDistros = []checkListForDistro{
 			{check: checkLibvirtCrcNetworkAvailable},
 			{check: checkLibvirtCrcNetworkActive},
 			{check: checkBundleExtracted},
+			{check: checkDaemonSystemdUnits},
+			{check: checkDaemonSystemdSockets},
+			{check: checkDaemonBundle},
 		},
 	},
 	{
@@ -275,6 +296,9 @@ var checkListForDistros = []checkListForDistro{
 			{configKeySuffix: "check-apparmor-profile-setup"},
 			{check: checkVsock},
 			{check: checkBundleExtracted},
+			{check: checkDaemonSystemdUnits},
+			{check: checkDaemonSystemdSockets},
+			{check: checkDaemonBundle},
 		},
 	},
 }
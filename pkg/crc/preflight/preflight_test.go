@@ -39,6 +39,26 @@ func TestFixPreflight(t *testing.T) {
 	assert.True(t, calls.fixed)
 }
 
+func TestReportPreflight(t *testing.T) {
+	check, calls := sampleCheck(errors.New("check failed"), nil)
+	cfg := config.New(config.NewEmptyInMemoryStorage())
+	doRegisterSettings(cfg, []Check{*check})
+
+	assert.Equal(t, []CheckResult{
+		{Description: "Sample check", Err: errors.New("check failed")},
+	}, doReportPreflightChecks(cfg, []Check{*check}))
+	assert.True(t, calls.checked)
+	assert.False(t, calls.fixed)
+
+	_, err := cfg.Set("skip-sample", true)
+	assert.NoError(t, err)
+	calls.checked = false
+	assert.Equal(t, []CheckResult{
+		{Description: "Sample check", Skipped: true},
+	}, doReportPreflightChecks(cfg, []Check{*check}))
+	assert.False(t, calls.checked)
+}
+
 func sampleCheck(checkErr, fixErr error) (*Check, *status) {
 	status := &status{}
 	return &Check{
@@ -150,11 +150,11 @@ func getPreflightChecks(experimentalFeatures bool, networkMode network.Mode) []C
 		checks = append(checks, vsockChecks[:]...)
 	}
 
+	// the daemon derives its DNS records from the bundle, it must be extracted first
+	checks = append(checks, bundleCheck)
 	// Experimental feature
 	if experimentalFeatures {
 		checks = append(checks, traySetupChecks[:]...)
 	}
-
-	checks = append(checks, bundleCheck)
 	return checks
 }
@@ -0,0 +1,131 @@
+package profile
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/code-ready/crc/pkg/crc/constants"
+)
+
+// Default is the profile used when none is given. It uses the paths
+// which were in use before profiles were introduced.
+const Default = constants.DefaultName
+
+// BaseDir holds one directory per profile other than the default one
+var BaseDir = filepath.Join(constants.CrcBaseDir, "profiles")
+
+// The profile name is used as VM name, libvirt domain and Hyper-V VM name,
+// and in the kubeconfig context names, so it is kept simple
+var validName = regexp.MustCompile(`^[a-z][a-z0-9-]{0,31}$`)
+
+// Validate checks that name can be used as a profile name
+func Validate(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("Invalid profile name '%s': it must start with a lowercase letter and only contain lowercase letters, digits and '-' (32 characters at most)", name)
+	}
+	return nil
+}
+
+func dir(name string) string {
+	return filepath.Join(BaseDir, name)
+}
+
+// ConfigPath returns the path of the configuration file of the profile
+func ConfigPath(name string) string {
+	if name == Default {
+		return constants.ConfigPath
+	}
+	return filepath.Join(dir(name), constants.ConfigFile)
+}
+
+// DaemonSocketPath returns the path of the API socket of the daemon managing the profile
+func DaemonSocketPath(name string) string {
+	if name == Default {
+		return constants.DaemonSocketPath
+	}
+	return filepath.Join(dir(name), "crc.sock")
+}
+
+// NetworkSocketPath returns the path of the socket used by the VM of the profile in vsock network mode
+func NetworkSocketPath(name string) string {
+	if name == Default {
+		return constants.NetworkSocketPath
+	}
+	return filepath.Join(dir(name), "network.sock")
+}
+
+// DaemonBundlePath returns the path of the file recording the bundle the
+// DNS records of the daemon managing the profile are derived from
+func DaemonBundlePath(name string) string {
+	if name == Default {
+		return constants.DaemonBundlePath
+	}
+	return filepath.Join(dir(name), "daemon-bundle")
+}
+
+// Exists returns true when the profile was created, the default profile always exists
+func Exists(name string) bool {
+	if name == Default {
+		return true
+	}
+	info, err := os.Stat(dir(name))
+	return err == nil && info.IsDir()
+}
+
+// CheckExists returns an error when name is not a valid profile name or
+// when the profile was not created yet
+func CheckExists(name string) error {
+	if err := Validate(name); err != nil {
+		return err
+	}
+	if !Exists(name) {
+		return fmt.Errorf("Profile '%s' does not exist, create it with 'crc setup --profile %s'", name, name)
+	}
+	return nil
+}
+
+// EnsureExists creates the directory of the profile if needed
+func EnsureExists(name string) error {
+	if err := Validate(name); err != nil {
+		return err
+	}
+	if name == Default {
+		return nil
+	}
+	return os.MkdirAll(dir(name), 0750)
+}
+
+// List returns the names of all the known profiles, starting with the default one
+func List() ([]string, error) {
+	entries, err := ioutil.ReadDir(BaseDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != Default && Validate(entry.Name()) == nil {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return append([]string{Default}, names...), nil
+}
+
+// Remove deletes the configuration of the profile. The VM of the profile
+// must have been deleted first.
+func Remove(name string) error {
+	if err := Validate(name); err != nil {
+		return err
+	}
+	if name == Default {
+		return fmt.Errorf("The default profile '%s' cannot be removed", Default)
+	}
+	if _, err := os.Stat(dir(name)); os.IsNotExist(err) {
+		return fmt.Errorf("Profile '%s' does not exist", name)
+	}
+	return os.RemoveAll(dir(name))
+}
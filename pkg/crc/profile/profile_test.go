@@ -0,0 +1,61 @@
+package profile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/code-ready/crc/pkg/crc/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate("crc"))
+	assert.NoError(t, Validate("okd-4-6"))
+	assert.Error(t, Validate(""))
+	assert.Error(t, Validate("4okd"))
+	assert.Error(t, Validate("OKD"))
+	assert.Error(t, Validate("../crc"))
+}
+
+func TestPaths(t *testing.T) {
+	assert.Equal(t, constants.ConfigPath, ConfigPath(Default))
+	assert.Equal(t, constants.DaemonSocketPath, DaemonSocketPath(Default))
+	assert.Equal(t, constants.NetworkSocketPath, NetworkSocketPath(Default))
+	assert.Equal(t, filepath.Join(BaseDir, "okd", constants.ConfigFile), ConfigPath("okd"))
+	assert.Equal(t, filepath.Join(BaseDir, "okd", "crc.sock"), DaemonSocketPath("okd"))
+}
+
+func TestListAndRemove(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profiles")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer func(orig string) { BaseDir = orig }(BaseDir)
+	BaseDir = filepath.Join(dir, "profiles")
+
+	profiles, err := List()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{Default}, profiles)
+
+	assert.True(t, Exists(Default))
+	assert.NoError(t, CheckExists(Default))
+	assert.False(t, Exists("okd"))
+	assert.EqualError(t, CheckExists("okd"), "Profile 'okd' does not exist, create it with 'crc setup --profile okd'")
+	assert.NoError(t, EnsureExists("okd"))
+	assert.True(t, Exists("okd"))
+	assert.NoError(t, CheckExists("okd"))
+	assert.NoError(t, EnsureExists("clean"))
+	assert.Error(t, EnsureExists("Not Valid"))
+	profiles, err = List()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{Default, "clean", "okd"}, profiles)
+
+	assert.NoError(t, Remove("okd"))
+	assert.EqualError(t, Remove("okd"), "Profile 'okd' does not exist")
+	assert.Error(t, Remove(Default))
+	profiles, err = List()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{Default, "clean"}, profiles)
+}
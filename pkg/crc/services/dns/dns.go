@@ -1,11 +1,11 @@
 package dns
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/code-ready/crc/pkg/crc/adminhelper"
-	"github.com/code-ready/crc/pkg/crc/constants"
 	"github.com/code-ready/crc/pkg/crc/errors"
 	"github.com/code-ready/crc/pkg/crc/network"
 	"github.com/code-ready/crc/pkg/crc/services"
@@ -22,12 +22,12 @@ const (
 func init() {
 }
 
-func RunPostStart(serviceConfig services.ServicePostStartConfig) error {
+func RunPostStart(ctx context.Context, serviceConfig services.ServicePostStartConfig) error {
 	if err := setupDnsmasq(serviceConfig); err != nil {
 		return err
 	}
 
-	if err := runPostStartForOS(serviceConfig); err != nil {
+	if err := runPostStartForOS(ctx, serviceConfig); err != nil {
 		return err
 	}
 
@@ -84,7 +84,7 @@ func dnsServers(serviceConfig services.ServicePostStartConfig) ([]network.NameSe
 	if serviceConfig.NetworkMode == network.VSockMode {
 		return []network.NameServer{
 			{
-				IPAddress: constants.VSockGateway,
+				IPAddress: serviceConfig.GatewayIP,
 			},
 		}, nil
 	}
@@ -95,7 +95,7 @@ func dnsServers(serviceConfig services.ServicePostStartConfig) ([]network.NameSe
 	return append([]network.NameServer{{IPAddress: dnsContainerIP}}, orgResolvValues.NameServers...), nil
 }
 
-func CheckCRCLocalDNSReachable(serviceConfig services.ServicePostStartConfig) (string, error) {
+func CheckCRCLocalDNSReachable(ctx context.Context, serviceConfig services.ServicePostStartConfig) (string, error) {
 	appsURI := fmt.Sprintf("foo.%s", serviceConfig.BundleMetadata.ClusterInfo.AppsDomain)
 	// Try 30 times for 1 second interval, In nested environment most of time crc failed to get
 	// Internal dns query resolved for some time.
@@ -109,7 +109,7 @@ func CheckCRCLocalDNSReachable(serviceConfig services.ServicePostStartConfig) (s
 		return nil
 	}
 
-	if err := errors.RetryAfter(30*time.Second, checkLocalDNSReach, time.Second); err != nil {
+	if err := errors.RetryAfter(ctx, 30*time.Second, checkLocalDNSReach, time.Second); err != nil {
 		return queryOutput, err
 	}
 	return queryOutput, err
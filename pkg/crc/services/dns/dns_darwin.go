@@ -2,6 +2,7 @@ package dns
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -30,7 +31,7 @@ type resolverFileValues struct {
 	SearchOrder int
 }
 
-func runPostStartForOS(serviceConfig services.ServicePostStartConfig) error {
+func runPostStartForOS(ctx context.Context, serviceConfig services.ServicePostStartConfig) error {
 	// Update /etc/hosts file for host
 	if err := addOpenShiftHosts(serviceConfig); err != nil {
 		return err
@@ -55,7 +56,7 @@ func runPostStartForOS(serviceConfig services.ServicePostStartConfig) error {
 		// Wait for the Network to come up but in the case of error, log it to error info.
 		// If we make it as fatal call then in offline use case for mac is
 		// always going to be broken.
-		if err := waitForNetwork(); err != nil {
+		if err := waitForNetwork(ctx); err != nil {
 			logging.Error(err)
 		}
 	} else {
@@ -111,7 +112,7 @@ func restartNetwork() error {
 }
 
 // Wait for Network wait till the network is up, since it is required to resolve external dnsquery
-func waitForNetwork() error {
+func waitForNetwork(ctx context.Context) error {
 	var hostResolv *network.ResolvFileValues
 	var err error
 
@@ -124,7 +125,7 @@ func waitForNetwork() error {
 		return nil
 	}
 
-	if err := crcerrors.RetryAfter(10*time.Second, getResolvValueFromHost, time.Second); err != nil {
+	if err := crcerrors.RetryAfter(ctx, 10*time.Second, getResolvValueFromHost, time.Second); err != nil {
 		return fmt.Errorf("Unable to read host resolv file (%v)", err)
 	}
 	// retry up to 5 times
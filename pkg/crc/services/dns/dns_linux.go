@@ -1,10 +1,11 @@
 package dns
 
 import (
+	"context"
 	"github.com/code-ready/crc/pkg/crc/services"
 )
 
-func runPostStartForOS(serviceConfig services.ServicePostStartConfig) error {
+func runPostStartForOS(_ context.Context, serviceConfig services.ServicePostStartConfig) error {
 	// We might need to set the firewall here to forward
 	// Update /etc/hosts file for host
 	return addOpenShiftHosts(serviceConfig)
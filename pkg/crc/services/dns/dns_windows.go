@@ -2,6 +2,7 @@ package dns
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -18,7 +19,7 @@ const (
 	AlternativeNetwork = "crc"
 )
 
-func runPostStartForOS(serviceConfig services.ServicePostStartConfig) error {
+func runPostStartForOS(_ context.Context, serviceConfig services.ServicePostStartConfig) error {
 	if serviceConfig.NetworkMode == network.VSockMode {
 		return addOpenShiftHosts(serviceConfig)
 	}
@@ -12,4 +12,6 @@ type ServicePostStartConfig struct {
 	BundleMetadata bundle.CrcBundleInfo
 	IP             string
 	NetworkMode    network.Mode
+	// Gateway of the virtual network, it is the DNS server of the VM in vsock mode
+	GatewayIP string
 }
@@ -4,17 +4,21 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
+	"os"
 	"strconv"
 	"time"
 
 	log "github.com/code-ready/crc/pkg/crc/logging"
 	"golang.org/x/crypto/ssh"
+	terminal "golang.org/x/term"
 )
 
 type Client interface {
 	Run(command string) ([]byte, []byte, error)
+	Interactive(command string, stdin io.Reader, stdout, stderr io.Writer) (int, error)
 	Close()
 }
 
@@ -109,6 +113,76 @@ func (client *NativeClient) Run(command string) ([]byte, []byte, error) {
 	return stdout.Bytes(), stderr.Bytes(), err
 }
 
+// Interactive runs command, or a shell when command is empty, with the given
+// streams and returns its exit code. A pseudo terminal is allocated when stdin
+// is a terminal.
+func (client *NativeClient) Interactive(command string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	session, err := client.session()
+	if err != nil {
+		return -1, err
+	}
+	defer session.Close()
+
+	session.Stdin = stdin
+	session.Stdout = stdout
+	session.Stderr = stderr
+
+	if file, ok := stdin.(*os.File); ok && terminal.IsTerminal(int(file.Fd())) {
+		restore, err := requestPty(session, int(file.Fd()))
+		if err != nil {
+			return -1, err
+		}
+		defer restore()
+	}
+
+	if command == "" {
+		if err := session.Shell(); err != nil {
+			return -1, err
+		}
+		err = session.Wait()
+	} else {
+		err = session.Run(command)
+	}
+	var exitErr *ssh.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus(), nil
+	}
+	if err != nil {
+		return -1, err
+	}
+	return 0, nil
+}
+
+func requestPty(session *ssh.Session, fd int) (func(), error) {
+	width, height, err := terminal.GetSize(fd)
+	if err != nil {
+		width, height = 80, 24
+	}
+	termType := os.Getenv("TERM")
+	if termType == "" {
+		termType = "xterm-256color"
+	}
+	modes := ssh.TerminalModes{
+		ssh.ECHO:          1,
+		ssh.TTY_OP_ISPEED: 14400,
+		ssh.TTY_OP_OSPEED: 14400,
+	}
+	if err := session.RequestPty(termType, height, width, modes); err != nil {
+		return nil, fmt.Errorf("Cannot allocate a pseudo terminal: %v", err)
+	}
+	state, err := terminal.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	stopResizes := forwardWindowResizes(session, fd)
+	return func() {
+		stopResizes()
+		if err := terminal.Restore(fd, state); err != nil {
+			log.Debugf("Cannot restore the terminal: %v", err)
+		}
+	}, nil
+}
+
 func (client *NativeClient) Close() {
 	if client.conn == nil {
 		return
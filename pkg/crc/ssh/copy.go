@@ -0,0 +1,271 @@
+package ssh
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/code-ready/crc/pkg/crc/logging"
+	"github.com/code-ready/crc/pkg/os/shell"
+)
+
+// CopyToVM copies the file or directory src of the host to dest in the VM,
+// recursively and keeping the permissions. As with cp, src is copied inside
+// dest when dest is an existing directory. The copied files are owned by root.
+func (runner *Runner) CopyToVM(src, dest string, showProgress bool) error {
+	size, err := diskUsage(src)
+	if err != nil {
+		return err
+	}
+
+	targetDir, name := dest, filepath.Base(src)
+	if _, _, err := runner.Run(fmt.Sprintf("sudo test -d %s", shell.Quote(dest))); err != nil {
+		targetDir, name = path.Dir(dest), path.Base(dest)
+	}
+	logging.Debugf("Copying %s to %s in the CRC VM", src, path.Join(targetDir, name))
+
+	reader, writer := io.Pipe()
+	var output io.Writer = writer
+	if showProgress {
+		bar := progressBar(size, src)
+		defer bar.Finish()
+		output = bar.NewProxyWriter(writer)
+	}
+	written := make(chan error, 1)
+	go func() {
+		err := writeTar(output, src, name)
+		_ = writer.CloseWithError(err)
+		written <- err
+	}()
+
+	var stderr bytes.Buffer
+	command := fmt.Sprintf("sudo tar -x -p --no-same-owner -C %s", shell.Quote(targetDir))
+	exitCode, err := runner.client.Interactive(command, reader, ioutil.Discard, &stderr)
+	// unblocks writeTar if tar exited before reading the whole archive
+	_ = reader.Close()
+	writeErr := <-written
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("Cannot copy %s to the VM: %s", src, strings.TrimSpace(stderr.String()))
+	}
+	// tar may accept an archive truncated at a header boundary
+	if writeErr != nil && writeErr != io.ErrClosedPipe {
+		return fmt.Errorf("Cannot copy %s to the VM: %v", src, writeErr)
+	}
+	return nil
+}
+
+// CopyFromVM copies the file or directory src of the VM to dest on the host,
+// recursively and keeping the permissions. As with cp, src is copied inside
+// dest when dest is an existing directory.
+func (runner *Runner) CopyFromVM(src, dest string, showProgress bool) error {
+	targetDir, name := dest, ""
+	if info, err := os.Stat(dest); err != nil || !info.IsDir() {
+		targetDir, name = filepath.Dir(dest), filepath.Base(dest)
+	}
+	logging.Debugf("Copying %s from the CRC VM to %s", src, dest)
+
+	reader, writer := io.Pipe()
+	var input io.Reader = reader
+	if showProgress {
+		// the size is only used to display the progress
+		stdout, _, err := runner.Run(fmt.Sprintf("sudo du -sb %s", shell.Quote(src)))
+		if err != nil {
+			return fmt.Errorf("Cannot find %s in the VM", src)
+		}
+		var size int64
+		if fields := strings.Fields(stdout); len(fields) > 0 {
+			size, _ = strconv.ParseInt(fields[0], 10, 64)
+		}
+		bar := progressBar(size, src)
+		defer bar.Finish()
+		input = bar.NewProxyReader(reader)
+	}
+	extracted := make(chan error, 1)
+	go func() {
+		err := readTar(input, targetDir, name)
+		if err == nil {
+			// consume the padding at the end of the archive
+			_, err = io.Copy(ioutil.Discard, reader)
+		}
+		_ = reader.CloseWithError(err)
+		extracted <- err
+	}()
+
+	var stderr bytes.Buffer
+	command := fmt.Sprintf("sudo tar -c -C %s %s", shell.Quote(path.Dir(src)), shell.Quote(path.Base(src)))
+	exitCode, err := runner.client.Interactive(command, nil, writer, &stderr)
+	_ = writer.Close()
+	if extractErr := <-extracted; extractErr != nil {
+		return extractErr
+	}
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("Cannot copy %s from the VM: %s", src, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// writeTar archives the file or directory src, its content is stored under name
+func writeTar(writer io.Writer, src, name string) error {
+	tarWriter := tar.NewWriter(writer)
+	err := filepath.Walk(src, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(name, filepath.ToSlash(relPath))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		return copyFileTo(tarWriter, file)
+	})
+	if err != nil {
+		return err
+	}
+	return tarWriter.Close()
+}
+
+func copyFileTo(writer io.Writer, file string) error {
+	f, err := os.Open(filepath.Clean(file))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(writer, f)
+	return err
+}
+
+// readTar extracts the archive in targetDir. When name is not empty, the
+// top-level entry of the archive is renamed to name.
+func readTar(reader io.Reader, targetDir, name string) error {
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		entry := header.Name
+		if name != "" {
+			parts := strings.SplitN(entry, "/", 2)
+			entry = name
+			if len(parts) == 2 {
+				entry = path.Join(name, parts[1])
+			}
+		}
+		target := filepath.Join(targetDir, filepath.FromSlash(entry)) // #nosec G305
+		if target != filepath.Clean(targetDir) && !strings.HasPrefix(target, filepath.Clean(targetDir)+string(os.PathSeparator)) {
+			return fmt.Errorf("%s: illegal file path", header.Name)
+		}
+
+		mode := header.FileInfo().Mode().Perm()
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0750); err != nil {
+				return err
+			}
+			if err := os.Chmod(target, mode); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := extractFile(tarReader, target, mode); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			// later entries could otherwise be written through the link, outside of targetDir
+			if !isLocalLink(header.Linkname) {
+				return fmt.Errorf("%s: illegal symbolic link to %s", header.Name, header.Linkname)
+			}
+			_ = os.Remove(target)
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		default:
+			logging.Debugf("Skipping %s, it is not a regular file, a directory or a symbolic link", header.Name)
+		}
+	}
+}
+
+// isLocalLink returns true when the target of a symbolic link is relative
+// and does not go up, a link inside the archive then stays inside of it
+func isLocalLink(link string) bool {
+	if filepath.IsAbs(link) || filepath.VolumeName(link) != "" || strings.HasPrefix(filepath.ToSlash(link), "/") {
+		return false
+	}
+	for _, part := range strings.Split(filepath.ToSlash(link), "/") {
+		if part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+func extractFile(reader io.Reader, target string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	// #nosec G110
+	if _, err := io.Copy(file, reader); err != nil {
+		return err
+	}
+	// the mode given to OpenFile is restricted by the umask
+	return file.Chmod(mode)
+}
+
+func diskUsage(src string) (int64, error) {
+	var size int64
+	err := filepath.Walk(src, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+func progressBar(size int64, name string) *pb.ProgressBar {
+	bar := pb.Simple.Start64(size)
+	bar.Set("prefix", fmt.Sprintf("%s: ", path.Base(filepath.ToSlash(name))))
+	return bar
+}
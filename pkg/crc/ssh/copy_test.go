@@ -0,0 +1,128 @@
+package ssh
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTarRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "copy")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "fixtures")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "certs"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "certs", "ca.pem"), []byte("ca"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(src, "run.sh"), []byte("#!/bin/sh"), 0755))
+
+	archive := new(bytes.Buffer)
+	require.NoError(t, writeTar(archive, src, "fixtures"))
+
+	// extracted as is in an existing directory
+	target := filepath.Join(dir, "target")
+	require.NoError(t, os.Mkdir(target, 0755))
+	require.NoError(t, readTar(bytes.NewReader(archive.Bytes()), target, ""))
+	content, err := ioutil.ReadFile(filepath.Join(target, "fixtures", "certs", "ca.pem"))
+	assert.NoError(t, err)
+	assert.Equal(t, "ca", string(content))
+
+	// extracted under a new name
+	require.NoError(t, readTar(bytes.NewReader(archive.Bytes()), dir, "renamed"))
+	content, err = ioutil.ReadFile(filepath.Join(dir, "renamed", "run.sh"))
+	assert.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh", string(content))
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filepath.Join(dir, "renamed", "run.sh"))
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+		info, err = os.Stat(filepath.Join(dir, "renamed", "certs", "ca.pem"))
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+}
+
+func TestReadTarRejectsPathsOutsideOfTarget(t *testing.T) {
+	dir, err := ioutil.TempDir("", "copy")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file"), []byte("content"), 0600))
+	archive := new(bytes.Buffer)
+	require.NoError(t, writeTar(archive, filepath.Join(dir, "file"), "../escaped"))
+
+	assert.Error(t, readTar(archive, filepath.Join(dir, "target"), ""))
+}
+
+func TestReadTarRejectsSymlinksOutsideOfTarget(t *testing.T) {
+	dir, err := ioutil.TempDir("", "copy")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, link := range []string{"/etc", "../..", "sub/../../etc"} {
+		archive := new(bytes.Buffer)
+		tarWriter := tar.NewWriter(archive)
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "x", Typeflag: tar.TypeSymlink, Linkname: link, Mode: 0777}))
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "x/passwd", Typeflag: tar.TypeReg, Mode: 0600}))
+		require.NoError(t, tarWriter.Close())
+
+		assert.Error(t, readTar(archive, dir, ""), link)
+		_, err := os.Lstat(filepath.Join(dir, "x"))
+		assert.True(t, os.IsNotExist(err))
+	}
+}
+
+func TestIsLocalLink(t *testing.T) {
+	assert.True(t, isLocalLink("run.sh"))
+	assert.True(t, isLocalLink("certs/ca.pem"))
+	assert.True(t, isLocalLink("./..certs"))
+	assert.False(t, isLocalLink("/etc"))
+	assert.False(t, isLocalLink(".."))
+	assert.False(t, isLocalLink("certs/../../etc"))
+}
+
+type copyTestClient struct {
+	received *bytes.Buffer
+}
+
+func (client *copyTestClient) Run(command string) ([]byte, []byte, error) {
+	return nil, nil, fmt.Errorf("%s: exit status 1", command)
+}
+
+func (client *copyTestClient) Interactive(command string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	_, err := io.Copy(client.received, stdin)
+	return 0, err
+}
+
+func (client *copyTestClient) Close() {}
+
+func TestCopyToVMReportsArchiveErrors(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets cannot be created in the temporary directory")
+	}
+	dir, err := ioutil.TempDir("", "copy")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file"), []byte("content"), 0600))
+	// sockets cannot be archived, the archive is truncated after file
+	listener, err := net.Listen("unix", filepath.Join(dir, "socket"))
+	require.NoError(t, err)
+	defer listener.Close()
+
+	client := &copyTestClient{received: new(bytes.Buffer)}
+	runner := &Runner{client: client}
+	assert.Error(t, runner.CopyToVM(dir, "/home/core/dir", false))
+	assert.NotZero(t, client.received.Len())
+}
@@ -3,6 +3,7 @@ package ssh
 import (
 	"encoding/base64"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"strings"
@@ -48,6 +49,13 @@ func (runner *Runner) RunPrivileged(reason string, cmdAndArgs ...string) (string
 	return runner.runSSHCommand(commandline, false)
 }
 
+// RunInteractive runs cmd, or a shell when cmd is empty, with the given
+// streams and returns the exit code of the command
+func (runner *Runner) RunInteractive(cmd string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	logging.Debugf("About to run interactive SSH command: %s", cmd)
+	return runner.client.Interactive(cmd, stdin, stdout, stderr)
+}
+
 func (runner *Runner) CopyData(data []byte, destFilename string, mode os.FileMode) error {
 	logging.Debugf("Creating %s with permissions 0%o in the CRC VM", destFilename, mode)
 	base64Data := base64.StdEncoding.EncodeToString(data)
@@ -169,3 +169,37 @@ func TestGenerateSSHKey(t *testing.T) {
 	// cleanup
 	_ = os.RemoveAll(tmpDir)
 }
+
+func TestRunInteractive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ssh")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+
+	clientKeyFile := filepath.Join(dir, "private.key")
+	writePrivateKey(t, clientKeyFile, clientKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	createSSHServer(t, listener, clientKey, func(input string) (byte, string) {
+		if input == "cat /etc/os-release; exit 3" {
+			return 3, "Red Hat Enterprise Linux CoreOS"
+		}
+		return 1, fmt.Sprintf("unexpected command: %q", input)
+	})
+
+	addr := listener.Addr().String()
+	runner, err := CreateRunner(ipFor(addr), portFor(addr), clientKeyFile)
+	assert.NoError(t, err)
+	defer runner.Close()
+
+	stdout := new(bytes.Buffer)
+	exitCode, err := runner.RunInteractive("cat /etc/os-release; exit 3", strings.NewReader(""), stdout, ioutil.Discard)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, exitCode)
+	assert.Equal(t, "Red Hat Enterprise Linux CoreOS", stdout.String())
+}
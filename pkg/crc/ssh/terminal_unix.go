@@ -0,0 +1,41 @@
+// +build !windows
+
+package ssh
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	log "github.com/code-ready/crc/pkg/crc/logging"
+	"golang.org/x/crypto/ssh"
+	terminal "golang.org/x/term"
+)
+
+// forwardWindowResizes changes the size of the pseudo terminal of session
+// when the local terminal is resized. The returned function stops it.
+func forwardWindowResizes(session *ssh.Session, fd int) func() {
+	resized := make(chan os.Signal, 1)
+	signal.Notify(resized, syscall.SIGWINCH)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-resized:
+				width, height, err := terminal.GetSize(fd)
+				if err != nil {
+					continue
+				}
+				if err := session.WindowChange(height, width); err != nil {
+					log.Debugf("Cannot resize the pseudo terminal: %v", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(resized)
+		close(done)
+	}
+}
@@ -0,0 +1,41 @@
+package ssh
+
+import (
+	"time"
+
+	log "github.com/code-ready/crc/pkg/crc/logging"
+	"golang.org/x/crypto/ssh"
+	terminal "golang.org/x/term"
+)
+
+// the console is not notified of its resizes, its size is polled instead
+const windowSizePollInterval = 250 * time.Millisecond
+
+// forwardWindowResizes changes the size of the pseudo terminal of session
+// when the local terminal is resized. The returned function stops it.
+func forwardWindowResizes(session *ssh.Session, fd int) func() {
+	done := make(chan struct{})
+	go func() {
+		width, height, _ := terminal.GetSize(fd)
+		ticker := time.NewTicker(windowSizePollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				newWidth, newHeight, err := terminal.GetSize(fd)
+				if err != nil || (newWidth == width && newHeight == height) {
+					continue
+				}
+				width, height = newWidth, newHeight
+				if err := session.WindowChange(height, width); err != nil {
+					log.Debugf("Cannot resize the pseudo terminal: %v", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+	}
+}
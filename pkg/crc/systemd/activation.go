@@ -0,0 +1,49 @@
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// listenFdsStart is the first file descriptor passed by systemd, see sd_listen_fds(3)
+const listenFdsStart = 3
+
+// ActivationListeners returns the sockets passed by systemd when the process
+// is socket activated, indexed by the FileDescriptorName of their unit. The
+// map is empty when the process is not socket activated.
+func ActivationListeners() (map[string]net.Listener, error) {
+	listeners := make(map[string]net.Listener)
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return listeners, nil
+	}
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid LISTEN_FDS value: %v", err)
+	}
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	// the sockets must not be inherited by the processes started by crc
+	for _, env := range []string{"LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES"} {
+		_ = os.Unsetenv(env)
+	}
+
+	for i := 0; i < count; i++ {
+		fd := listenFdsStart + i
+		name := fmt.Sprintf("LISTEN_FD_%d", fd)
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+		file := os.NewFile(uintptr(fd), name)
+		// FileListener duplicates the file descriptor
+		listener, err := net.FileListener(file)
+		_ = file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Cannot use the socket %s passed by systemd: %v", name, err)
+		}
+		listeners[name] = listener
+	}
+	return listeners, nil
+}
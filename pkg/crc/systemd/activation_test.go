@@ -0,0 +1,41 @@
+package systemd
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivationListenersNotActivated(t *testing.T) {
+	os.Unsetenv("LISTEN_PID")
+	listeners, err := ActivationListeners()
+	assert.NoError(t, err)
+	assert.Empty(t, listeners)
+}
+
+func TestActivationListenersOtherProcess(t *testing.T) {
+	// the sockets were passed to the parent process
+	os.Setenv("LISTEN_PID", strconv.Itoa(os.Getppid()))
+	os.Setenv("LISTEN_FDS", "2")
+	defer os.Unsetenv("LISTEN_PID")
+	defer os.Unsetenv("LISTEN_FDS")
+
+	listeners, err := ActivationListeners()
+	assert.NoError(t, err)
+	assert.Empty(t, listeners)
+}
+
+func TestActivationListenersWithoutSockets(t *testing.T) {
+	os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	os.Setenv("LISTEN_FDS", "0")
+	os.Setenv("LISTEN_FDNAMES", "")
+
+	listeners, err := ActivationListeners()
+	require.NoError(t, err)
+	assert.Empty(t, listeners)
+	_, set := os.LookupEnv("LISTEN_PID")
+	assert.False(t, set)
+}
@@ -11,6 +11,9 @@ import (
 
 type Commander struct {
 	commandRunner crcos.CommandRunner
+	// userMode is true when the commander manages the units of the
+	// systemd instance of the current user, with systemctl --user
+	userMode bool
 }
 
 func NewInstanceSystemdCommander(sshRunner *ssh.Runner) *Commander {
@@ -19,6 +22,15 @@ func NewInstanceSystemdCommander(sshRunner *ssh.Runner) *Commander {
 	}
 }
 
+// User returns a commander managing the units of the systemd instance of the
+// current user. These commands don't need to be privileged.
+func (c Commander) User() *Commander {
+	return &Commander{
+		commandRunner: c.commandRunner,
+		userMode:      true,
+	}
+}
+
 func (c Commander) Enable(name string) error {
 	_, err := c.service(name, actions.Enable)
 	return err
@@ -58,7 +70,15 @@ func (c Commander) Status(name string) (states.State, error) {
 }
 
 func (c Commander) DaemonReload() error {
-	stdOut, stdErr, err := c.commandRunner.RunPrivileged("Executing systemctl daemon-reload command", "systemctl", "daemon-reload")
+	var (
+		stdOut, stdErr string
+		err            error
+	)
+	if c.userMode {
+		stdOut, stdErr, err = c.commandRunner.Run("systemctl", "--user", "daemon-reload")
+	} else {
+		stdOut, stdErr, err = c.commandRunner.RunPrivileged("Executing systemctl daemon-reload command", "systemctl", "daemon-reload")
+	}
 	if err != nil {
 		return fmt.Errorf("Executing systemctl daemon-reload failed: %s %v: %s", stdOut, err, stdErr)
 	}
@@ -70,10 +90,13 @@ func (c Commander) service(name string, action actions.Action) (states.State, er
 		stdOut, stdErr string
 		err            error
 	)
-	if action.IsPriviledged() {
+	switch {
+	case c.userMode:
+		stdOut, stdErr, err = c.commandRunner.Run("systemctl", "--user", action.String(), name)
+	case action.IsPriviledged():
 		msg := fmt.Sprintf("Executing systemctl %s %s", action.String(), name)
 		stdOut, stdErr, err = c.commandRunner.RunPrivileged(msg, "systemctl", action.String(), name)
-	} else {
+	default:
 		stdOut, stdErr, err = c.commandRunner.Run("systemctl", action.String(), name)
 	}
 
@@ -55,6 +55,37 @@ func TestSystemdStatuses(t *testing.T) {
 	assert.Equal(t, states.NotFound.String(), status.String())
 }
 
+func TestSystemdUser(t *testing.T) {
+	runner := &recordingRunner{}
+	systemctl := (&Commander{commandRunner: runner}).User()
+
+	assert.NoError(t, systemctl.Enable("crc-api.socket"))
+	assert.NoError(t, systemctl.Start("crc-api.socket"))
+	assert.Equal(t, [][]string{
+		{"systemctl", "--user", "enable", "crc-api.socket"},
+		{"systemctl", "--user", "daemon-reload"},
+		{"systemctl", "--user", "start", "crc-api.socket"},
+	}, runner.commands)
+}
+
+// recordingRunner records the commands it runs, they must not be privileged
+type recordingRunner struct {
+	commands [][]string
+}
+
+func (r *recordingRunner) Run(command string, args ...string) (string, string, error) {
+	r.commands = append(r.commands, append([]string{command}, args...))
+	return "", "", nil
+}
+
+func (r *recordingRunner) RunPrivate(command string, args ...string) (string, string, error) {
+	return "", "", fmt.Errorf("Unexpected RunPrivate() call")
+}
+
+func (r *recordingRunner) RunPrivileged(reason string, cmdAndArgs ...string) (string, string, error) {
+	return "", "", fmt.Errorf("Unexpected RunPrivileged() call")
+}
+
 type mockSystemdRunner struct {
 	test    *testing.T
 	failing bool
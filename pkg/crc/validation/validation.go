@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"time"
 
 	"github.com/code-ready/crc/pkg/crc/constants"
 	"github.com/code-ready/crc/pkg/crc/logging"
@@ -44,6 +45,26 @@ func ValidateDiskSize(value int) error {
 	return nil
 }
 
+// ValidateWaitCondition checks if the condition to wait for at the end of start is known
+func ValidateWaitCondition(value string) error {
+	if value != constants.WaitNone && value != constants.WaitOperators {
+		return fmt.Errorf("wait condition should be either %s or %s", constants.WaitOperators, constants.WaitNone)
+	}
+	return nil
+}
+
+// ValidateTimeout checks if the provided timeout is a valid positive duration
+func ValidateTimeout(value string) error {
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("requires a duration such as 10m or 90s")
+	}
+	if timeout <= 0 {
+		return fmt.Errorf("requires a positive duration")
+	}
+	return nil
+}
+
 // ValidateEnoughMemory checks if enough memory is installed on the host
 func ValidateEnoughMemory(value int) error {
 	totalMemory := memory.TotalMemory()
@@ -100,6 +100,8 @@ func (d *Driver) GetState() (state.State, error) {
 		return state.Running, nil
 	case "Off":
 		return state.Stopped, nil
+	case "Saved":
+		return state.Saved, nil
 	default:
 		return state.None, nil
 	}
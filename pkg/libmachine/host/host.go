@@ -1,6 +1,7 @@
 package host
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/rpc"
@@ -18,6 +19,8 @@ import (
 // therefore migration, introduced to the config file format.
 const Version = 3
 
+const defaultStateTimeout = 3 * time.Minute
+
 type Host struct {
 	ConfigVersion int
 	Driver        drivers.Driver
@@ -31,7 +34,7 @@ type Metadata struct {
 	ConfigVersion int
 }
 
-func (h *Host) runActionForState(action func() error, desiredState state.State) error {
+func (h *Host) runActionForState(ctx context.Context, action func() error, desiredState state.State) error {
 	if err := MachineInState(h.Driver, desiredState)(); err == nil {
 		return fmt.Errorf("machine is already %s", strings.ToLower(desiredState.String()))
 	}
@@ -40,12 +43,21 @@ func (h *Host) runActionForState(action func() error, desiredState state.State)
 		return err
 	}
 
-	return crcerrors.RetryAfter(3*time.Minute, MachineInState(h.Driver, desiredState), 3*time.Second)
+	return crcerrors.RetryAfter(ctx, stateTimeout(ctx), MachineInState(h.Driver, desiredState), 3*time.Second)
+}
+
+// stateTimeout returns how long to wait for the machine to reach the desired
+// state: until the deadline of ctx when it has one, 3 minutes otherwise
+func stateTimeout(ctx context.Context) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline)
+	}
+	return defaultStateTimeout
 }
 
-func (h *Host) Start() error {
+func (h *Host) Start(ctx context.Context) error {
 	log.Debugf("Starting %q...", h.Name)
-	if err := h.runActionForState(h.Driver.Start, state.Running); err != nil {
+	if err := h.runActionForState(ctx, h.Driver.Start, state.Running); err != nil {
 		return err
 	}
 
@@ -54,9 +66,9 @@ func (h *Host) Start() error {
 	return nil
 }
 
-func (h *Host) Stop() error {
+func (h *Host) Stop(ctx context.Context) error {
 	log.Debugf("Stopping %q...", h.Name)
-	if err := h.runActionForState(h.Driver.Stop, state.Stopped); err != nil {
+	if err := h.runActionForState(ctx, h.Driver.Stop, state.Stopped); err != nil {
 		return err
 	}
 
@@ -64,9 +76,9 @@ func (h *Host) Stop() error {
 	return nil
 }
 
-func (h *Host) Kill() error {
+func (h *Host) Kill(ctx context.Context) error {
 	log.Debugf("Killing %q...", h.Name)
-	if err := h.runActionForState(h.Driver.Kill, state.Stopped); err != nil {
+	if err := h.runActionForState(ctx, h.Driver.Kill, state.Stopped); err != nil {
 		return err
 	}
 
@@ -97,7 +109,7 @@ func MachineInState(d drivers.Driver, desiredState state.State) func() error {
 		if currentState == desiredState {
 			return nil
 		}
-		return crcerrors.RetriableError{
+		return &crcerrors.RetriableError{
 			Err: fmt.Errorf("expected machine state %s, got %s", desiredState.String(), currentState.String()),
 		}
 	}
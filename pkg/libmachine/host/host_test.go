@@ -0,0 +1,18 @@
+package host
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateTimeout(t *testing.T) {
+	assert.Equal(t, defaultStateTimeout, stateTimeout(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	timeout := stateTimeout(ctx)
+	assert.True(t, timeout > 9*time.Minute && timeout <= 10*time.Minute, "unexpected timeout %s", timeout)
+}
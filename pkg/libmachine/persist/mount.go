@@ -0,0 +1,43 @@
+package persist
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Mount records a directory of the host shared with a machine
+type Mount struct {
+	HostDir string
+	VMDir   string
+	// Tag identifies the shared directory when mounting it in the VM
+	Tag string
+}
+
+func (s Filestore) mountsPath(name string) string {
+	return filepath.Join(s.MachinesDir, name, "mounts.json")
+}
+
+func (s Filestore) LoadMounts(name string) ([]Mount, error) {
+	data, err := ioutil.ReadFile(s.mountsPath(name))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var mounts []Mount
+	if err := json.Unmarshal(data, &mounts); err != nil {
+		return nil, err
+	}
+	return mounts, nil
+}
+
+func (s Filestore) SaveMounts(name string, mounts []Mount) error {
+	data, err := json.Marshal(mounts)
+	if err != nil {
+		return err
+	}
+	return s.saveToFile(data, s.mountsPath(name))
+}
@@ -0,0 +1,38 @@
+package persist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMounts(t *testing.T) {
+	store, cleanup, err := getTestStore()
+	assert.NoError(t, err)
+	defer cleanup()
+
+	h := testHost()
+	assert.NoError(t, store.Save(h))
+
+	mounts, err := store.LoadMounts(h.Name)
+	assert.NoError(t, err)
+	assert.Empty(t, mounts)
+
+	expected := []Mount{
+		{
+			HostDir: "/home/user/src",
+			VMDir:   "/mnt/src",
+			Tag:     "crc-0123456789ab",
+		},
+	}
+	assert.NoError(t, store.SaveMounts(h.Name, expected))
+	mounts, err = store.LoadMounts(h.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, mounts)
+
+	assert.NoError(t, store.Remove(h.Name))
+	_, err = os.Stat(filepath.Join(store.MachinesDir, h.Name, "mounts.json"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,45 @@
+package persist
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Snapshot records a snapshot of the disk of a stopped machine, along with
+// the driver configuration at the time it was taken so that restoring it
+// also restores the bundle name, memory, CPUs and disk size of the machine
+type Snapshot struct {
+	Name      string
+	CreatedAt time.Time
+	RawDriver json.RawMessage
+}
+
+func (s Filestore) snapshotsPath(name string) string {
+	return filepath.Join(s.MachinesDir, name, "snapshots.json")
+}
+
+func (s Filestore) LoadSnapshots(name string) ([]Snapshot, error) {
+	data, err := ioutil.ReadFile(s.snapshotsPath(name))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []Snapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+func (s Filestore) SaveSnapshots(name string, snapshots []Snapshot) error {
+	data, err := json.Marshal(snapshots)
+	if err != nil {
+		return err
+	}
+	return s.saveToFile(data, s.snapshotsPath(name))
+}
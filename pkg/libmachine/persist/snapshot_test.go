@@ -0,0 +1,45 @@
+package persist
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshots(t *testing.T) {
+	store, cleanup, err := getTestStore()
+	assert.NoError(t, err)
+	defer cleanup()
+
+	h := testHost()
+	assert.NoError(t, store.Save(h))
+
+	snapshots, err := store.LoadSnapshots(h.Name)
+	assert.NoError(t, err)
+	assert.Empty(t, snapshots)
+
+	expected := []Snapshot{
+		{
+			Name:      "clean",
+			CreatedAt: time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC),
+			RawDriver: json.RawMessage(`{"BundleName":"crc_libvirt_4.6.6.crcbundle"}`),
+		},
+	}
+	assert.NoError(t, store.SaveSnapshots(h.Name, expected))
+	snapshots, err = store.LoadSnapshots(h.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, snapshots)
+
+	assert.NoError(t, store.SaveSnapshots(h.Name, nil))
+	snapshots, err = store.LoadSnapshots(h.Name)
+	assert.NoError(t, err)
+	assert.Empty(t, snapshots)
+
+	assert.NoError(t, store.Remove(h.Name))
+	_, err = os.Stat(filepath.Join(store.MachinesDir, h.Name, "snapshots.json"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -16,4 +16,16 @@ type Store interface {
 
 	// Save persists a machine in the store
 	Save(host *host.Host) error
+
+	// LoadSnapshots returns the snapshots recorded for a machine
+	LoadSnapshots(name string) ([]Snapshot, error)
+
+	// SaveSnapshots replaces the snapshots recorded for a machine
+	SaveSnapshots(name string, snapshots []Snapshot) error
+
+	// LoadMounts returns the host directories shared with a machine
+	LoadMounts(name string) ([]Mount, error)
+
+	// SaveMounts replaces the host directories shared with a machine
+	SaveMounts(name string, mounts []Mount) error
 }
@@ -78,3 +78,8 @@ func GetPathEnvString(userShell string, prependedPath string) string {
 
 	return GetEnvString(userShell, "PATH", pathStr)
 }
+
+// Quote quotes s so that POSIX shells, such as the one of the VM, read it as a single word
+func Quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
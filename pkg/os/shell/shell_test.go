@@ -28,3 +28,8 @@ func TestDetectFish(t *testing.T) {
 	assert.Equal(t, "fish", shell)
 	assert.NoError(t, err)
 }
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, `'/var/log/it'\''s here'`, Quote("/var/log/it's here"))
+	assert.Equal(t, `''`, Quote(""))
+}